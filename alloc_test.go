@@ -0,0 +1,52 @@
+package gotag
+
+import "testing"
+
+func TestExactMatchAllocs(t *testing.T) {
+	fn := func(t T) {}
+	mock := &mockT{}
+
+	tc := New()
+	tc.Skip("tagA")
+	tc.Fuzzy = true
+	allocs := testing.AllocsPerRun(100, func() {
+		tc.Test("tagA", mock, fn)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations for skip, got %v", allocs)
+	}
+
+	tc = New()
+	tc.RunOnly("tagA")
+	allocs = testing.AllocsPerRun(100, func() {
+		tc.Test("tagA", mock, fn)
+		tc.Test("tagB", mock, fn)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations for run only, got %v", allocs)
+	}
+}
+
+func BenchmarkTestExactSkip(b *testing.B) {
+	tc := New()
+	tc.Skip("tagA")
+	mock := &mockT{}
+	fn := func(t T) {}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tc.Test("tagA", mock, fn)
+	}
+}
+
+func BenchmarkTestExactRun(b *testing.B) {
+	tc := New()
+	tc.Skip("tagA")
+	mock := &mockT{}
+	fn := func(t T) {}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tc.Test("tagB", mock, fn)
+	}
+}
@@ -0,0 +1,23 @@
+// Package autoload configures the default gotag context from a
+// .gotag config file and environment variables when imported, and
+// registers the -gotag.* flags in test binaries.
+// Adding tag support to a test package is a single import:
+//
+//	import _ "github.com/boxtown/gotag/autoload"
+package autoload
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/boxtown/gotag"
+)
+
+func init() {
+	if err := gotag.LoadDefault(); err != nil {
+		panic("gotag: " + err.Error())
+	}
+	if testing.Testing() {
+		gotag.RegisterFlags(flag.CommandLine)
+	}
+}
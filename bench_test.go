@@ -0,0 +1,128 @@
+package gotag
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// decisions are made through decide rather than Test, which would
+// answer every iteration after the first from the decision cache
+func BenchmarkFuzzyLookup(b *testing.B) {
+	for _, size := range []int{16, 256, 4096} {
+		for _, length := range []int{16, 64} {
+			tc := New()
+			tc.Fuzzy = true
+			for i := 0; i < size; i++ {
+				tc.Skip(benchTag(i, length))
+			}
+			tags := []string{strings.Repeat("z", length)}
+			b.Run(fmt.Sprintf("size=%d/length=%d", size, length), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					tc.mu.RLock()
+					tc.decide(tags)
+					tc.mu.RUnlock()
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkFuzzyLookupParallel(b *testing.B) {
+	tc := New()
+	tc.Fuzzy = true
+	for i := 0; i < 256; i++ {
+		tc.Skip(benchTag(i, 16))
+	}
+	tags := []string{"no-match-at-all"}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			tc.mu.RLock()
+			tc.decide(tags)
+			tc.mu.RUnlock()
+		}
+	})
+}
+
+// measures Test when the decision for its tag is cached
+func BenchmarkCachedLookup(b *testing.B) {
+	for _, size := range []int{16, 4096} {
+		tc := New()
+		tc.Fuzzy = true
+		for i := 0; i < size; i++ {
+			tc.Skip(benchTag(i, 16))
+		}
+		tag := strings.Repeat("z", 16)
+		mock := &mockT{}
+		fn := func(t T) {}
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				tc.Test(tag, mock, fn)
+			}
+		})
+	}
+}
+
+func BenchmarkLoadFrom(b *testing.B) {
+	for _, name := range []string{".gotag.json", ".gotag.yml"} {
+		dir := b.TempDir()
+		config := `{"skip": ["integration", "end-to-end"], "fuzzy": true, "distance": 3}`
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(config), 0644)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := LoadFrom(dir); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// generates a unique tag of the given length
+func benchTag(i, length int) string {
+	tag := fmt.Sprintf("tag-%d-", i)
+	return tag + strings.Repeat("x", length-len(tag))
+}
+
+// compares fuzzy lookups through the BK-tree index with a linear scan
+// of every registered tag, the strategy used below fuzzyIndexThreshold
+func BenchmarkFuzzyIndex(b *testing.B) {
+	for _, size := range []int{128, 1024, 8192} {
+		tags := make([]string, size)
+		tree := &bkTree{}
+		for i := range tags {
+			tags[i] = fmt.Sprintf("%s-%s-%d", benchWords[i%len(benchWords)], benchWords[i/len(benchWords)%len(benchWords)], i)
+			tree.insert(tags[i], i)
+		}
+		tag := "integratoin-postgers"
+		b.Run(fmt.Sprintf("size=%d/linear", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, t := range tags {
+					if levenshtein(t, tag) <= 2 {
+						break
+					}
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("size=%d/bktree", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree.search(tag, 2)
+			}
+		})
+	}
+}
+
+var benchWords = []string{
+	"integration", "unit", "slow", "postgres", "mysql", "redis", "kafka", "docker",
+	"network", "e2e", "browser", "api", "grpc", "auth", "billing", "search",
+}
@@ -0,0 +1,110 @@
+package gotag
+
+import "sync"
+
+// fuzzyIndexThreshold is the number of registered tags at which
+// fuzzy lookups use a BK-tree index instead of a linear scan
+const fuzzyIndexThreshold = 128
+
+// bkTree indexes tags by edit distance so that fuzzy lookups only
+// compare against tags that could be within the requested distance.
+// See https://en.wikipedia.org/wiki/BK-tree
+type bkTree struct {
+	root *bkNode
+}
+
+type bkNode struct {
+	tag string
+
+	// position of the tag in registration order
+	index    int
+	children map[int]*bkNode
+}
+
+// inserts a tag registered at the given position
+func (t *bkTree) insert(tag string, index int) {
+	node := &bkNode{tag: tag, index: index}
+	if t.root == nil {
+		t.root = node
+		return
+	}
+	current := t.root
+	for {
+		d := levenshtein(current.tag, tag)
+		if d == 0 {
+			return
+		}
+		child, ok := current.children[d]
+		if !ok {
+			if current.children == nil {
+				current.children = make(map[int]*bkNode)
+			}
+			current.children[d] = node
+			return
+		}
+		current = child
+	}
+}
+
+// returns the registration position of the earliest registered tag
+// within the given distance of tag, or false if there is none
+func (t *bkTree) search(tag string, distance int) (int, bool) {
+	if t.root == nil {
+		return 0, false
+	}
+	best, found := 0, false
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := levenshtein(node.tag, tag)
+		if d <= distance && (!found || node.index < best) {
+			best, found = node.index, true
+		}
+		for k, child := range node.children {
+			if k >= d-distance && k <= d+distance {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return best, found
+}
+
+// fuzzyIndex lazily builds a BK-tree over a tagSet on the first fuzzy
+// lookup and keeps it up to date as tags are added afterwards, so tags
+// registered late never force a rebuild
+type fuzzyIndex struct {
+	mu   sync.RWMutex
+	tree *bkTree
+}
+
+// inserts a tag into the index if it has been built
+func (idx *fuzzyIndex) insert(tag string, index int) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if idx.tree != nil {
+		idx.tree.insert(tag, index)
+	}
+}
+
+// searches the index, building it from the given tags
+// in registration order if needed
+func (idx *fuzzyIndex) search(tags []string, tag string, distance int) (int, bool) {
+	idx.mu.RLock()
+	if idx.tree == nil {
+		idx.mu.RUnlock()
+		idx.mu.Lock()
+		if idx.tree == nil {
+			tree := &bkTree{}
+			for i, t := range tags {
+				tree.insert(t, i)
+			}
+			idx.tree = tree
+		}
+		idx.mu.Unlock()
+		idx.mu.RLock()
+	}
+	defer idx.mu.RUnlock()
+	return idx.tree.search(tag, distance)
+}
@@ -0,0 +1,50 @@
+package gotag
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBKTreeSearch(t *testing.T) {
+	tree := &bkTree{}
+	tags := []string{"integration", "end-to-end", "unit", "intgration", "slow"}
+	for i, tag := range tags {
+		tree.insert(tag, i)
+	}
+
+	if i, ok := tree.search("integratoin", 2); !ok || i != 0 {
+		t.Errorf("Expected earliest match 'integration', got %d", i)
+	}
+	if i, ok := tree.search("units", 1); !ok || tags[i] != "unit" {
+		t.Errorf("Expected match 'unit', got %d", i)
+	}
+	if _, ok := tree.search("database", 2); ok {
+		t.Error("Expected no match")
+	}
+}
+
+func TestFuzzyIndexIncremental(t *testing.T) {
+	tc := New()
+	tc.Fuzzy = true
+	for i := 0; i < fuzzyIndexThreshold; i++ {
+		tc.Skip(fmt.Sprintf("generated-tag-%03d", i))
+	}
+
+	mock := &mockT{}
+	tc.Test("generated-tag-00", mock, func(t T) {})
+	if tc.skip.index.tree == nil {
+		t.Fatal("Expected fuzzy lookup to build the index")
+	}
+
+	// tags registered after the index is built are inserted
+	// into the existing index
+	tree := tc.skip.index.tree
+	tc.Skip("integration")
+	tc.Test("integratoin", mock, func(t T) {})
+	if mock.skipped != 2 {
+		t.Error("Wrong number of tests skipped")
+	}
+	if tc.skip.index.tree != tree {
+		t.Error("Expected the index to be updated rather than rebuilt")
+	}
+}
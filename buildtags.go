@@ -0,0 +1,47 @@
+package gotag
+
+import "runtime/debug"
+
+// reads the build tags the binary was built with, such as those given
+// to go test -tags, from the build information embedded by the go tool
+var readBuildTags = func() []string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	for _, s := range info.Settings {
+		if s.Key == "-tags" {
+			return splitTags(s.Value)
+		}
+	}
+	return nil
+}
+
+// BuildTags returns the build tags the running binary was built with,
+// e.g. integration for a test binary built by go test -tags=integration
+func BuildTags() []string {
+	return readBuildTags()
+}
+
+// RunBuildTags maps build tags to gotag tags. Each gotag tag whose build
+// tag the binary was built with is marked to run with RunOnly, so that
+// go test -tags=integration runs only integration tests given
+//
+//	tc.RunBuildTags(map[string]string{"integration": gotag.Integration, "e2e": gotag.EndToEnd})
+func (tc *TestContext) RunBuildTags(mapping map[string]string) {
+	var tags []string
+	for _, build := range readBuildTags() {
+		if tag, ok := mapping[build]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		tc.RunOnly(tags...)
+	}
+}
+
+// RunBuildTags maps build tags to gotag tags
+// within the default context
+func RunBuildTags(mapping map[string]string) {
+	Default().RunBuildTags(mapping)
+}
@@ -0,0 +1,27 @@
+package gotag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunBuildTags(t *testing.T) {
+	defer func(read func() []string) { readBuildTags = read }(readBuildTags)
+	readBuildTags = func() []string { return []string{"e2e", "netgo"} }
+
+	tc := New()
+	err := tc.Apply(&Config{BuildTags: map[string]string{"integration": Integration, "e2e": EndToEnd}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.RunTags(), ","); tags != EndToEnd {
+		t.Errorf("Expected only end-to-end to run, got %s", tags)
+	}
+
+	readBuildTags = func() []string { return nil }
+	tc = New()
+	tc.RunBuildTags(map[string]string{"integration": Integration})
+	if tags := tc.RunTags(); len(tags) != 0 {
+		t.Errorf("Expected no tags to run without build tags, got %v", tags)
+	}
+}
@@ -0,0 +1,106 @@
+package gotag
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// captureT records the failures of a test instead of reporting them,
+// logging their messages unless quiet. FailNow, Fatal and Fatalf call
+// stop, which must not return
+type captureT struct {
+	T
+	stop  func()
+	quiet bool
+
+	mu       sync.Mutex
+	failed   bool
+	detached bool
+	messages []string
+}
+
+// Name returns the name of the wrapped test so that
+// subtests started with Run inherit its tags
+func (c *captureT) Name() string {
+	if n, ok := c.T.(interface{ Name() string }); ok {
+		return n.Name()
+	}
+	return ""
+}
+
+func (c *captureT) Error(args ...interface{}) {
+	c.fail(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+func (c *captureT) Errorf(format string, args ...interface{}) {
+	c.fail(fmt.Sprintf(format, args...))
+}
+
+func (c *captureT) Fail() {
+	c.fail("")
+}
+
+func (c *captureT) FailNow() {
+	c.fail("")
+	c.stop()
+}
+
+func (c *captureT) Failed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.failed
+}
+
+func (c *captureT) Fatal(args ...interface{}) {
+	c.Error(args...)
+	c.stop()
+}
+
+func (c *captureT) Fatalf(format string, args ...interface{}) {
+	c.Errorf(format, args...)
+	c.stop()
+}
+
+func (c *captureT) Log(args ...interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.detached {
+		c.T.Log(args...)
+	}
+}
+
+func (c *captureT) Logf(format string, args ...interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.detached {
+		c.T.Logf(format, args...)
+	}
+}
+
+func (c *captureT) fail(msg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failed = true
+	if msg != "" {
+		c.messages = append(c.messages, msg)
+		if !c.detached && !c.quiet {
+			c.T.Log(msg)
+		}
+	}
+}
+
+// stops logging to the wrapped test, which may complete while the
+// test function keeps running
+func (c *captureT) detach() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.detached = true
+}
+
+// returns a copy of the failure messages
+func (c *captureT) failures() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.messages...)
+}
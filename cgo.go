@@ -0,0 +1,6 @@
+//go:build cgo
+
+package gotag
+
+// whether cgo is enabled, see CGO
+const cgoEnabled = true
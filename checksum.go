@@ -0,0 +1,28 @@
+package gotag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ErrChecksumMismatch is wrapped, along with the URL, by the error
+// returned when fetched config content does not hash to the SHA-256 it
+// was pinned to, so a compromised config source cannot silently change
+// which tests are skipped. Test for it with errors.Is
+var ErrChecksumMismatch = errors.New("Config checksum does not match pinned SHA-256")
+
+// verifies that data hashes to the pinned hex encoded SHA-256. An
+// optional "sha256:" prefix is accepted and an empty pin always passes
+func verifyChecksum(data []byte, pin string) error {
+	pin = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(pin)), "sha256:")
+	if pin == "" {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != pin {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
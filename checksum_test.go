@@ -0,0 +1,16 @@
+package gotag
+
+import "testing"
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("skip: [integration]\n")
+	sum := "2dca8750c6e557b56b08003a0cc3e713216cadf8bd418adb7bc68cf936acb913"
+	for _, pin := range []string{"", sum, "sha256:" + sum, " SHA256:" + sum + "\n"} {
+		if err := verifyChecksum(data, pin); err != nil {
+			t.Errorf("Expected pin %q to pass, got %v", pin, err)
+		}
+	}
+	if err := verifyChecksum([]byte("skip: []\n"), sum); err != ErrChecksumMismatch {
+		t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+	}
+}
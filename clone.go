@@ -0,0 +1,251 @@
+package gotag
+
+import (
+	"sort"
+	"time"
+)
+
+// Clone returns an independent copy of the context holding the same
+// tags, skip entries, groups, requirements, dependencies, hooks,
+// fixtures, retries, timeouts, priorities, locks, registered tests and
+// tags, reporters and settings.
+// Changes to either context don't affect the other.
+// Recorded decisions are not copied, and requirements, setup hooks and
+// fixtures are evaluated again by the clone, whose tags and fixtures
+// are torn down by its own Teardown
+func (tc *TestContext) Clone() *TestContext {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return tc.clone()
+}
+
+// Child returns a context refining this one. The child starts with a
+// copy of the context, like Clone, except for the tags marked by Skip
+// and RunOnly: lookups of those fall back to this context, so tags its
+// parent skips or runs later apply to the child as well. Tags the child
+// skips or runs are its own
+//
+//	root := gotag.Default()
+//	db := root.Child()
+//	db.Skip("postgres")
+func (tc *TestContext) Child() *TestContext {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	child := tc.clone()
+	child.skip = tc.emptyTagSet()
+	child.runOnly = tc.emptyTagSet()
+	child.parent = tc
+	return child
+}
+
+// Must be called with at least a read lock held
+func (tc *TestContext) clone() *TestContext {
+	c := &TestContext{
+		skip:         tc.copyTagSet(tc.skip),
+		runOnly:      tc.copyTagSet(tc.runOnly),
+		mustRun:      tc.copyTagSet(tc.mustRun),
+		shortSkip:    tc.copyTagSet(tc.shortSkip),
+		quarantine:   tc.copyTagSet(tc.quarantine),
+		selector:     tc.selector,
+		defaultSkip:  tc.defaultSkip,
+		foldCase:     tc.foldCase,
+		shard:        tc.shard,
+		distanceFunc: tc.distanceFunc,
+		suggestTags:  tc.suggestTags,
+		strictTags:   tc.strictTags,
+
+		requireRegistered: tc.requireRegistered,
+		parent:            tc.parent,
+
+		groups:        make(map[string][]string, len(tc.groups)),
+		prerequisites: make(map[string]*prerequisite, len(tc.prerequisites)),
+		active:        make(map[string][]string),
+		hooks:         make(map[string]*tagHooks, len(tc.hooks)),
+		fixtures:      make(map[string]*fixture, len(tc.fixtures)),
+		retries:       make(map[string]retryPolicy, len(tc.retries)),
+		timeouts:      make(map[string]time.Duration, len(tc.timeouts)),
+		priorities:    make(map[string]int, len(tc.priorities)),
+		hostSkips:     make(map[string]hostSkip, len(tc.hostSkips)),
+		exclusions:    make(map[string][]*exclusiveLock, len(tc.exclusions)),
+		dependencies:  make(map[string][]string, len(tc.dependencies)),
+		registered:    make(map[string][]string, len(tc.registered)),
+		tagRegistry:   make(map[string]Tag, len(tc.tagRegistry)),
+		skipEntries:   make(map[string]SkipEntry, len(tc.skipEntries)),
+		expired:       append([]SkipEntry(nil), tc.expired...),
+		messages:      make(map[skipKey][]interface{}),
+
+		Verbose:        tc.Verbose,
+		Logger:         tc.Logger,
+		EditDistance:   tc.EditDistance,
+		Fuzzy:          tc.Fuzzy,
+		FuzzyWorkers:   tc.FuzzyWorkers,
+		FuzzyThreshold: tc.FuzzyThreshold,
+		Mode:           tc.Mode,
+		InsertionOrder: tc.InsertionOrder,
+		SkipMessage:    tc.SkipMessage,
+		DryRun:         tc.DryRun,
+		Strict:         tc.Strict,
+
+		report:          tc.report,
+		junit:           tc.junit,
+		metrics:         tc.metrics,
+		webhook:         tc.webhook,
+		decisionLog:     tc.decisionLog,
+		decisionLogPath: tc.decisionLogPath,
+		reporters:       tc.reporters,
+	}
+	for key, members := range tc.groups {
+		c.groups[key] = append([]string(nil), members...)
+	}
+	for key, req := range tc.prerequisites {
+		c.prerequisites[key] = &prerequisite{name: req.name, pred: req.pred}
+	}
+	for key, h := range tc.hooks {
+		c.hooks[key] = &tagHooks{
+			tag:       h.tag,
+			setups:    append([]func() error(nil), h.setups...),
+			teardowns: append([]func(){}, h.teardowns...),
+		}
+	}
+	if h := tc.allHooks; h != nil {
+		c.allHooks = &tagHooks{
+			all:       true,
+			setups:    append([]func() error(nil), h.setups...),
+			teardowns: append([]func(){}, h.teardowns...),
+		}
+	}
+	for name, fix := range tc.fixtures {
+		c.fixtures[name] = &fixture{name: fix.name, provide: fix.provide}
+	}
+	for key, policy := range tc.retries {
+		c.retries[key] = policy
+	}
+	for key, d := range tc.timeouts {
+		c.timeouts[key] = d
+	}
+	for key, p := range tc.priorities {
+		c.priorities[key] = p
+	}
+	for key, skip := range tc.hostSkips {
+		c.hostSkips[key] = skip
+	}
+	for key, required := range tc.dependencies {
+		c.dependencies[key] = append([]string(nil), required...)
+	}
+	for key, locks := range tc.exclusions {
+		c.exclusions[key] = append([]*exclusiveLock(nil), locks...)
+	}
+	for key, tag := range tc.tagRegistry {
+		c.tagRegistry[key] = tag
+	}
+	for key, skip := range tc.skipEntries {
+		c.skipEntries[key] = skip
+	}
+	for name, tags := range tc.registered {
+		c.registered[name] = append([]string(nil), tags...)
+	}
+	return c
+}
+
+// returns a copy of the set. Must be called with at least a read lock held
+func (tc *TestContext) copyTagSet(set *tagSet) *tagSet {
+	c := tc.emptyTagSet()
+	for _, key := range set.order {
+		c.add(set.originals[key], key)
+	}
+	return c
+}
+
+// which of the tag sets inherited by children a lookup concerns
+type setKind int
+
+const (
+	skipSet setKind = iota
+	runOnlySet
+)
+
+func (tc *TestContext) set(kind setKind) *tagSet {
+	if kind == skipSet {
+		return tc.skip
+	}
+	return tc.runOnly
+}
+
+// The methods below look a tag set up in the context and its ancestors.
+// Each must be called with at least a read lock on the context held and
+// takes the read locks of its ancestors in turn
+
+// reports whether the canonical tag is covered by the set
+func (tc *TestContext) inheritedCovers(kind setKind, key string) bool {
+	if tc.set(kind).covers(key) {
+		return true
+	}
+	for p := tc.parent; p != nil; p = p.parent {
+		p.mu.RLock()
+		ok := p.set(kind).covers(key)
+		p.mu.RUnlock()
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the number of tags in the set
+func (tc *TestContext) inheritedLen(kind setKind) int {
+	n := tc.set(kind).len()
+	for p := tc.parent; p != nil; p = p.parent {
+		p.mu.RLock()
+		n += p.set(kind).len()
+		p.mu.RUnlock()
+	}
+	return n
+}
+
+// returns the tag of the set within the edit distance of the canonical tag
+func (tc *TestContext) inheritedFuzzy(kind setKind, key string) (string, bool) {
+	if match, ok := tc.checkFuzzy(key, tc.set(kind)); ok {
+		return match, true
+	}
+	for p := tc.parent; p != nil; p = p.parent {
+		p.mu.RLock()
+		match, ok := tc.checkFuzzy(key, p.set(kind))
+		p.mu.RUnlock()
+		if ok {
+			return match, true
+		}
+	}
+	return "", false
+}
+
+// returns the original forms of the tags in the set, the tags of
+// ancestors first, sorted unless insertion order is requested
+func (tc *TestContext) inheritedTags(kind setKind) []string {
+	if tc.parent == nil {
+		return tc.set(kind).tags(tc.InsertionOrder)
+	}
+	var chain []*TestContext
+	for p := tc.parent; p != nil; p = p.parent {
+		chain = append(chain, p)
+	}
+	var tags []string
+	seen := make(map[string]bool)
+	add := func(set *tagSet) {
+		for _, tag := range set.tags(true) {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		chain[i].mu.RLock()
+		add(chain[i].set(kind))
+		chain[i].mu.RUnlock()
+	}
+	add(tc.set(kind))
+	if !tc.InsertionOrder {
+		sort.Strings(tags)
+	}
+	return tags
+}
@@ -0,0 +1,63 @@
+package gotag
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	tc := New()
+	tc.Fuzzy = true
+	tc.Skip("tagA")
+	tc.DefineGroup("group", "tagB")
+	tc.Timeout("tagC", time.Second)
+
+	clone := tc.Clone()
+	clone.Skip("group")
+	tc.Skip("tagD")
+	if tags := strings.Join(clone.SkippedTags(), ","); tags != "group,tagA,tagB" {
+		t.Errorf("Expected the clone's skips to be independent, got %s", tags)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "tagA,tagD" {
+		t.Errorf("Expected the original's skips to be independent, got %s", tags)
+	}
+	if !clone.Fuzzy || clone.timeouts["tagC"] != time.Second {
+		t.Error("Expected the clone to copy settings and timeouts")
+	}
+}
+
+func TestChild(t *testing.T) {
+	root := New()
+	root.Skip("tagA")
+	child := root.Child()
+	child.Skip("tagB")
+	root.Skip("tagC")
+
+	for _, tag := range []string{"tagA", "tagB", "tagC"} {
+		if skipped, _ := child.WouldSkip(tag); !skipped {
+			t.Errorf("Expected the child to skip %s", tag)
+		}
+	}
+	if skipped, _ := root.WouldSkip("tagB"); skipped {
+		t.Error("Expected the child's skips not to affect its parent")
+	}
+	if tags := strings.Join(child.SkippedTags(), ","); tags != "tagA,tagB,tagC" {
+		t.Errorf("Expected the child to list inherited tags, got %s", tags)
+	}
+
+	root.RunOnly("tagD")
+	if skipped, _ := child.WouldSkip("tagE"); !skipped {
+		t.Error("Expected the parent's run tags to apply to the child")
+	}
+	child.RunOnly("tagE")
+	if skipped, _ := child.WouldSkip("tagE"); skipped {
+		t.Error("Expected the child's run tags to apply")
+	}
+
+	grandchild := child.Child()
+	grandchild.Fuzzy = true
+	if skipped, reason := grandchild.WouldSkip("tagX"); skipped {
+		t.Errorf("Expected fuzzy matches against run tags of ancestors, got %s", reason)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// shieldsBadge is the shields.io endpoint badge format.
+// See https://shields.io/badges/endpoint-badge
+type shieldsBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// writes a badge describing the given tag, or all tags if tag is empty
+func writeBadge(path string, summaries []tagSummary, tag string) error {
+	b, err := newBadge(summaries, tag)
+	if err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bytes, 0644)
+}
+
+func newBadge(summaries []tagSummary, tag string) (shieldsBadge, error) {
+	label := "tests"
+	var run, skipped, failed int
+	found := false
+	for _, s := range summaries {
+		if tag != "" && s.Tag != tag {
+			continue
+		}
+		found = true
+		run += s.Run
+		skipped += s.Skipped
+		failed += s.Failed
+	}
+	if tag != "" {
+		if !found {
+			return shieldsBadge{}, fmt.Errorf("Tag '%s' does not appear in the report", tag)
+		}
+		label = tag + " tests"
+	}
+
+	message := fmt.Sprintf("%d run / %d skipped", run, skipped)
+	color := "brightgreen"
+	switch {
+	case failed > 0:
+		message = fmt.Sprintf("%s / %d failed", message, failed)
+		color = "red"
+	case skipped > 0:
+		color = "yellow"
+	}
+	return shieldsBadge{SchemaVersion: 1, Label: label, Message: message, Color: color}, nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestNewBadge(t *testing.T) {
+	summaries := []tagSummary{
+		{Tag: "integration", Run: 124, Skipped: 3},
+		{Tag: "unit", Run: 10, Failed: 1},
+	}
+
+	b, err := newBadge(summaries, "integration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Label != "integration tests" || b.Message != "124 run / 3 skipped" || b.Color != "yellow" {
+		t.Errorf("Unexpected badge %+v", b)
+	}
+
+	b, _ = newBadge(summaries, "")
+	if b.Message != "134 run / 3 skipped / 1 failed" || b.Color != "red" {
+		t.Errorf("Unexpected badge %+v", b)
+	}
+
+	if _, err := newBadge(summaries, "missing"); err == nil {
+		t.Error("Expected an error for a missing tag")
+	}
+}
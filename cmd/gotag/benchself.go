@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/boxtown/gotag"
+)
+
+// benchResult holds the measurements of a single benchmark
+type benchResult struct {
+	NsPerOp     int64 `json:"ns_per_op"`
+	AllocsPerOp int64 `json:"allocs_per_op"`
+}
+
+// benchSelf runs the matching engine benchmarks, optionally saving the
+// results as a baseline or comparing them against a previous baseline
+func benchSelf(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("bench-self", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	baseline := fs.String("baseline", "", "compare results against a baseline file")
+	save := fs.String("save", "", "save results as a baseline file")
+	threshold := fs.Float64("threshold", 0.2, "allowed slowdown relative to the baseline")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	results := make(map[string]benchResult)
+	for _, bench := range selfBenchmarks() {
+		r := testing.Benchmark(bench.fn)
+		results[bench.name] = benchResult{NsPerOp: r.NsPerOp(), AllocsPerOp: r.AllocsPerOp()}
+		fmt.Fprintf(stdout, "%-28s %12d ns/op %6d allocs/op\n", bench.name, r.NsPerOp(), r.AllocsPerOp())
+	}
+
+	if *save != "" {
+		bytes, err := json.MarshalIndent(results, "", "  ")
+		if err == nil {
+			err = ioutil.WriteFile(*save, bytes, 0644)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: could not save baseline: %v\n", err)
+			return 2
+		}
+	}
+	if *baseline == "" {
+		return 0
+	}
+
+	bytes, err := ioutil.ReadFile(*baseline)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: could not read baseline: %v\n", err)
+		return 2
+	}
+	var previous map[string]benchResult
+	if err := json.Unmarshal(bytes, &previous); err != nil {
+		fmt.Fprintf(stderr, "gotag: could not parse baseline: %v\n", err)
+		return 2
+	}
+	regressions := compareBenchmarks(previous, results, *threshold)
+	for _, r := range regressions {
+		fmt.Fprintf(stderr, "REGRESSION %s\n", r)
+	}
+	if len(regressions) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// returns a description of every benchmark that is slower than its
+// baseline by more than the threshold or allocates more
+func compareBenchmarks(baseline, results map[string]benchResult, threshold float64) []string {
+	var regressions []string
+	for name, r := range results {
+		base, ok := baseline[name]
+		if !ok {
+			continue
+		}
+		if float64(r.NsPerOp) > float64(base.NsPerOp)*(1+threshold) {
+			regressions = append(regressions, fmt.Sprintf(
+				"%s: %d ns/op, baseline %d ns/op", name, r.NsPerOp, base.NsPerOp))
+		}
+		if r.AllocsPerOp > base.AllocsPerOp {
+			regressions = append(regressions, fmt.Sprintf(
+				"%s: %d allocs/op, baseline %d allocs/op", name, r.AllocsPerOp, base.AllocsPerOp))
+		}
+	}
+	sort.Strings(regressions)
+	return regressions
+}
+
+type selfBenchmark struct {
+	name string
+	fn   func(b *testing.B)
+}
+
+// the benchmarks run by bench-self, mirroring those in the
+// gotag package but built only on its public API
+func selfBenchmarks() []selfBenchmark {
+	benchmarks := []selfBenchmark{
+		{"exact/skip", benchTest([]string{"tagA", "tagB"}, false, 0)},
+		{"exact/run", benchTest([]string{"tagB", "tagC"}, false, 0)},
+	}
+	for _, size := range []int{16, 256, 4096} {
+		benchmarks = append(benchmarks, selfBenchmark{
+			fmt.Sprintf("fuzzy/size=%d", size),
+			benchTest([]string{strings.Repeat("z", 16), strings.Repeat("y", 16)}, true, size),
+		})
+	}
+	return append(benchmarks,
+		selfBenchmark{"cached/size=4096", benchTest([]string{strings.Repeat("z", 16)}, true, 4096)},
+		selfBenchmark{"load/json", benchLoad})
+}
+
+// benchmarks TestTags against a context skipping tagA and size
+// generated tags. Only decisions for a single tag are cached, so
+// tests with several tags measure matching on every iteration
+func benchTest(tags []string, fuzzy bool, size int) func(b *testing.B) {
+	return func(b *testing.B) {
+		tc := gotag.New()
+		tc.Fuzzy = fuzzy
+		tc.Skip("tagA")
+		for i := 0; i < size; i++ {
+			tc.Skip(fmt.Sprintf("generated-tag-%05d", i))
+		}
+		t := nopT{}
+		fn := func(t gotag.T) {}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			tc.TestTags(tags, t, fn)
+		}
+	}
+}
+
+func benchLoad(b *testing.B) {
+	dir, err := ioutil.TempDir("", "gotag-bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	config := `{"skip": ["integration", "end-to-end"], "fuzzy": true, "distance": 3}`
+	if err := ioutil.WriteFile(filepath.Join(dir, ".gotag.json"), []byte(config), 0644); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := gotag.LoadFrom(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// nopT is a gotag.T that does nothing
+type nopT struct{}
+
+func (nopT) Error(...interface{})              {}
+func (nopT) Errorf(string, ...interface{})     {}
+func (nopT) Fail()                             {}
+func (nopT) FailNow()                          {}
+func (nopT) Failed() bool                      { return false }
+func (nopT) Fatal(...interface{})              {}
+func (nopT) Fatalf(string, ...interface{})     {}
+func (nopT) Log(...interface{})                {}
+func (nopT) Logf(string, ...interface{})       {}
+func (nopT) Parallel()                         {}
+func (nopT) Run(string, func(*testing.T)) bool { return false }
+func (nopT) Skip(...interface{})               {}
+func (nopT) SkipNow()                          {}
+func (nopT) Skipf(string, ...interface{})      {}
+func (nopT) Skipped() bool                     { return false }
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestCompareBenchmarks(t *testing.T) {
+	baseline := map[string]benchResult{
+		"a": {NsPerOp: 100, AllocsPerOp: 0},
+		"b": {NsPerOp: 100, AllocsPerOp: 1},
+	}
+	results := map[string]benchResult{
+		"a": {NsPerOp: 150, AllocsPerOp: 1},
+		"b": {NsPerOp: 110, AllocsPerOp: 1},
+		"c": {NsPerOp: 1000, AllocsPerOp: 10},
+	}
+	regressions := compareBenchmarks(baseline, results, 0.2)
+	if len(regressions) != 2 {
+		t.Errorf("Expected 2 regressions, got %v", regressions)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// goPackage is a package listed by go list along with its dependencies
+type goPackage struct {
+	ImportPath string
+	Dir        string
+	// whether the package only matched as a dependency of the patterns
+	DepOnly bool
+	Deps    []string
+}
+
+// lists the packages matching the patterns and their dependencies.
+// A variable so tests need not build packages
+var listPackages = func(patterns []string) ([]goPackage, error) {
+	const format = "{{.ImportPath}}\t{{.Dir}}\t{{.DepOnly}}\t{{join .Deps \" \"}}"
+	args := append([]string{"list", "-deps", "-f", format}, patterns...)
+	out, err := exec.Command("go", args...).Output()
+	if exit, ok := err.(*exec.ExitError); ok {
+		return nil, fmt.Errorf("go list: %s", strings.TrimSpace(string(exit.Stderr)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var pkgs []goPackage
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		pkgs = append(pkgs, goPackage{
+			ImportPath: fields[0],
+			Dir:        fields[1],
+			DepOnly:    fields[2] == "true",
+			Deps:       strings.Fields(fields[3]),
+		})
+	}
+	return pkgs, nil
+}
+
+// returns the root of the git repository, which the changed files
+// are relative to. A variable so tests need not run git
+var repoRoot = func() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// returns the sorted import paths of the packages matching the patterns
+// that are affected by the changes since the git ref: those holding a
+// changed file or depending on a package that does. A changed go.mod or
+// go.sum affects every package
+func affectedPackages(patterns []string, ref string) ([]string, error) {
+	files, err := changedFiles(ref)
+	if err != nil {
+		return nil, err
+	}
+	root, err := repoRoot()
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := listPackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	all := false
+	dirs := make(map[string]bool, len(files))
+	for _, file := range files {
+		if base := filepath.Base(file); base == "go.mod" || base == "go.sum" {
+			all = true
+		}
+		dirs[filepath.Join(root, filepath.Dir(filepath.FromSlash(file)))] = true
+	}
+	changed := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if dirs[filepath.Clean(pkg.Dir)] {
+			changed[pkg.ImportPath] = true
+		}
+	}
+
+	var affected []string
+	for _, pkg := range pkgs {
+		if !pkg.DepOnly && (all || changed[pkg.ImportPath] || dependsOnAny(pkg.Deps, changed)) {
+			affected = append(affected, pkg.ImportPath)
+		}
+	}
+	sort.Strings(affected)
+	return affected, nil
+}
+
+func dependsOnAny(deps []string, changed map[string]bool) bool {
+	for _, dep := range deps {
+		if changed[dep] {
+			return true
+		}
+	}
+	return false
+}
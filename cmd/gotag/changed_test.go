@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunChanged(t *testing.T) {
+	root := filepath.FromSlash("/repo")
+	defer func(fn func() (string, error)) { repoRoot = fn }(repoRoot)
+	repoRoot = func() (string, error) { return root, nil }
+	defer func(fn func([]string) ([]goPackage, error)) { listPackages = fn }(listPackages)
+	listPackages = func(patterns []string) ([]goPackage, error) {
+		return []goPackage{
+			{ImportPath: "example.com/db", Dir: filepath.Join(root, "db"), DepOnly: true},
+			{ImportPath: "example.com/api", Dir: filepath.Join(root, "api"), Deps: []string{"example.com/db"}},
+			{ImportPath: "example.com/web", Dir: filepath.Join(root, "web")},
+		}, nil
+	}
+	var files []string
+	defer func(fn func(string) ([]string, error)) { changedFiles = fn }(changedFiles)
+	changedFiles = func(ref string) ([]string, error) {
+		if ref != "origin/main" {
+			t.Errorf("Unexpected ref %s", ref)
+		}
+		return files, nil
+	}
+	var tested []string
+	defer func(fn func([]string, []string, io.Writer, io.Writer) (int, error)) { goTest = fn }(goTest)
+	goTest = func(args, env []string, stdout, stderr io.Writer) (int, error) {
+		tested = append(tested, args[len(args)-1])
+		return 0, nil
+	}
+
+	files = []string{"db/db.go", "README.md"}
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"run", "-changed", "origin/main", "./..."}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !reflect.DeepEqual(tested, []string{"example.com/api"}) {
+		t.Errorf("Expected only the dependent package to be tested, got %v", tested)
+	}
+
+	tested = nil
+	files = []string{"docs/index.md"}
+	run([]string{"run", "-changed", "origin/main", "./..."}, &stdout, &stderr)
+	if len(tested) != 0 || !strings.Contains(stderr.String(), "no packages affected") {
+		t.Errorf("Expected no packages to be tested, got %v", tested)
+	}
+
+	files = []string{"go.sum"}
+	if pkgs, err := affectedPackages([]string{"./..."}, "origin/main"); err != nil || len(pkgs) != 2 {
+		t.Errorf("Expected a changed go.sum to affect every package, got %v, %v", pkgs, err)
+	}
+}
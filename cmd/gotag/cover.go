@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/boxtown/gotag"
+)
+
+// pass is a go test run over every package, only running the given
+// tag if any
+type pass struct {
+	tc  *gotag.TestContext
+	tag string
+}
+
+// labels a package tested by the pass
+func (p pass) label(pkg string) string {
+	if p.tag == "" {
+		return pkg
+	}
+	return fmt.Sprintf("%s (%s)", pkg, p.tag)
+}
+
+// returns a pass per tag to run, each only running its tag along with
+// the rest of the selection, or a single pass if no tags are given
+func coverPasses(tc *gotag.TestContext, tags []string) []pass {
+	if len(tags) == 0 {
+		return []pass{{tc: tc}}
+	}
+	passes := make([]pass, len(tags))
+	for i, tag := range tags {
+		c := tc.Clone()
+		c.ClearRunOnly()
+		c.RunOnly(tag)
+		passes[i] = pass{tc: c, tag: tag}
+	}
+	return passes
+}
+
+// coverProfile merges the coverage profiles written by go test
+type coverProfile struct {
+	dir   string
+	n     int
+	mode  string
+	count map[string]int
+}
+
+func newCoverProfile(dir string) *coverProfile {
+	return &coverProfile{dir: dir, count: make(map[string]int)}
+}
+
+// returns the path the next profile is written to
+func (p *coverProfile) next() string {
+	p.n++
+	return filepath.Join(p.dir, fmt.Sprintf("%d.out", p.n))
+}
+
+// merges the profile written to the last path returned by next. Packages
+// without tests write no profile. Blocks count the executions of every
+// profile in count and atomic mode, and whether any executed in set mode
+func (p *coverProfile) addLast() error {
+	f, err := os.Open(filepath.Join(p.dir, fmt.Sprintf("%d.out", p.n)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "mode: ") {
+			mode := strings.TrimPrefix(line, "mode: ")
+			if p.mode != "" && mode != p.mode {
+				return fmt.Errorf("cannot merge coverage profiles of modes %s and %s", p.mode, mode)
+			}
+			p.mode = mode
+			continue
+		}
+		// file.go:line.col,line.col statements count
+		space := strings.LastIndex(line, " ")
+		if space < 0 {
+			continue
+		}
+		n, err := strconv.Atoi(line[space+1:])
+		if err != nil {
+			return fmt.Errorf("malformed coverage profile line %q", line)
+		}
+		block := line[:space]
+		if p.mode == "set" {
+			if n > 0 || p.count[block] > 0 {
+				n = 1
+			}
+			p.count[block] = n
+		} else {
+			p.count[block] += n
+		}
+	}
+	return scanner.Err()
+}
+
+// writes the merged profile to the path, with its blocks sorted
+func (p *coverProfile) write(path string) error {
+	mode := p.mode
+	if mode == "" {
+		mode = "set"
+	}
+	blocks := make([]string, 0, len(p.count))
+	for block := range p.count {
+		blocks = append(blocks, block)
+	}
+	sort.Strings(blocks)
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "mode: %s\n", mode)
+	for _, block := range blocks {
+		fmt.Fprintf(&b, "%s %d\n", block, p.count[block])
+	}
+	return ioutil.WriteFile(path, b.Bytes(), 0644)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunCoverprofile(t *testing.T) {
+	defer func(fn func([]string) ([]string, error)) { goList = fn }(goList)
+	goList = func(patterns []string) ([]string, error) {
+		return []string{"example.com/a", "example.com/b"}, nil
+	}
+	profiles := map[string]string{
+		"unit example.com/a":        "mode: count\na.go:1.1,2.2 1 2\na.go:3.1,4.2 1 0\n",
+		"integration example.com/a": "mode: count\na.go:1.1,2.2 1 1\na.go:3.1,4.2 1 3\n",
+		"integration example.com/b": "mode: count\nb.go:1.1,2.2 2 0\n",
+	}
+	var runs []string
+	defer func(fn func([]string, []string, io.Writer, io.Writer) (int, error)) { goTest = fn }(goTest)
+	goTest = func(args, env []string, stdout, stderr io.Writer) (int, error) {
+		var run string
+		for _, v := range env {
+			if strings.HasPrefix(v, "GOTAG_RUN=") {
+				run = strings.TrimPrefix(v, "GOTAG_RUN=")
+			}
+		}
+		key := run + " " + args[len(args)-1]
+		runs = append(runs, key)
+		if profile, ok := profiles[key]; ok {
+			path := strings.TrimPrefix(args[len(args)-2], "-coverprofile=")
+			if err := ioutil.WriteFile(path, []byte(profile), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return 0, nil
+	}
+
+	out := filepath.Join(t.TempDir(), "merged.out")
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"run", "-coverprofile", out, "-t", "unit", "-t", "integration", "./..."}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	want := []string{"unit example.com/a", "unit example.com/b", "integration example.com/a", "integration example.com/b"}
+	if !reflect.DeepEqual(runs, want) {
+		t.Errorf("Expected a pass per tag, got %v", runs)
+	}
+	merged, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(merged); got != "mode: count\na.go:1.1,2.2 1 3\na.go:3.1,4.2 1 3\nb.go:1.1,2.2 2 0\n" {
+		t.Errorf("Unexpected merged profile %q", got)
+	}
+}
+
+func TestMergeSetProfiles(t *testing.T) {
+	dir := t.TempDir()
+	p := newCoverProfile(dir)
+	for _, profile := range []string{"mode: set\na.go:1.1,2.2 1 1\n", "mode: set\na.go:1.1,2.2 1 0\n"} {
+		if err := ioutil.WriteFile(p.next(), []byte(profile), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := p.addLast(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p.count["a.go:1.1,2.2 1"] != 1 {
+		t.Errorf("Expected a block covered by any profile to be covered, got %v", p.count)
+	}
+	if err := ioutil.WriteFile(p.next(), []byte("mode: count\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.addLast(); err == nil {
+		t.Error("Expected profiles of different modes not to merge")
+	}
+}
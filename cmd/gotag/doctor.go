@@ -0,0 +1,247 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/boxtown/gotag"
+)
+
+// finding is a problem reported by gotag doctor
+type finding struct {
+	err     bool
+	message string
+}
+
+// findings collects the problems found by gotag doctor
+type findings []finding
+
+func (f *findings) errorf(format string, args ...interface{}) {
+	*f = append(*f, finding{err: true, message: fmt.Sprintf(format, args...)})
+}
+
+func (f *findings) warnf(format string, args ...interface{}) {
+	*f = append(*f, finding{message: fmt.Sprintf(format, args...)})
+}
+
+// doctor checks the config files discovered from the current directory
+// against the tags used by the test files matched by the patterns,
+// ./... by default. It reports the problems of malformed configs, such
+// as unknown fields, tags in the config that no test uses and tags used
+// by tests that appear nowhere in the config, and fuzzy matching
+// settings likely to skip tests by accident. Returns 1 if any problem
+// was found
+func doctor(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	funcs, err := scan(patterns)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	paths, err := gotag.ConfigFiles(wd)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+
+	var found findings
+	var configs []*gotag.Config
+	if len(paths) == 0 {
+		found.warnf("no config file found, run gotag init to write one")
+	}
+	for _, path := range paths {
+		if config := checkConfigFile(path, &found); config != nil {
+			configs = append(configs, config)
+		}
+	}
+	config := gotag.MergeConfigs(configs...)
+	if len(configs) > 0 {
+		if err := gotag.New().Apply(config); err != nil {
+			found.errorf("%v", err)
+		}
+	}
+	usages := tagUsages(funcs)
+	checkConfigTags(config, usages, &found)
+	checkFuzzy(config, usages, &found)
+
+	if len(found) == 0 {
+		fmt.Fprintln(stdout, "gotag doctor: no problems found")
+		return 0
+	}
+	for _, f := range found {
+		severity := "warning"
+		if f.err {
+			severity = "error"
+		}
+		fmt.Fprintf(stdout, "%s: %s\n", severity, f.message)
+	}
+	fmt.Fprintf(stdout, "gotag doctor: %d problem(s) found\n", len(found))
+	return 1
+}
+
+// reports the problems of the config file, returning the
+// decoded config or nil if it is malformed
+func checkConfigFile(path string, found *findings) *gotag.Config {
+	config, err := gotag.LoadConfigFile(path)
+	if errs, ok := err.(gotag.ConfigErrors); ok {
+		for _, e := range errs {
+			e.Path = filepath.Base(e.Path)
+			found.errorf("%v", e)
+		}
+		return nil
+	}
+	if err != nil {
+		found.errorf("%v", err)
+		return nil
+	}
+	return config
+}
+
+// returns the tags named by the config along with the option naming
+// each of them first. Tag patterns are left out
+func configTags(c *gotag.Config) map[string]string {
+	tags := make(map[string]string)
+	add := func(option string, names ...string) {
+		for _, tag := range names {
+			if _, ok := tags[tag]; !ok && !strings.ContainsAny(tag, "*?/") {
+				tags[tag] = option
+			}
+		}
+	}
+	add("skip", c.Skip...)
+	for _, entry := range c.SkipEntries {
+		add("skip", entry.Tag)
+	}
+	add("run", c.Run...)
+	add("must_run", c.MustRun...)
+	add("quarantine", c.Quarantine...)
+	add("short_skips", c.ShortSkips...)
+	for _, rule := range c.SkipIf {
+		add("skip_if", rule.Tag)
+	}
+	for _, tag := range c.Tags {
+		add("tags", tag.Name)
+	}
+	for tag, required := range c.Depends {
+		add("depends", tag)
+		add("depends", required...)
+	}
+	for tag := range c.Timeouts {
+		add("timeouts", tag)
+	}
+	for _, tag := range c.BuildTags {
+		add("build_tags", tag)
+	}
+	for _, members := range c.Groups {
+		add("groups", members...)
+	}
+	return tags
+}
+
+// reports the tags of the config that no test uses and the tags used by
+// tests that appear nowhere in the config. A tag covers its namespaced
+// tags and a group is used if any of its members is
+func checkConfigTags(c *gotag.Config, usages []tagUsage, found *findings) {
+	tags := configTags(c)
+	used := func(tag string) bool {
+		for _, u := range usages {
+			if u.Tag == tag || strings.HasPrefix(u.Tag, tag+".") {
+				return true
+			}
+		}
+		return false
+	}
+	names := make([]string, 0, len(tags))
+	for tag := range tags {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+	for _, tag := range names {
+		if used(tag) {
+			continue
+		}
+		if members, ok := c.Groups[tag]; ok && anyUsed(members, used) {
+			continue
+		}
+		found.warnf("tag '%s' in %s is not used by any test, remove it or check its spelling", tag, tags[tag])
+	}
+
+	for _, u := range usages {
+		covered := false
+		for tag := range tags {
+			if u.Tag == tag || strings.HasPrefix(u.Tag, tag+".") {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			found.warnf("tag '%s' used by %d test(s) appears nowhere in the config, register it under tags", u.Tag, u.Count)
+		}
+	}
+}
+
+func anyUsed(tags []string, used func(string) bool) bool {
+	for _, tag := range tags {
+		if used(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// reports fuzzy matching settings that skip tests by accident: tags used
+// by tests within the edit distance of a skipped tag they differ from,
+// and distances matching any tag as short as a skipped one
+func checkFuzzy(c *gotag.Config, usages []tagUsage, found *findings) {
+	if !c.Fuzzy {
+		return
+	}
+	distance := c.EditDistance
+	if distance <= 0 {
+		distance = gotag.New().EditDistance
+	}
+	measure := gotag.Levenshtein
+	if c.DistanceFunc != "" {
+		var err error
+		if measure, err = gotag.ParseDistanceFunc(c.DistanceFunc); err != nil {
+			return
+		}
+	}
+	skipped := append([]string(nil), c.Skip...)
+	for _, entry := range c.SkipEntries {
+		skipped = append(skipped, entry.Tag)
+	}
+	sort.Strings(skipped)
+	for _, skip := range skipped {
+		if distance >= len(skip) {
+			found.warnf("fuzzy distance %d is not below the length of skipped tag '%s', which then matches unrelated short tags", distance, skip)
+		}
+		for _, u := range usages {
+			if u.Tag == skip || strings.HasPrefix(u.Tag, skip+".") {
+				continue
+			}
+			if d := measure(u.Tag, skip); d <= distance {
+				found.warnf("tag '%s' used by %d test(s) is within an edit distance of %d of skipped tag '%s' and is skipped by fuzzy matching, lower distance or rename one of the tags",
+					u.Tag, u.Count, d, skip)
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDoctor(t *testing.T) {
+	t.Setenv("GOTAG_USER_CONFIG", "off")
+	sample, err := filepath.Abs(filepath.Join("testdata", "sample"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := `skip: [slw, {tag: db, reasn: flaky}]
+tags:
+  - {name: integration, ownr: qa}
+profiles:
+  ci:
+    dry_rnu: true
+`
+	if err := ioutil.WriteFile(filepath.Join(root, ".gotag.yml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"doctor", sample}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1, got %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"error: .gotag.yml:1: unknown field 'skip[1].reasn', did you mean 'reason'?\n",
+		"error: .gotag.yml:3: unknown field 'tags[0].ownr', did you mean 'owner'?\n",
+		"error: .gotag.yml:6: unknown field 'profiles.ci.dry_rnu', did you mean 'dry_run'?\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got\n%s", want, out)
+		}
+	}
+
+	config = "skip: [slw]\nfuzzy: true\ndistance: 1\ntags: [{name: integration}, {name: unit}]\n"
+	if err := ioutil.WriteFile(filepath.Join(root, ".gotag.yml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if code := run([]string{"doctor", sample}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1, got %d: %s", code, stderr.String())
+	}
+	out = stdout.String()
+	for _, want := range []string{
+		"warning: tag 'unit' in tags is not used by any test",
+		"warning: tag 'postgres' used by 1 test(s) appears nowhere in the config",
+		"warning: tag 'slow' used by 1 test(s) is within an edit distance of 1 of skipped tag 'slw'",
+		"gotag doctor: 7 problem(s) found\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got\n%s", want, out)
+		}
+	}
+
+	config = "skip: [slow]\ntags: [{name: db}, {name: integration}, {name: postgres}, {name: fuzz-long}]\n"
+	if err := ioutil.WriteFile(filepath.Join(root, ".gotag.yml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if code := run([]string{"doctor", sample}, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected exit code 0, got %d:\n%s", code, stdout.String())
+	}
+
+	// the skip file and the user config are checked as Load discovers them
+	if err := ioutil.WriteFile(filepath.Join(root, ".gotagskip"), []byte("flakey\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	user := filepath.Join(t.TempDir(), "config.yml")
+	if err := ioutil.WriteFile(user, []byte("skip: [vendorr]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOTAG_USER_CONFIG", user)
+	stdout.Reset()
+	if code := run([]string{"doctor", sample}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1, got %d: %s", code, stderr.String())
+	}
+	out = stdout.String()
+	for _, want := range []string{"tag 'flakey' in skip", "tag 'vendorr' in skip"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got\n%s", want, out)
+		}
+	}
+}
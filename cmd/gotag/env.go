@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/boxtown/gotag"
+)
+
+// env prints the effective selection as environment variable
+// assignments for shell scripts or Makefiles
+func env(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("env", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "sh", "output format: sh or make")
+	var s selection
+	s.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	tc, err := s.resolve(fs)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+
+	vars := envVars(tc)
+	if s.profile != "" {
+		vars = append(vars, [2]string{gotag.EnvProfile, s.profile})
+	}
+	switch *format {
+	case "sh":
+		for _, v := range vars {
+			fmt.Fprintf(stdout, "export %s=%s\n", v[0], shellQuote(v[1]))
+		}
+	case "make":
+		for _, v := range vars {
+			fmt.Fprintf(stdout, "%s := %s\nexport %s\n", v[0], v[1], v[0])
+		}
+	default:
+		fmt.Fprintf(stderr, "gotag: unknown env format '%s'\n", *format)
+		return 2
+	}
+	return 0
+}
+
+// returns the GOTAG_* variables describing the context's selection
+func envVars(tc *gotag.TestContext) [][2]string {
+	vars := [][2]string{
+		{gotag.EnvSkip, strings.Join(tc.SkippedTags(), ",")},
+		{gotag.EnvRun, strings.Join(tc.RunTags(), ",")},
+		{gotag.EnvFuzzy, strconv.FormatBool(tc.Fuzzy)},
+		{gotag.EnvDistance, strconv.Itoa(tc.EditDistance)},
+	}
+	if tc.Mode != gotag.RunOnlyWins {
+		vars = append(vars, [2]string{gotag.EnvMode, tc.Mode.String()})
+	}
+	if sel := tc.Selector(); sel != nil {
+		vars = append(vars, [2]string{gotag.EnvSelector, sel.String()})
+	}
+	return vars
+}
+
+// quotes a value for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnv(t *testing.T) {
+	t.Setenv("GOTAG_SKIP", "integration")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"env", "-skip", "end-to-end", "-fuzzy"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "export GOTAG_SKIP='end-to-end,integration'\n") {
+		t.Errorf("Unexpected output %s", out)
+	}
+	if !strings.Contains(out, "export GOTAG_FUZZY='true'\n") {
+		t.Errorf("Unexpected output %s", out)
+	}
+
+	stdout.Reset()
+	run([]string{"env", "-format", "make"}, &stdout, &stderr)
+	if !strings.Contains(stdout.String(), "GOTAG_SKIP := integration\nexport GOTAG_SKIP\n") {
+		t.Errorf("Unexpected output %s", stdout.String())
+	}
+}
+
+func TestEnvProfile(t *testing.T) {
+	t.Setenv("GOTAG_USER_CONFIG", "off")
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "profiles:\n  local:\n    skip: [integration]\n"
+	if err := ioutil.WriteFile(filepath.Join(root, ".gotag.yml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"env", "-profile", "local"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "export GOTAG_SKIP='integration'\n") || !strings.Contains(out, "export GOTAG_PROFILE='local'\n") {
+		t.Errorf("Unexpected output %s", out)
+	}
+	if code := run([]string{"env", "-profile", "nightly"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for an unknown profile, got %d", code)
+	}
+}
+
+func TestEnvOwner(t *testing.T) {
+	t.Setenv("GOTAG_USER_CONFIG", "off")
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "tags:\n  - {name: integration, owner: platform-team}\n  - {name: billing, owner: payments}\n"
+	if err := ioutil.WriteFile(filepath.Join(root, ".gotag.yml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"env", "-owner", "platform-team"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); !strings.Contains(out, "export GOTAG_RUN='integration'\n") {
+		t.Errorf("Expected the owned tags to run, got %s", out)
+	}
+	if code := run([]string{"env", "-owner", "qa"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for an owner without tags, got %d", code)
+	}
+}
+
+func TestEnvRemoteConfig(t *testing.T) {
+	config := `{"skip": ["integration"]}`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, config)
+	}))
+	defer srv.Close()
+	sum := sha256.Sum256([]byte(config))
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"env", "-config", srv.URL + "/gotag.json", "-config-sha256", hex.EncodeToString(sum[:])},
+		&stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "export GOTAG_SKIP='integration'\n") {
+		t.Errorf("Unexpected output %s", stdout.String())
+	}
+
+	stderr.Reset()
+	code = run([]string{"env", "-config", srv.URL + "/gotag.json", "-config-sha256", strings.Repeat("0", 64)},
+		&stdout, &stderr)
+	if code != 2 || !strings.Contains(stderr.String(), "checksum") {
+		t.Errorf("Expected the mismatching config to be refused, got %d: %s", code, stderr.String())
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/boxtown/gotag"
+)
+
+// ANSI escape sequences of the same length, so
+// that colored table rows stay aligned
+const (
+	colorBold   = "\x1b[01m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorReset  = "\x1b[0m"
+)
+
+// formatter renders the `go test -json` output of go test invocations
+// as a per tag summary. Tests are traced so that their tags are known
+type formatter struct {
+	format string
+	color  bool
+	events bytes.Buffer
+}
+
+// registers the -format flag on the flag set
+func (f *formatter) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.format, "format", "", "output format: pretty, json or quiet, go test output is passed through by default")
+}
+
+// validates the format once flags are parsed, enabling colors
+// if stdout is a terminal and NO_COLOR is not set
+func (f *formatter) init(stdout io.Writer) error {
+	switch f.format {
+	case "", "pretty", "json", "quiet":
+	default:
+		return fmt.Errorf("unknown format '%s'", f.format)
+	}
+	f.color = isTerminal(stdout) && os.Getenv("NO_COLOR") == ""
+	return nil
+}
+
+// reports whether w is a terminal. A variable for tests
+var isTerminal = func(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// returns the go test arguments and environment producing the output of the format
+func (f *formatter) wrap(args, env []string) ([]string, []string) {
+	if f.format == "" {
+		return args, env
+	}
+	return append([]string{"-json"}, args...), append(env, gotag.EnvTrace+"=1")
+}
+
+// returns the writer that go test output is written to, collecting
+// the events of every invocation for formats rendering them
+func (f *formatter) writer(stdout io.Writer) io.Writer {
+	if f.format == "pretty" || f.format == "quiet" {
+		return &f.events
+	}
+	return stdout
+}
+
+// failedTest is a failed test along with its output
+type failedTest struct {
+	name   string
+	output []string
+}
+
+// renders the collected events. The pretty format prints the output of
+// failed tests, then the run, skipped and failed counts and durations
+// of each tag and the totals. The quiet format only prints the output
+// of failed tests
+func (f *formatter) render(stdout io.Writer) error {
+	if f.format != "pretty" && f.format != "quiet" {
+		return nil
+	}
+	failures, passed, skipped, err := readFailures(bytes.NewReader(f.events.Bytes()))
+	if err != nil {
+		return err
+	}
+	for _, fail := range failures {
+		fmt.Fprintf(stdout, "%s--- FAIL: %s%s\n", f.paint(colorRed), fail.name, f.paint(colorReset))
+		for _, line := range fail.output {
+			fmt.Fprint(stdout, line)
+		}
+	}
+	if f.format == "quiet" {
+		return nil
+	}
+
+	// the tests were traced by wrap
+	r, err := readTestEvents(bytes.NewReader(f.events.Bytes()), nil)
+	if err != nil {
+		return err
+	}
+	if summaries := summarize(r, nil); len(summaries) > 0 {
+		w := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintf(w, "%sTAG\tRUN\tSKIPPED\tFAILED\tDURATION%s\n", f.paint(colorBold), f.paint(colorReset))
+		for _, s := range summaries {
+			color := colorGreen
+			if s.Failed > 0 {
+				color = colorRed
+			} else if s.Run == 0 {
+				color = colorYellow
+			}
+			fmt.Fprintf(w, "%s%s\t%d\t%d\t%d\t%s%s\n", f.paint(color), s.Tag, s.Run, s.Skipped, s.Failed, s.Duration, f.paint(colorReset))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	color := colorGreen
+	if len(failures) > 0 {
+		color = colorRed
+	}
+	_, err = fmt.Fprintf(stdout, "%s%d passed, %d skipped, %d failed%s\n", f.paint(color), passed, skipped, len(failures), f.paint(colorReset))
+	return err
+}
+
+// returns the escape sequence if colors are enabled
+func (f *formatter) paint(color string) string {
+	if !f.color {
+		return ""
+	}
+	return color
+}
+
+// reads `go test -json` output, returning the failed tests with their
+// output in the order they ended and the numbers of passed and skipped tests
+func readFailures(r io.Reader) ([]failedTest, int, int, error) {
+	type testKey struct{ pkg, test string }
+	output := make(map[testKey][]string)
+	var failures []failedTest
+	passed, skipped := 0, 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Test == "" {
+			continue
+		}
+		key := testKey{e.Package, e.Test}
+		switch e.Action {
+		case "output":
+			// the tag markers are only traced for the summary
+			if !tagMarker.MatchString(e.Output) {
+				output[key] = append(output[key], e.Output)
+			}
+		case "pass":
+			passed++
+			delete(output, key)
+		case "skip":
+			skipped++
+			delete(output, key)
+		case "fail":
+			failures = append(failures, failedTest{name: e.Package + "." + e.Test, output: output[key]})
+			delete(output, key)
+		}
+	}
+	return failures, passed, skipped, scanner.Err()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+const testFailJSON = `{"Time":"2024-01-01T00:00:00Z","Action":"run","Package":"q","Test":"TestC"}
+{"Time":"2024-01-01T00:00:00Z","Action":"output","Package":"q","Test":"TestC","Output":"    lib.go:1: gotag: tag=\"db\"\n"}
+{"Time":"2024-01-01T00:00:00Z","Action":"output","Package":"q","Test":"TestC","Output":"    c_test.go:9: connection refused\n"}
+{"Time":"2024-01-01T00:00:01Z","Action":"fail","Package":"q","Test":"TestC","Elapsed":1}
+`
+
+func TestTestFormat(t *testing.T) {
+	var got, gotEnv []string
+	defer func(fn func([]string, []string, io.Writer, io.Writer) (int, error)) { goTest = fn }(goTest)
+	goTest = func(args, env []string, stdout, stderr io.Writer) (int, error) {
+		got, gotEnv = args, env
+		io.WriteString(stdout, testJSON+testFailJSON)
+		return 1, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"test", "-format", "pretty", "./..."}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected the go test exit code 1, got %d: %s", code, stderr.String())
+	}
+	if got[0] != "-json" || !contains(gotEnv, "GOTAG_TRACE=1") {
+		t.Errorf("Expected traced json output to be requested, got %v, %v", got, gotEnv)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"--- FAIL: q.TestC\n    c_test.go:9: connection refused\n",
+		"db           1    0        1       1s\n",
+		"integration  1    1        0       2s\n",
+		"2 passed, 1 skipped, 1 failed\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Error("Expected no colors when not writing to a terminal")
+	}
+
+	stdout.Reset()
+	run([]string{"test", "-format", "quiet", "./..."}, &stdout, &stderr)
+	if out := stdout.String(); !strings.HasPrefix(out, "--- FAIL: q.TestC\n") || strings.Contains(out, "passed") {
+		t.Errorf("Expected only failures, got %s", out)
+	}
+
+	if code := run([]string{"test", "-format", "xml", "./..."}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for an unknown format, got %d", code)
+	}
+}
+
+func TestFormatterColor(t *testing.T) {
+	f := formatter{format: "pretty", color: true}
+	f.events.WriteString(testFailJSON)
+	var out bytes.Buffer
+	if err := f.render(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), colorRed+"db ") || !strings.Contains(out.String(), colorRed+"0 passed") {
+		t.Errorf("Expected failures in red, got %q", out.String())
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// generatedHeader marks files written by gotag generate
+const generatedHeader = "// Code generated by gotag generate. DO NOT EDIT."
+
+// directiveFunc is a test function tagged with //gotag: directives
+type directiveFunc struct {
+	Name string
+	Tags []string
+}
+
+// generate writes a file registering the test functions tagged with
+// //gotag: directives with gotag into each package matched by the patterns
+func generate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", "generated_gotag_test.go", "name of the file to write in each package")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !strings.HasSuffix(*out, "_test.go") || strings.ContainsAny(*out, `/\`) {
+		fmt.Fprintf(stderr, "gotag: -o must name a _test.go file, got %s\n", *out)
+		return 2
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		found, files, err := expandPattern(pattern)
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+		for _, file := range files {
+			found = append(found, filepath.Dir(file))
+		}
+		for _, dir := range found {
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, *out)
+		src, bypassed, err := generateDir(dir, *out)
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 1
+		}
+		if bypassed {
+			fmt.Fprintf(stderr, "gotag: the TestMain of %s does not call gotag.Main, registered tests won't be skipped\n", dir)
+		}
+		if src == nil {
+			// remove a stale file left by a previous run
+			if isGenerated(path) {
+				if err := os.Remove(path); err != nil {
+					fmt.Fprintf(stderr, "gotag: %v\n", err)
+					return 1
+				}
+				fmt.Fprintf(stdout, "removed %s\n", path)
+			}
+			continue
+		}
+		if err := ioutil.WriteFile(path, src, 0644); err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "wrote %s\n", path)
+	}
+	return 0
+}
+
+// renders the registering file of the package in dir, or nil if none
+// of its test functions are tagged with directives. Also reports
+// whether the package declares a TestMain that bypasses gotag.Main
+func generateDir(dir, out string) ([]byte, bool, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	if err != nil {
+		return nil, false, err
+	}
+	sort.Strings(files)
+
+	fset := token.NewFileSet()
+	var pkg string
+	var funcs []directiveFunc
+	hasMain, callsMain := false, false
+	for _, file := range files {
+		if filepath.Base(file) == out {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil {
+				continue
+			}
+			if fn.Name.Name == "TestMain" {
+				hasMain, callsMain = true, callsGotagMain(fn)
+				continue
+			}
+			if !isTestName(fn.Name.Name) {
+				continue
+			}
+			if tags := directiveTags(fn.Doc); len(tags) > 0 {
+				if pkg == "" {
+					pkg = f.Name.Name
+				}
+				funcs = append(funcs, directiveFunc{fn.Name.Name, tags})
+			}
+		}
+	}
+	if len(funcs) == 0 {
+		return nil, false, nil
+	}
+	src, err := renderRegistrations(pkg, funcs, !hasMain)
+	return src, hasMain && !callsMain, err
+}
+
+// reports whether fn calls gotag.Main
+func callsGotagMain(fn *ast.FuncDecl) bool {
+	found := false
+	ast.Inspect(fn, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == "Main" {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "gotag" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// renders a test file registering funcs with gotag
+// and declaring a TestMain calling gotag.Main if withMain
+func renderRegistrations(pkg string, funcs []directiveFunc, withMain bool) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n\npackage %s\n\nimport (\n", generatedHeader, pkg)
+	if withMain {
+		b.WriteString("\t\"os\"\n\t\"testing\"\n\n")
+	}
+	b.WriteString("\t\"github.com/boxtown/gotag\"\n)\n\n")
+	b.WriteString("func init() {\n")
+	for _, fn := range funcs {
+		fmt.Fprintf(&b, "\tgotag.Register(%s", strconv.Quote(fn.Name))
+		for _, tag := range fn.Tags {
+			fmt.Fprintf(&b, ", %s", strconv.Quote(tag))
+		}
+		b.WriteString(")\n")
+	}
+	b.WriteString("}\n")
+	if withMain {
+		b.WriteString("\nfunc TestMain(m *testing.M) {\n\tos.Exit(gotag.Main(m))\n}\n")
+	}
+	return format.Source(b.Bytes())
+}
+
+// reports whether the file at path was written by gotag generate
+func isGenerated(path string) bool {
+	data, err := ioutil.ReadFile(path)
+	return err == nil && bytes.HasPrefix(data, []byte(generatedHeader))
+}
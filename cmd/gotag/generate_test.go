@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	src := `package sample
+
+import "testing"
+
+//gotag:integration
+func TestCheckout(t *testing.T) {}
+
+// TestDeploy deploys
+//
+//gotag:end-to-end, slow
+func TestDeploy(t *testing.T) {}
+
+func TestUntagged(t *testing.T) {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "sample_test.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"generate", dir}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	path := filepath.Join(dir, "generated_gotag_test.go")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		generatedHeader,
+		"package sample\n",
+		`gotag.Register("TestCheckout", "integration")`,
+		`gotag.Register("TestDeploy", "end-to-end", "slow")`,
+		"os.Exit(gotag.Main(m))",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected generated file to contain %q, got\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "TestUntagged") {
+		t.Errorf("Expected untagged tests not to be registered, got\n%s", out)
+	}
+
+	funcs, err := scan([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(funcs) != 2 || strings.Join(funcs[1].Tags, ",") != "end-to-end,slow" {
+		t.Errorf("Expected scan to find the directive tags, got %+v", funcs)
+	}
+
+	src = strings.Replace(src, "//gotag:", "// ", -1)
+	if err := os.WriteFile(filepath.Join(dir, "sample_test.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"generate", dir}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Expected the stale generated file to be removed")
+	}
+}
+
+func TestGenerateExistingMain(t *testing.T) {
+	dir := t.TempDir()
+	src := `package sample
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) { os.Exit(m.Run()) }
+
+//gotag:integration
+func TestCheckout(t *testing.T) {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "sample_test.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"generate", dir}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "does not call gotag.Main") {
+		t.Errorf("Expected a warning about TestMain, got %s", stderr.String())
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "generated_gotag_test.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "TestMain") {
+		t.Errorf("Expected no second TestMain, got\n%s", data)
+	}
+}
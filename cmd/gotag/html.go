@@ -0,0 +1,58 @@
+package main
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>gotag report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { padding: 4px 10px; border-bottom: 1px solid #ddd; text-align: left; }
+.pass { color: #2a7d2a; }
+.fail { color: #c0392b; font-weight: bold; }
+.skip { color: #888; }
+.flaky { color: #d68910; }
+details { margin: 0.5em 0; }
+summary { cursor: pointer; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>gotag report</h1>
+<table>
+<tr><th>Tag</th><th>Run</th><th>Skipped</th><th>Failed</th><th>Flaky</th><th>Duration</th></tr>
+{{range .}}<tr>
+<td><a href="#tag-{{.Tag}}">{{.Tag}}</a></td>
+<td>{{.Run}}</td>
+<td class="skip">{{.Skipped}}</td>
+<td{{if .Failed}} class="fail"{{end}}>{{.Failed}}</td>
+<td{{if .Flaky}} class="flaky"{{end}}>{{.Flaky}}</td>
+<td>{{.Duration}}</td>
+</tr>
+{{end}}</table>
+{{range .}}<details id="tag-{{.Tag}}">
+<summary>{{.Tag}}</summary>
+<table>
+<tr><th>Test</th><th>Status</th><th>Duration</th><th>Reason</th><th>History</th></tr>
+{{range .Tests}}<tr>
+<td>{{.Name}}</td>
+<td class="{{.Status}}">{{.Status}}{{if .Flaky}} <span class="flaky">(flaky)</span>{{end}}</td>
+<td>{{.Duration}}</td>
+<td>{{.Reason}}</td>
+<td>{{range .History}}<span class="{{.}}">{{.}}</span> {{end}}</td>
+</tr>
+{{end}}</table>
+</details>
+{{end}}</body>
+</html>
+`))
+
+// writes the summaries as a standalone html page
+func writeHTML(w io.Writer, summaries []tagSummary) error {
+	return htmlReport.Execute(w, summaries)
+}
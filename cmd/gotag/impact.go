@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const defaultImpactFile = ".gotag-impact.json"
+
+// impactDB maps each tag to the source files covered while
+// running the tests under it
+type impactDB struct {
+	Tags map[string][]string `json:"tags"`
+}
+
+// lists the files changed relative to a git ref. A variable so
+// tests need not run git
+var changedFiles = func(ref string) ([]string, error) {
+	out, err := exec.Command("git", "diff", "--name-only", ref+"...HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff %s: %v", ref, err)
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// impact records the files covered by each tag and selects
+// the tags impacted by changes since a git ref
+func impact(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, "usage: gotag impact record|select [arguments]\n")
+		return 2
+	}
+	switch args[0] {
+	case "record":
+		return impactRecord(args[1:], stderr)
+	case "select":
+		return impactSelect(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "gotag: unknown impact command '%s'\n", args[0])
+		return 2
+	}
+}
+
+// impactRecord adds the files covered in a coverprofile, produced by
+// running only the given tag, to the impact database
+func impactRecord(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("impact record", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	db := fs.String("db", defaultImpactFile, "impact database to update")
+	tag := fs.String("tag", "", "tag the coverprofile was recorded under")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *tag == "" || fs.NArg() == 0 {
+		fmt.Fprint(stderr, "usage: gotag impact record -tag <tag> <coverprofile>...\n")
+		return 2
+	}
+
+	d, err := readImpactDB(*db)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 1
+	}
+	covered := make(map[string]bool)
+	for _, file := range d.Tags[*tag] {
+		covered[file] = true
+	}
+	for _, path := range fs.Args() {
+		if err := readCoverProfile(path, covered); err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 1
+		}
+	}
+	files := make([]string, 0, len(covered))
+	for file := range covered {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	d.Tags[*tag] = files
+
+	bytes, err := json.MarshalIndent(d, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(*db, bytes, 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// impactSelect prints the comma separated tags whose covered files
+// changed since the given git ref, suitable for GOTAG_RUN
+func impactSelect(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("impact select", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	db := fs.String("db", defaultImpactFile, "impact database to read")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprint(stderr, "usage: gotag impact select <ref>\n")
+		return 2
+	}
+	d, err := readImpactDB(*db)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 1
+	}
+	changed, err := changedFiles(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, strings.Join(d.impacted(changed), ","))
+	return 0
+}
+
+// returns the sorted tags covering any of the changed files. Coverprofiles
+// name files by import path while git names them relative to the
+// repository root, so files are matched by path suffix
+func (d *impactDB) impacted(changed []string) []string {
+	var tags []string
+	for tag, files := range d.Tags {
+		if coversAny(files, changed) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func coversAny(files, changed []string) bool {
+	for _, file := range files {
+		for _, c := range changed {
+			c = filepath.ToSlash(c)
+			if file == c || strings.HasSuffix(file, "/"+c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func readImpactDB(path string) (*impactDB, error) {
+	d := &impactDB{Tags: make(map[string][]string)}
+	f, err := os.Open(path)
+	if err != nil {
+		return d, err
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(d); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if d.Tags == nil {
+		d.Tags = make(map[string][]string)
+	}
+	return d, nil
+}
+
+// adds the files with at least one executed block in
+// the coverprofile at path to covered
+func readCoverProfile(path string, covered map[string]bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "mode:") {
+			continue
+		}
+		// file.go:line.col,line.col statements count
+		colon := strings.LastIndex(line, ":")
+		space := strings.LastIndex(line, " ")
+		if colon < 0 || space < 0 {
+			continue
+		}
+		if line[space+1:] != "0" {
+			covered[line[:colon]] = true
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testCoverProfile = `mode: set
+github.com/org/repo/db/db.go:10.2,12.3 2 1
+github.com/org/repo/db/db.go:14.2,15.3 1 0
+github.com/org/repo/api/api.go:5.2,6.3 1 0
+`
+
+func TestImpact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gotag")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	profile := filepath.Join(dir, "cover.out")
+	if err := ioutil.WriteFile(profile, []byte(testCoverProfile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	db := filepath.Join(dir, "impact.json")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"impact", "record", "-db", db, "-tag", "integration", profile}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	d, err := readImpactDB(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := d.Tags["integration"]; !reflect.DeepEqual(files, []string{"github.com/org/repo/db/db.go"}) {
+		t.Errorf("Expected only executed files to be recorded, got %v", files)
+	}
+
+	defer func(fn func(string) ([]string, error)) { changedFiles = fn }(changedFiles)
+	changedFiles = func(ref string) ([]string, error) {
+		return []string{"README.md", "db/db.go"}, nil
+	}
+	stdout.Reset()
+	if code := run([]string{"impact", "select", "-db", db, "origin/main"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if out := stdout.String(); out != "integration\n" {
+		t.Errorf("Expected integration to be impacted, got %q", out)
+	}
+
+	changedFiles = func(ref string) ([]string, error) {
+		return []string{"api/api.go"}, nil
+	}
+	stdout.Reset()
+	run([]string{"impact", "select", "-db", db, "origin/main"}, &stdout, &stderr)
+	if out := stdout.String(); out != "\n" {
+		t.Errorf("Expected no impacted tags, got %q", out)
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// prefixes of test names, after Test, that suggest a tag
+var namePatterns = []struct {
+	prefix, tag string
+}{
+	{"Integration", "integration"},
+	{"EndToEnd", "end-to-end"},
+	{"E2E", "end-to-end"},
+	{"Acceptance", "acceptance"},
+	{"Smoke", "smoke"},
+	{"Slow", "slow"},
+}
+
+// tags that are skipped by default in a generated config
+var expensiveTags = map[string]bool{
+	"integration": true,
+	"end-to-end":  true,
+	"acceptance":  true,
+	"slow":        true,
+}
+
+// suggestion is an untagged test whose name suggests a tag
+type suggestion struct {
+	Tag string
+	Ref testRef
+}
+
+// initConfig scans the test files of the repository for tags in use and
+// untagged tests whose names suggest a tag, and writes a starter config
+func initConfig(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	out := fs.String("o", ".gotag.yml", "path of the config file to write, - for stdout")
+	force := fs.Bool("force", false, "overwrite an existing config file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	funcs, err := scan(patterns)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	suggestions, err := suggestTags(patterns, funcs)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	config := starterConfig(tagUsages(funcs), suggestions)
+
+	if *out == "-" {
+		io.WriteString(stdout, config)
+		return 0
+	}
+	if _, err := os.Stat(*out); err == nil && !*force {
+		fmt.Fprintf(stderr, "gotag: %s already exists, use -force to overwrite it\n", *out)
+		return 1
+	}
+	if err := ioutil.WriteFile(*out, []byte(config), 0644); err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "wrote %s\n", *out)
+	return 0
+}
+
+// finds the untagged test functions whose names suggest a tag
+func suggestTags(patterns []string, tagged []testFunc) ([]suggestion, error) {
+	seen := make(map[testRef]bool)
+	for _, fn := range tagged {
+		seen[testRef{File: fn.File, Line: fn.Line, Name: fn.Name}] = true
+	}
+	var files []string
+	for _, pattern := range patterns {
+		dirs, matched, err := expandPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			found, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+			if err != nil {
+				return nil, err
+			}
+			matched = append(matched, found...)
+		}
+		files = append(files, matched...)
+	}
+	sort.Strings(files)
+
+	fset := token.NewFileSet()
+	var suggestions []suggestion
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Test") {
+				continue
+			}
+			ref := testRef{File: file, Line: fset.Position(fn.Pos()).Line, Name: fn.Name.Name}
+			if seen[ref] {
+				continue
+			}
+			rest := strings.TrimPrefix(fn.Name.Name, "Test")
+			for _, p := range namePatterns {
+				if strings.HasPrefix(rest, p.prefix) {
+					suggestions = append(suggestions, suggestion{Tag: p.tag, Ref: ref})
+					break
+				}
+			}
+		}
+	}
+	return suggestions, nil
+}
+
+// renders a commented YAML config skipping the expensive tags in use
+// or suggested and listing every other tag for reference
+func starterConfig(usages []tagUsage, suggestions []suggestion) string {
+	var b bytes.Buffer
+	b.WriteString("# gotag configuration, generated by gotag init. Config files in\n")
+	b.WriteString("# subdirectories add to and override this one.\n")
+
+	if len(usages) > 0 {
+		b.WriteString("#\n# tags used by tests:\n")
+		for _, u := range usages {
+			fmt.Fprintf(&b, "#   %s (%d)\n", u.Tag, u.Count)
+		}
+	}
+	if len(suggestions) > 0 {
+		b.WriteString("#\n# untagged tests that look like they should be tagged:\n")
+		for _, s := range suggestions {
+			fmt.Fprintf(&b, "#   %s: %s (%s:%d)\n", s.Tag, s.Ref.Name, s.Ref.File, s.Ref.Line)
+		}
+	}
+
+	var skip []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		if expensiveTags[tag] && !seen[tag] {
+			seen[tag] = true
+			skip = append(skip, tag)
+		}
+	}
+	for _, u := range usages {
+		add(u.Tag)
+	}
+	for _, s := range suggestions {
+		add(s.Tag)
+	}
+	sort.Strings(skip)
+
+	b.WriteString("\n# tags to skip, override with gotag -only=<tag> or GOTAG_RUN\n")
+	if len(skip) == 0 {
+		b.WriteString("skip: []\n")
+	} else {
+		b.WriteString("skip:\n")
+		for _, tag := range skip {
+			fmt.Fprintf(&b, "  - %s\n", tag)
+		}
+	}
+	b.WriteString("\n# tags to run, causes skip to be ignored\n")
+	b.WriteString("# run: []\n")
+	b.WriteString("\n# skip tags within an edit distance of a skipped tag, catching typos\n")
+	b.WriteString("fuzzy: false\n")
+	b.WriteString("distance: 2\n")
+	b.WriteString("\n# tags that must run, skips are reported by gotag.Main\n")
+	b.WriteString("# must_run: []\n")
+	b.WriteString("\n# registry of tags, listed by gotag list. With require_registered\n")
+	b.WriteString("# tests under any other tag fail\n")
+	if len(usages) == 0 {
+		b.WriteString("# tags: []\n")
+	} else {
+		b.WriteString("tags:\n")
+		for _, u := range usages {
+			fmt.Fprintf(&b, "  - name: %s\n", u.Tag)
+		}
+	}
+	b.WriteString("# require_registered: false\n")
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boxtown/gotag"
+)
+
+func TestInit(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"init", "-o", "-", "./testdata/..."}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"#   integration (2)\n",
+		"#   integration: TestIntegrationLegacy (testdata/sample/sample_test.go:29)\n",
+		"skip:\n  - integration\n  - slow\n",
+		"tags:\n  - name: db\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got\n%s", want, out)
+		}
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".gotag.yml")
+	if err := os.WriteFile(path, stdout.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tc, err := gotag.LoadFrom(dir)
+	if err != nil {
+		t.Fatalf("Expected the generated config to load: %v", err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "integration,slow" {
+		t.Errorf("Unexpected skipped tags %s", tags)
+	}
+	if tags := tc.RegisteredTags(); len(tags) != 5 || tags[0].Name != "db" {
+		t.Errorf("Expected the tags in use to be registered, got %v", tags)
+	}
+}
+
+func TestInitExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gotag.yml")
+	if err := os.WriteFile(path, []byte("skip: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"init", "-o", path, "./testdata/..."}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected exit code 1 for an existing config, got %d", code)
+	}
+	if code := run([]string{"init", "-o", path, "-force", "./testdata/..."}, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected -force to overwrite the config, got %d: %s", code, stderr.String())
+	}
+}
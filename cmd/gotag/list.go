@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/boxtown/gotag"
+)
+
+// tagUsage lists the tests using a tag
+type tagUsage struct {
+	Tag   string    `json:"tag"`
+	Count int       `json:"count"`
+	Tests []testRef `json:"tests"`
+
+	// metadata from the registry of the config, if any
+	Description  string `json:"description,omitempty"`
+	Owner        string `json:"owner,omitempty"`
+	Unregistered bool   `json:"unregistered,omitempty"`
+}
+
+// testRef locates a test function
+type testRef struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Name string `json:"function"`
+}
+
+// loads the tag registry of the config files discovered from the
+// current directory. A variable for tests
+var loadRegistry = func() ([]gotag.Tag, error) {
+	tc, err := gotag.LoadProfile("")
+	if err == gotag.ErrNoConfig {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tc.RegisteredTags(), nil
+}
+
+// list statically scans test files for every tag in use and prints
+// the tests using each of them, along with the description and owner
+// of the tags registered in the config
+func list(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "write JSON instead of text")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	funcs, err := scan(fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	registry, err := loadRegistry()
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	usages := annotateUsages(tagUsages(funcs), registry)
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(usages); err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	for _, u := range usages {
+		fmt.Fprintf(stdout, "%s (%d)%s\n", u.Tag, u.Count, describe(u))
+		for _, ref := range u.Tests {
+			fmt.Fprintf(stdout, "    %s:%d %s\n", ref.File, ref.Line, ref.Name)
+		}
+	}
+	return 0
+}
+
+// groups test functions by tag, sorted by tag
+func tagUsages(funcs []testFunc) []tagUsage {
+	byTag := make(map[string]*tagUsage)
+	for _, fn := range funcs {
+		for _, tag := range fn.Tags {
+			u, ok := byTag[tag]
+			if !ok {
+				u = &tagUsage{Tag: tag}
+				byTag[tag] = u
+			}
+			u.Count++
+			u.Tests = append(u.Tests, testRef{File: fn.File, Line: fn.Line, Name: fn.Name})
+		}
+	}
+	usages := make([]tagUsage, 0, len(byTag))
+	for _, u := range byTag {
+		usages = append(usages, *u)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Tag < usages[j].Tag
+	})
+	return usages
+}
+
+// adds the metadata of registered tags to the usages, adding registered
+// tags no test uses and marking the tags missing from a non-empty
+// registry, sorted by tag. Namespaced tags such as integration.db fall
+// back to the metadata of their namespace
+func annotateUsages(usages []tagUsage, registry []gotag.Tag) []tagUsage {
+	if len(registry) == 0 {
+		return usages
+	}
+	byName := make(map[string]gotag.Tag, len(registry))
+	for _, tag := range registry {
+		byName[tag.Name] = tag
+	}
+	used := make(map[string]bool, len(usages))
+	for i := range usages {
+		u := &usages[i]
+		used[u.Tag] = true
+		tag, ok := lookupTag(byName, u.Tag)
+		u.Description, u.Owner, u.Unregistered = tag.Description, tag.Owner, !ok
+	}
+	for _, tag := range registry {
+		if !used[tag.Name] {
+			usages = append(usages, tagUsage{Tag: tag.Name, Description: tag.Description, Owner: tag.Owner})
+		}
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Tag < usages[j].Tag
+	})
+	return usages
+}
+
+// returns the registered tag of the name or of its nearest namespace
+func lookupTag(byName map[string]gotag.Tag, name string) (gotag.Tag, bool) {
+	for {
+		if tag, ok := byName[name]; ok {
+			return tag, true
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			return gotag.Tag{}, false
+		}
+		name = name[:i]
+	}
+}
+
+// describes the metadata of a tag for the text listing
+func describe(u tagUsage) string {
+	if u.Unregistered {
+		return ": unregistered"
+	}
+	var parts []string
+	if u.Description != "" {
+		parts = append(parts, u.Description)
+	}
+	if u.Owner != "" {
+		parts = append(parts, "owned by "+u.Owner)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(parts, ", ")
+}
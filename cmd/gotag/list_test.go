@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/boxtown/gotag"
+)
+
+func TestList(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"list", "./testdata/..."}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"db (1)\n",
+		"fuzz-long (1)\n",
+		"integration (2)\n",
+		"sample_test.go:11 TestIntegration\n",
+		"postgres (1)\n",
+		"slow (1)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "db (1)") > strings.Index(out, "slow (1)") {
+		t.Errorf("Expected tags to be sorted, got\n%s", out)
+	}
+}
+
+func TestListRegistry(t *testing.T) {
+	defer func(f func() ([]gotag.Tag, error)) { loadRegistry = f }(loadRegistry)
+	loadRegistry = func() ([]gotag.Tag, error) {
+		return []gotag.Tag{
+			{Name: "integration", Description: "needs postgres", Owner: "platform-team"},
+			{Name: "smoke", Owner: "qa"},
+		}, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"list", "./testdata/..."}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"integration (2): needs postgres, owned by platform-team\n",
+		"slow (1): unregistered\n",
+		"smoke (0): owned by qa\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got\n%s", want, out)
+		}
+	}
+}
+
+func TestLookupTag(t *testing.T) {
+	byName := map[string]gotag.Tag{"integration": {Name: "integration", Owner: "platform-team"}}
+	if tag, ok := lookupTag(byName, "integration.db.postgres"); !ok || tag.Owner != "platform-team" {
+		t.Errorf("Expected namespaced tags to fall back to their namespace, got %v", tag)
+	}
+	if _, ok := lookupTag(byName, "integ"); ok {
+		t.Error("Expected an unregistered tag not to be found")
+	}
+}
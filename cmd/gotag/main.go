@@ -0,0 +1,85 @@
+// Command gotag is a companion tool for the gotag testing library
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const usage = `usage: gotag <command> [arguments]
+       gotag [selection flags] [go test arguments]
+
+commands:
+  bench-self  benchmark the gotag matching engine
+  doctor      check the config files against the tags used by tests
+  env         print the effective selection as environment variables
+  generate    register tests tagged with //gotag: directives with gotag
+  init        write a starter .gotag.yml from the tags used by tests
+  impact      record the files covered by tags and select tags impacted by changes
+  list        list the tags used by test files and the tests using them
+  matrix      print a GitHub Actions matrix with a job per tag or tag group
+  report      render a report written by gotag.Main
+  run         run go test for each package with a tag selection
+  stats       show per tag skip rates and duration trends across past runs
+  symbols     list tagged test functions and whether they would be skipped
+  test        run go test, passing through every non gotag argument
+  timings     attribute durations to tags from go test -json output
+  watch       run go test again whenever files change
+
+The exit status is 1 if tests fail and 2 for usage or configuration errors.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// runs the command given by args, returning the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	switch args[0] {
+	case "bench-self":
+		return benchSelf(args[1:], stdout, stderr)
+	case "doctor":
+		return doctor(args[1:], stdout, stderr)
+	case "env":
+		return env(args[1:], stdout, stderr)
+	case "generate":
+		return generate(args[1:], stdout, stderr)
+	case "init":
+		return initConfig(args[1:], stdout, stderr)
+	case "impact":
+		return impact(args[1:], stdout, stderr)
+	case "list":
+		return list(args[1:], stdout, stderr)
+	case "matrix":
+		return matrix(args[1:], stdout, stderr)
+	case "report":
+		return report(args[1:], stdout, stderr)
+	case "run":
+		return runTests(args[1:], stdout, stderr)
+	case "stats":
+		return stats(args[1:], stdout, stderr)
+	case "symbols":
+		return symbols(args[1:], stdout, stderr)
+	case "test":
+		return test(args[1:], stdout, stderr)
+	case "timings":
+		return timings(args[1:], os.Stdin, stdout, stderr)
+	case "watch":
+		return watch(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		if strings.HasPrefix(args[0], "-") {
+			return test(args, stdout, stderr)
+		}
+		fmt.Fprintf(stderr, "gotag: unknown command '%s'\n\n%s", args[0], usage)
+		return 2
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/boxtown/gotag"
+)
+
+// matrixEntry is a job of the matrix, running the tests under its
+// comma separated tags with gotag run -t
+type matrixEntry struct {
+	Name           string `json:"name"`
+	Tags           string `json:"tags"`
+	RunsOn         string `json:"runs-on,omitempty"`
+	TimeoutMinutes int    `json:"timeout-minutes,omitempty"`
+}
+
+// settingsFlag collects key=value settings from repeated flags
+type settingsFlag map[string]string
+
+func (f settingsFlag) String() string {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		keys[i] = key + "=" + f[key]
+	}
+	return strings.Join(keys, ",")
+}
+
+func (f settingsFlag) Set(value string) error {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return fmt.Errorf("expected name=value, got '%s'", value)
+	}
+	f[strings.TrimSpace(value[:i])] = strings.TrimSpace(value[i+1:])
+	return nil
+}
+
+// matrix prints a GitHub Actions matrix with an entry per tag used by
+// the test files matched by the patterns, ./... by default, or per tag
+// group of the config, leaving out tags the selection skips, so that
+// each class of tests runs as its own job
+//
+//	strategy:
+//	  matrix: ${{ fromJSON(needs.plan.outputs.matrix) }}
+func matrix(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("matrix", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var s selection
+	s.register(fs)
+	groupBy := fs.String("group-by", "tag", "matrix entry per tag or per tag group of the config")
+	runsOn := settingsFlag{}
+	timeouts := settingsFlag{}
+	fs.Var(runsOn, "runs-on", "runner of an entry as name=label, repeatable, * for every other entry")
+	fs.Var(timeouts, "timeout", "job timeout of an entry as name=duration, repeatable, * for every other entry")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *groupBy != "tag" && *groupBy != "group" {
+		fmt.Fprintf(stderr, "gotag: invalid -group-by '%s', expected tag or group\n", *groupBy)
+		return 2
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	tc, err := s.resolve(fs)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	funcs, err := scan(patterns)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	var groups map[string][]string
+	if *groupBy == "group" {
+		wd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+		if groups, err = configGroups(wd); err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+	}
+
+	var tags []string
+	for _, u := range tagUsages(funcs) {
+		if skip, _ := tc.WouldSkip(u.Tag); !skip {
+			tags = append(tags, u.Tag)
+		}
+	}
+	entries, err := matrixEntries(tags, groups, runsOn, timeouts)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(stderr, "gotag: no tags to run, the matrix is empty")
+		return 1
+	}
+	// GitHub Actions reads the matrix from a single line output
+	if err := json.NewEncoder(stdout).Encode(struct {
+		Include []matrixEntry `json:"include"`
+	}{entries}); err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// returns the groups of the config files discovered from dir
+func configGroups(dir string) (map[string][]string, error) {
+	paths, err := gotag.ConfigFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var configs []*gotag.Config
+	for _, path := range paths {
+		config, err := gotag.LoadConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	return gotag.MergeConfigs(configs...).Groups, nil
+}
+
+// returns an entry per group holding any of the tags, sorted by name,
+// followed by an entry per tag in no group. A namespaced tag belongs to
+// the group of its parent tag. Runners and timeouts are looked up by
+// entry name, falling back to the * setting
+func matrixEntries(tags []string, groups map[string][]string, runsOn, timeouts map[string]string) ([]matrixEntry, error) {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	grouped := make(map[string]bool)
+	var entries []matrixEntry
+	for _, name := range names {
+		var members []string
+		for _, tag := range tags {
+			if inGroup(tag, groups[name]) {
+				members = append(members, tag)
+				grouped[tag] = true
+			}
+		}
+		if len(members) > 0 {
+			entries = append(entries, matrixEntry{Name: name, Tags: strings.Join(members, ",")})
+		}
+	}
+	for _, tag := range tags {
+		if !grouped[tag] {
+			entries = append(entries, matrixEntry{Name: tag, Tags: tag})
+		}
+	}
+
+	for i := range entries {
+		e := &entries[i]
+		e.RunsOn = setting(runsOn, e.Name)
+		if v := setting(timeouts, e.Name); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid timeout '%s' for %s", v, e.Name)
+			}
+			e.TimeoutMinutes = int(math.Ceil(d.Minutes()))
+		}
+	}
+	return entries, nil
+}
+
+func inGroup(tag string, members []string) bool {
+	for _, member := range members {
+		if tag == member || strings.HasPrefix(tag, member+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the setting of the name, or the * setting
+func setting(settings map[string]string, name string) string {
+	if v, ok := settings[name]; ok {
+		return v
+	}
+	return settings["*"]
+}
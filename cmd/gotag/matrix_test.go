@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatrixEntries(t *testing.T) {
+	groups := map[string][]string{
+		"storage": {"db", "postgres"},
+		"empty":   {"unused"},
+	}
+	runsOn := map[string]string{"storage": "ubuntu-latest-8-cores", "*": "ubuntu-latest"}
+	timeouts := map[string]string{"storage": "45m", "slow": "90s"}
+	entries, err := matrixEntries([]string{"db.migrations", "integration", "postgres", "slow"}, groups, runsOn, timeouts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []matrixEntry{
+		{Name: "storage", Tags: "db.migrations,postgres", RunsOn: "ubuntu-latest-8-cores", TimeoutMinutes: 45},
+		{Name: "integration", Tags: "integration", RunsOn: "ubuntu-latest"},
+		{Name: "slow", Tags: "slow", RunsOn: "ubuntu-latest", TimeoutMinutes: 2},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %+v, got %+v", want, entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("Expected entry %+v, got %+v", want[i], entries[i])
+		}
+	}
+
+	if _, err := matrixEntries([]string{"slow"}, nil, nil, map[string]string{"*": "soon"}); err == nil {
+		t.Error("Expected an error for an invalid timeout")
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	t.Setenv("GOTAG_USER_CONFIG", "off")
+	sample, err := filepath.Abs(filepath.Join("testdata", "sample"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := "skip: [fuzz-long]\ngroups:\n  storage: [db, postgres]\n"
+	if err := ioutil.WriteFile(filepath.Join(root, ".gotag.yml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"matrix", "-group-by", "group", "-timeout", "*=10m", sample}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	var m struct {
+		Include []matrixEntry `json:"include"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	names := make(map[string]string)
+	for _, e := range m.Include {
+		names[e.Name] = e.Tags
+		if e.TimeoutMinutes != 10 {
+			t.Errorf("Expected a 10 minute timeout, got %+v", e)
+		}
+	}
+	if names["storage"] != "db,postgres" || names["integration"] != "integration" || names["slow"] != "slow" {
+		t.Errorf("Unexpected matrix %s", stdout.String())
+	}
+	if _, ok := names["fuzz-long"]; ok {
+		t.Errorf("Expected the skipped tag to be left out, got %s", stdout.String())
+	}
+
+	if code := run([]string{"matrix", "-group-by", "owner", sample}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for an invalid -group-by, got %d", code)
+	}
+}
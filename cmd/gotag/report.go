@@ -0,0 +1,274 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"time"
+)
+
+// runReport is the JSON report written by gotag.Main. Merged
+// reports also carry the per tag totals of their decisions
+type runReport struct {
+	Decisions []testDecision `json:"decisions"`
+	Tags      []tagTotals    `json:"tags,omitempty"`
+}
+
+// tagTotals holds the counts and total duration of a tag
+type tagTotals struct {
+	Tag      string        `json:"tag"`
+	Run      int           `json:"run"`
+	Skipped  int           `json:"skipped"`
+	Failed   int           `json:"failed"`
+	Duration time.Duration `json:"duration"`
+}
+
+type testDecision struct {
+	Tag      string        `json:"tag"`
+	Test     string        `json:"test"`
+	Skipped  bool          `json:"skipped"`
+	Reason   string        `json:"reason"`
+	Failed   bool          `json:"failed"`
+	Duration time.Duration `json:"duration"`
+}
+
+func (d testDecision) status() string {
+	switch {
+	case d.Skipped:
+		return "skip"
+	case d.Failed:
+		return "fail"
+	default:
+		return "pass"
+	}
+}
+
+// tagSummary aggregates the tests run under a single tag
+type tagSummary struct {
+	Tag      string
+	Run      int
+	Skipped  int
+	Failed   int
+	Flaky    int
+	Duration time.Duration
+	Tests    []testSummary
+}
+
+// testSummary holds the outcome of a single test along with its
+// outcomes in previous runs, oldest first
+type testSummary struct {
+	Name     string
+	Status   string
+	Reason   string
+	Duration time.Duration
+	History  []string
+	Flaky    bool
+}
+
+// report renders a report written by gotag.Main. Reports of previous
+// runs may follow the current report to include flaky test history
+func report(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && args[0] == "merge" {
+		return mergeReports(args[1:], stdout, stderr)
+	}
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "html", "output format: html")
+	output := fs.String("o", "", "write the report to a file instead of stdout")
+	badge := fs.String("badge", "", "write a shields.io endpoint badge to a file, skipping the report unless -o is set")
+	badgeTag := fs.String("badge-tag", "", "tag to describe in the badge instead of all tags")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: gotag report [-format html] [-o file] [-badge file] report.json [previous.json ...]")
+		return 2
+	}
+
+	reports := make([]*runReport, fs.NArg())
+	for i, path := range fs.Args() {
+		r, err := readReport(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+		reports[i] = r
+	}
+	summaries := summarize(reports[0], reports[1:])
+
+	if *badge != "" {
+		if err := writeBadge(*badge, summaries, *badgeTag); err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 1
+		}
+		if *output == "" {
+			return 0
+		}
+	}
+
+	w := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+		defer f.Close()
+		w = f
+	}
+
+	var err error
+	switch *format {
+	case "html":
+		err = writeHTML(w, summaries)
+	default:
+		fmt.Fprintf(stderr, "gotag: unknown report format '%s'\n", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func readReport(path string) (*runReport, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r runReport
+	if err := json.Unmarshal(bytes, &r); err != nil {
+		return nil, fmt.Errorf("Could not parse report %s: %v", path, err)
+	}
+	return &r, nil
+}
+
+// aggregates the current report per tag, sorted by tag. Previous
+// reports are given newest first and provide each test's history
+func summarize(current *runReport, previous []*runReport) []tagSummary {
+	history := make(map[string][]string)
+	for i := len(previous) - 1; i >= 0; i-- {
+		for _, d := range previous[i].Decisions {
+			key := d.Tag + "\x00" + d.Test
+			history[key] = append(history[key], d.status())
+		}
+	}
+
+	byTag := make(map[string]*tagSummary)
+	for _, d := range current.Decisions {
+		s, ok := byTag[d.Tag]
+		if !ok {
+			s = &tagSummary{Tag: d.Tag}
+			byTag[d.Tag] = s
+		}
+		test := testSummary{
+			Name:     d.Test,
+			Status:   d.status(),
+			Reason:   d.Reason,
+			Duration: d.Duration,
+			History:  history[d.Tag+"\x00"+d.Test],
+		}
+		test.Flaky = flaky(append(test.History, test.Status))
+
+		switch test.Status {
+		case "skip":
+			s.Skipped++
+		case "fail":
+			s.Run++
+			s.Failed++
+		default:
+			s.Run++
+		}
+		if test.Flaky {
+			s.Flaky++
+		}
+		s.Duration += d.Duration
+		s.Tests = append(s.Tests, test)
+	}
+
+	summaries := make([]tagSummary, 0, len(byTag))
+	for _, s := range byTag {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Tag < summaries[j].Tag
+	})
+	return summaries
+}
+
+// a test is flaky if it has both passed and failed
+func flaky(statuses []string) bool {
+	passed, failed := false, false
+	for _, s := range statuses {
+		passed = passed || s == "pass"
+		failed = failed || s == "fail"
+	}
+	return passed && failed
+}
+
+// mergeReports combines the reports of several modules or CI shards
+// into one, summing the counts and durations of each tag
+func mergeReports(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("report merge", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	output := fs.String("o", "", "write the merged report to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: gotag report merge [-o file] report.json ...")
+		return 2
+	}
+
+	reports := make([]*runReport, fs.NArg())
+	for i, path := range fs.Args() {
+		r, err := readReport(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+		reports[i] = r
+	}
+	merged := merge(reports)
+
+	w := stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(merged); err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// concatenates the decisions of the reports and totals them per tag
+func merge(reports []*runReport) *runReport {
+	merged := &runReport{}
+	for _, r := range reports {
+		merged.Decisions = append(merged.Decisions, r.Decisions...)
+	}
+	for _, s := range summarize(merged, nil) {
+		merged.Tags = append(merged.Tags, tagTotals{
+			Tag:      s.Tag,
+			Run:      s.Run,
+			Skipped:  s.Skipped,
+			Failed:   s.Failed,
+			Duration: s.Duration,
+		})
+	}
+	return merged
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	current := &runReport{Decisions: []testDecision{
+		{Tag: "integration", Test: "TestA", Failed: true},
+		{Tag: "integration", Test: "TestB"},
+		{Tag: "end-to-end", Test: "TestC", Skipped: true, Reason: "in skip list"},
+	}}
+	previous := []*runReport{
+		{Decisions: []testDecision{{Tag: "integration", Test: "TestA"}}},
+	}
+
+	summaries := summarize(current, previous)
+	if len(summaries) != 2 || summaries[0].Tag != "end-to-end" {
+		t.Fatalf("Unexpected summaries %+v", summaries)
+	}
+	integration := summaries[1]
+	if integration.Run != 2 || integration.Failed != 1 || integration.Flaky != 1 {
+		t.Errorf("Unexpected integration summary %+v", integration)
+	}
+	if summaries[0].Skipped != 1 {
+		t.Errorf("Unexpected end-to-end summary %+v", summaries[0])
+	}
+}
+
+func TestReportHTML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	data, _ := json.Marshal(runReport{Decisions: []testDecision{
+		{Tag: "integration", Test: "TestA"},
+	}})
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"report", "--format", "html", path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "TestA") {
+		t.Error("Expected the report to include the test")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := &runReport{Decisions: []testDecision{
+		{Tag: "integration", Test: "TestA", Duration: time.Second},
+		{Tag: "unit", Test: "TestB", Skipped: true},
+	}}
+	b := &runReport{Decisions: []testDecision{
+		{Tag: "integration", Test: "TestC", Duration: 2 * time.Second, Failed: true},
+	}}
+
+	merged := merge([]*runReport{a, b})
+	if len(merged.Decisions) != 3 || len(merged.Tags) != 2 {
+		t.Fatalf("Unexpected merged report %+v", merged)
+	}
+	integration := merged.Tags[0]
+	if integration.Tag != "integration" || integration.Run != 2 || integration.Failed != 1 ||
+		integration.Duration != 3*time.Second {
+		t.Errorf("Unexpected integration totals %+v", integration)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// lists the import paths of the packages matching the patterns.
+// A variable so tests need not build packages
+var goList = func(patterns []string) ([]string, error) {
+	out, err := exec.Command("go", append([]string{"list"}, patterns...)...).Output()
+	if exit, ok := err.(*exec.ExitError); ok {
+		return nil, fmt.Errorf("go list: %s", strings.TrimSpace(string(exit.Stderr)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(out)), nil
+}
+
+// runTests runs go test once per package matching the package patterns
+// with the selection given by the gotag flags, -t and -x being short for
+// -only and -skip. Arguments following -- are passed to every go test
+// invocation. Returns the highest exit code of the packages, so that
+// any failing package fails the run, or 2 if the selection is malformed.
+// With -q only the output of failing packages is printed, and the
+// output of every package is rendered together as given by -format.
+// With -changed only the packages affected by changes since the given
+// git ref are tested. With -coverprofile each tag to run is tested in
+// a separate pass and the coverage profiles of every pass and package
+// are merged into a single file
+func runTests(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var s selection
+	s.registerRunAs(fs, "only")
+	var f formatter
+	f.register(fs)
+	quiet := fs.Bool("q", false, "only print the output of failing packages")
+	changed := fs.String("changed", "", "only test packages affected by changes since the git ref")
+	cover := fs.String("coverprofile", "", "run each tag given by -t in a separate pass and merge their coverage profiles into the file")
+	fs.Var(&s.run, "t", "short for -only")
+	fs.Var(&s.skip, "x", "short for -skip")
+	var goArgs []string
+	for i, arg := range args {
+		if arg == "--" {
+			args, goArgs = args[:i], args[i+1:]
+			break
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	tc, err := s.resolve(fs)
+	if err == nil {
+		err = f.init(stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	var pkgs []string
+	if *changed != "" {
+		pkgs, err = affectedPackages(patterns, *changed)
+	} else {
+		pkgs, err = goList(patterns)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	if len(pkgs) == 0 && *changed != "" {
+		fmt.Fprintf(stderr, "gotag: no packages affected by changes since %s\n", *changed)
+		return 0
+	}
+
+	passes := []pass{{tc: tc}}
+	var merged *coverProfile
+	if *cover != "" {
+		dir, err := ioutil.TempDir("", "gotag-cover")
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+		defer os.RemoveAll(dir)
+		passes, merged = coverPasses(tc, s.run), newCoverProfile(dir)
+	}
+	tester := goTest
+	if *quiet {
+		tester = quietTest
+	}
+	code := 0
+	var failed []string
+	for _, p := range passes {
+		passArgs, env := f.wrap(goArgs, s.environ(p.tc))
+		for _, pkg := range pkgs {
+			args := append([]string(nil), passArgs...)
+			if merged != nil {
+				args = append(args, "-coverprofile="+merged.next())
+			}
+			c, err := tester(append(args, pkg), env, f.writer(stdout), stderr)
+			if err == nil && merged != nil {
+				err = merged.addLast()
+			}
+			if err != nil {
+				fmt.Fprintf(stderr, "gotag: %v\n", err)
+				return 2
+			}
+			if c != 0 {
+				failed = append(failed, p.label(pkg))
+			}
+			if c > code {
+				code = c
+			}
+		}
+	}
+	if err := f.render(stdout); err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	if merged != nil {
+		if err := merged.write(*cover); err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+	}
+	if len(failed) > 0 && !*quiet {
+		fmt.Fprintf(stderr, "gotag: %d of %d packages failed: %s\n", len(failed), len(pkgs)*len(passes), strings.Join(failed, ", "))
+	}
+	return code
+}
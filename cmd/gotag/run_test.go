@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunTests(t *testing.T) {
+	var calls [][]string
+	var gotEnv []string
+	defer func(fn func([]string, []string, io.Writer, io.Writer) (int, error)) { goTest = fn }(goTest)
+	goTest = func(args, env []string, stdout, stderr io.Writer) (int, error) {
+		calls = append(calls, args)
+		gotEnv = env
+		if args[len(args)-1] == "example.com/b" {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	defer func(fn func([]string) ([]string, error)) { goList = fn }(goList)
+	goList = func(patterns []string) ([]string, error) {
+		if !reflect.DeepEqual(patterns, []string{"./pkg/..."}) {
+			t.Errorf("Unexpected patterns %v", patterns)
+		}
+		return []string{"example.com/a", "example.com/b", "example.com/c"}, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"run", "-t", "integration", "-x", "slow", "./pkg/...", "--", "-v", "-race"}, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("Expected exit code 1 for a failing package, got %d", code)
+	}
+	want := [][]string{
+		{"-v", "-race", "example.com/a"},
+		{"-v", "-race", "example.com/b"},
+		{"-v", "-race", "example.com/c"},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Expected go test invocations %v, got %v", want, calls)
+	}
+	if !contains(gotEnv, "GOTAG_RUN=integration") || !contains(gotEnv, "GOTAG_SKIP=slow") {
+		t.Errorf("Expected the selection in the environment, got %v", gotEnv)
+	}
+	if !strings.Contains(stderr.String(), "1 of 3 packages failed: example.com/b") {
+		t.Errorf("Expected a summary of failures, got %s", stderr.String())
+	}
+}
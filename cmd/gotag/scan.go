@@ -0,0 +1,291 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// testFunc is a test or benchmark function and the
+// gotag tags used within it
+type testFunc struct {
+	File string   `json:"file"`
+	Line int      `json:"line"`
+	Name string   `json:"function"`
+	Tags []string `json:"tags"`
+
+	// the tags of each Test or Benchmark call
+	calls [][]string
+}
+
+// directive prefixes the comment tagging the function it documents
+const directive = "//gotag:"
+
+// the tag constants exported by gotag
+var builtinTags = map[string]string{
+	"Integration": "integration",
+	"EndToEnd":    "end-to-end",
+}
+
+// statically scans the test files matched by the patterns for calls to
+// gotag's Test and Benchmark functions. A pattern is a file, a directory
+// or a directory followed by /... to include its subdirectories
+func scan(patterns []string) ([]testFunc, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	var funcs []testFunc
+	for _, pattern := range patterns {
+		dirs, files, err := expandPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			matches, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, matches...)
+		}
+		found, err := scanFiles(files)
+		if err != nil {
+			return nil, err
+		}
+		funcs = append(funcs, found...)
+	}
+	return funcs, nil
+}
+
+// expands a pattern into directories and files
+func expandPattern(pattern string) ([]string, []string, error) {
+	if strings.HasSuffix(pattern, "/...") || pattern == "..." {
+		root := strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+		if root == "" {
+			root = "."
+		}
+		var dirs []string
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return nil
+			}
+			name := info.Name()
+			if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		})
+		return dirs, nil, err
+	}
+	info, err := os.Stat(pattern)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return []string{pattern}, nil, nil
+	}
+	return nil, []string{pattern}, nil
+}
+
+// parses the files, resolving tags given as string constants
+// declared in any of the files of the same directory
+func scanFiles(files []string) ([]testFunc, error) {
+	fset := token.NewFileSet()
+	parsed := make(map[string]*ast.File)
+	consts := make(map[string]map[string]string)
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		parsed[file] = f
+		dir := filepath.Dir(file)
+		if consts[dir] == nil {
+			consts[dir] = make(map[string]string)
+		}
+		collectConsts(f, consts[dir])
+	}
+
+	sort.Strings(files)
+	var funcs []testFunc
+	for _, file := range files {
+		f := parsed[file]
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Body == nil || !isTestName(fn.Name.Name) {
+				continue
+			}
+			calls := findCalls(fn.Body, consts[filepath.Dir(file)])
+			if tags := directiveTags(fn.Doc); len(tags) > 0 {
+				calls = append([][]string{tags}, calls...)
+			}
+			if len(calls) == 0 {
+				continue
+			}
+			funcs = append(funcs, testFunc{
+				File:  file,
+				Line:  fset.Position(fn.Pos()).Line,
+				Name:  fn.Name.Name,
+				Tags:  uniqueTags(calls),
+				calls: calls,
+			})
+		}
+	}
+	return funcs, nil
+}
+
+func isTestName(name string) bool {
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz"} {
+		if strings.HasPrefix(name, prefix) && name != "TestMain" {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the tags of the //gotag: directives of a doc comment. A
+// directive holds one or more comma separated tags, e.g.
+// //gotag:integration,slow
+func directiveTags(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+	var tags []string
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, directive) {
+			continue
+		}
+		for _, tag := range strings.Split(c.Text[len(directive):], ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// records top level string constants
+func collectConsts(f *ast.File, consts map[string]string) {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i >= len(vs.Values) {
+					continue
+				}
+				if lit, ok := vs.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					if value, err := strconv.Unquote(lit.Value); err == nil {
+						consts[name.Name] = value
+					}
+				}
+			}
+		}
+	}
+}
+
+// finds the tags passed to each Test, TestTags, TestFor, Run,
+// Benchmark, BenchmarkTags, Fuzz and Tagged call within a function body
+func findCalls(body ast.Node, consts map[string]string) [][]string {
+	var calls [][]string
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) < 3 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		switch sel.Sel.Name {
+		case "Test", "Benchmark", "Fuzz", "Tagged", "TestFor":
+			if tag, ok := resolveTag(call.Args[0], consts); ok {
+				calls = append(calls, []string{tag})
+			}
+		case "Run":
+			// gotag's Run takes a tag before the arguments of t.Run
+			if len(call.Args) != 4 {
+				break
+			}
+			if tag, ok := resolveTag(call.Args[0], consts); ok {
+				calls = append(calls, []string{tag})
+			}
+		case "TestForContext":
+			if tag, ok := resolveTag(call.Args[1], consts); ok {
+				calls = append(calls, []string{tag})
+			}
+		case "TestTags", "BenchmarkTags":
+			if tags, ok := resolveTags(call.Args[0], consts); ok {
+				calls = append(calls, tags)
+			}
+		}
+		return true
+	})
+	return calls
+}
+
+// resolves a []string composite literal of tags
+func resolveTags(expr ast.Expr, consts map[string]string) ([]string, bool) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+	tags := make([]string, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		tag, ok := resolveTag(elt, consts)
+		if !ok {
+			return nil, false
+		}
+		tags = append(tags, tag)
+	}
+	return tags, true
+}
+
+// returns the tags of every call in order of first use
+func uniqueTags(calls [][]string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, call := range calls {
+		for _, tag := range call {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// resolves a tag expression to a string if it is a literal,
+// a constant of the package or a constant exported by gotag
+func resolveTag(expr ast.Expr, consts map[string]string) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.Ident:
+		value, ok := consts[e.Name]
+		return value, ok
+	case *ast.SelectorExpr:
+		if pkg, ok := e.X.(*ast.Ident); ok && pkg.Name == "gotag" {
+			value, ok := builtinTags[e.Sel.Name]
+			return value, ok
+		}
+	}
+	return "", false
+}
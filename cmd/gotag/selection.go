@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/boxtown/gotag"
+)
+
+// selection holds the tag selection flags shared by commands
+type selection struct {
+	skip     tagsFlag
+	run      tagsFlag
+	owners   tagsFlag
+	fuzzy    bool
+	distance int
+	selector string
+	profile  string
+	config   string
+	pin      string
+	set      map[string]bool
+}
+
+// tagsFlag collects comma separated tags from repeated flags
+type tagsFlag []string
+
+func (f *tagsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *tagsFlag) Set(value string) error {
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			*f = append(*f, tag)
+		}
+	}
+	return nil
+}
+
+// registers the selection flags on the flag set
+func (s *selection) register(fs *flag.FlagSet) {
+	s.registerRunAs(fs, "run")
+}
+
+// registers the selection flags on the flag set, naming the flag for
+// tags to run differently for commands that pass -run through to go test
+func (s *selection) registerRunAs(fs *flag.FlagSet, run string) {
+	fs.Var(&s.skip, "skip", "comma separated list of tags to skip")
+	fs.Var(&s.run, run, "comma separated list of tags to run, causes skipped tags to be ignored")
+	fs.Var(&s.owners, "owner", "comma separated list of owners whose registered tags are run")
+	fs.BoolVar(&s.fuzzy, "fuzzy", false, "enable fuzzy matching of tags")
+	fs.IntVar(&s.distance, "distance", 2, "maximum edit distance for fuzzy matching")
+	fs.StringVar(&s.selector, "selector", "", "label selector, e.g. 'speed!=slow, requires in (db)'")
+	fs.StringVar(&s.profile, "profile", "", "config profile to apply, overriding GOTAG_PROFILE")
+	fs.StringVar(&s.config, "config", "", "http or https URL of a config to load instead of the config files")
+	fs.StringVar(&s.pin, "config-sha256", "", "hex encoded SHA-256 that the content of -config must hash to")
+}
+
+// resolves the effective selection from the config files discovered
+// from the current directory, with the profile applied, GOTAG_*
+// environment variables and the flags, in increasing order of
+// precedence. A config given by -config is fetched in place of the
+// config files. Must be called after the flag set is parsed
+func (s *selection) resolve(fs *flag.FlagSet) (*gotag.TestContext, error) {
+	tc, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	if err := tc.LoadEnv(); err != nil {
+		return nil, err
+	}
+
+	s.set = make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		s.set[f.Name] = true
+	})
+	tc.Skip(s.skip...)
+	tc.RunOnly(s.run...)
+	if len(s.owners) > 0 {
+		owned := tc.OwnedTags(s.owners...)
+		if len(owned) == 0 {
+			return nil, fmt.Errorf("no registered tags are owned by %s", strings.Join(s.owners, ", "))
+		}
+		tc.RunOnly(owned...)
+	}
+	if s.set["fuzzy"] {
+		tc.Fuzzy = s.fuzzy
+	}
+	if s.set["distance"] {
+		tc.EditDistance = s.distance
+	}
+	if s.selector != "" {
+		sel, err := gotag.ParseSelector(s.selector)
+		if err != nil {
+			return nil, err
+		}
+		tc.Select(sel)
+	}
+	return tc, nil
+}
+
+// loads the context from the config given by -config, verified against
+// -config-sha256, or else from the config files
+func (s *selection) load() (*gotag.TestContext, error) {
+	if s.config == "" {
+		if s.pin != "" {
+			return nil, fmt.Errorf("-config-sha256 requires -config")
+		}
+		tc, err := gotag.LoadProfile(s.profile)
+		if err == gotag.ErrNoConfig && s.profile == "" {
+			return gotag.New(), nil
+		}
+		return tc, err
+	}
+	if s.profile != "" {
+		return nil, fmt.Errorf("-profile cannot be combined with -config")
+	}
+	return gotag.LoadFromURL(s.config, s.pin)
+}
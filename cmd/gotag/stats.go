@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// pastRun holds the per tag totals of a past run, read from a report
+// written by gotag.Main or a decision log
+type pastRun struct {
+	Time time.Time
+	Tags map[string]*tagTotals
+}
+
+// loggedDecision is a line of a decision log
+type loggedDecision struct {
+	Time    time.Time `json:"time"`
+	Tags    []string  `json:"tags"`
+	Tag     string    `json:"tag"`
+	Skipped bool      `json:"skipped"`
+}
+
+// tagStats holds the totals of a tag across past runs. Trend is the
+// change in percent of the average duration of the tests that ran
+// between the older and the newer half of the runs, nil if unknown
+type tagStats struct {
+	Tag         string        `json:"tag"`
+	Runs        int           `json:"runs"`
+	Run         int           `json:"run"`
+	Skipped     int           `json:"skipped"`
+	Failed      int           `json:"failed"`
+	SkipRate    float64       `json:"skip_rate"`
+	AvgDuration time.Duration `json:"avg_duration"`
+	Trend       *float64      `json:"trend,omitempty"`
+	Note        string        `json:"note,omitempty"`
+}
+
+// stats reads past reports and decision logs, one per run, and prints
+// per tag run counts, skip rates, average durations and their trend,
+// noting tags that are always skipped or growing slower
+func stats(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "write the stats as JSON instead of a table")
+	slower := fs.Float64("slower", 20, "percent increase of the average duration noted as growing slower")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: gotag stats [-json] [-slower percent] report.json|decisions.jsonl ...")
+		return 2
+	}
+
+	runs := make([]*pastRun, fs.NArg())
+	for i, path := range fs.Args() {
+		r, err := readPastRun(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+		runs[i] = r
+	}
+	tags := tagHistory(runs, *slower)
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(tags); err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	w := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tRUNS\tRUN\tSKIPPED\tFAILED\tSKIP RATE\tAVG DURATION\tTREND\tNOTE")
+	for _, s := range tags {
+		avg, trend := "-", "-"
+		if s.AvgDuration > 0 {
+			avg = s.AvgDuration.String()
+		}
+		if s.Trend != nil {
+			trend = fmt.Sprintf("%+.0f%%", *s.Trend)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%.0f%%\t%s\t%s\t%s\n", s.Tag, s.Runs, s.Run, s.Skipped, s.Failed,
+			s.SkipRate*100, avg, trend, s.Note)
+	}
+	w.Flush()
+	return 0
+}
+
+// reads a report written by gotag.Main or a decision log. Reports
+// don't record when they ran so the modification time of the file is
+// used, while decision logs ran at the time of their first decision
+func readPastRun(path string) (*pastRun, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report struct {
+		Decisions *[]testDecision `json:"decisions"`
+	}
+	if json.Unmarshal(data, &report) == nil && report.Decisions != nil {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		run := &pastRun{Time: info.ModTime(), Tags: make(map[string]*tagTotals)}
+		for _, s := range summarize(&runReport{Decisions: *report.Decisions}, nil) {
+			run.Tags[s.Tag] = &tagTotals{Tag: s.Tag, Run: s.Run, Skipped: s.Skipped, Failed: s.Failed, Duration: s.Duration}
+		}
+		return run, nil
+	}
+
+	run := &pastRun{Tags: make(map[string]*tagTotals)}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var d loggedDecision
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			return nil, fmt.Errorf("Could not parse decision log %s, line %d: %v", path, n, err)
+		}
+		if run.Time.IsZero() || d.Time.Before(run.Time) {
+			run.Time = d.Time
+		}
+		tags := d.Tags
+		if len(tags) == 0 && d.Tag != "" {
+			tags = []string{d.Tag}
+		}
+		// tests with several tags count under each of them
+		for _, tag := range tags {
+			t, ok := run.Tags[tag]
+			if !ok {
+				t = &tagTotals{Tag: tag}
+				run.Tags[tag] = t
+			}
+			if d.Skipped {
+				t.Skipped++
+			} else {
+				t.Run++
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// totals the runs per tag, sorted by tag. A tag is noted as always
+// skipped if none of its tests ran in any run, and as slower if the
+// average duration of its tests grew by at least slower percent
+func tagHistory(runs []*pastRun, slower float64) []tagStats {
+	sorted := append([]*pastRun(nil), runs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	byTag := make(map[string]*tagStats)
+	history := make(map[string][]*tagTotals)
+	duration := make(map[string]time.Duration)
+	for _, r := range sorted {
+		for tag, t := range r.Tags {
+			s, ok := byTag[tag]
+			if !ok {
+				s = &tagStats{Tag: tag}
+				byTag[tag] = s
+			}
+			s.Runs++
+			s.Run += t.Run
+			s.Skipped += t.Skipped
+			s.Failed += t.Failed
+			duration[tag] += t.Duration
+			history[tag] = append(history[tag], t)
+		}
+	}
+
+	tags := make([]tagStats, 0, len(byTag))
+	for tag, s := range byTag {
+		if total := s.Run + s.Skipped; total > 0 {
+			s.SkipRate = float64(s.Skipped) / float64(total)
+		}
+		if s.Run > 0 {
+			s.AvgDuration = duration[tag] / time.Duration(s.Run)
+		}
+		s.Trend = durationTrend(history[tag])
+		switch {
+		case s.Run == 0 && s.Skipped > 0:
+			s.Note = "always skipped"
+		case s.Trend != nil && *s.Trend >= slower:
+			s.Note = "slower"
+		}
+		tags = append(tags, *s)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags
+}
+
+// returns the change in percent of the average duration of the tests
+// that ran between the older and the newer half of the runs holding
+// durations, oldest first, or nil if fewer than two runs hold any
+func durationTrend(history []*tagTotals) *float64 {
+	var timed []*tagTotals
+	for _, t := range history {
+		if t.Run > 0 && t.Duration > 0 {
+			timed = append(timed, t)
+		}
+	}
+	if len(timed) < 2 {
+		return nil
+	}
+	average := func(runs []*tagTotals) float64 {
+		var d time.Duration
+		n := 0
+		for _, t := range runs {
+			d += t.Duration
+			n += t.Run
+		}
+		return float64(d) / float64(n)
+	}
+	half := len(timed) / 2
+	older, newer := average(timed[:half]), average(timed[len(timed)-half:])
+	trend := (newer - older) / older * 100
+	return &trend
+}
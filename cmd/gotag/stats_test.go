@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTagHistory(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	runs := []*pastRun{
+		{Time: day.Add(48 * time.Hour), Tags: map[string]*tagTotals{
+			"integration": {Run: 2, Duration: 6 * time.Second},
+			"manual":      {Skipped: 1},
+		}},
+		{Time: day, Tags: map[string]*tagTotals{
+			"integration": {Run: 2, Skipped: 2, Failed: 1, Duration: 2 * time.Second},
+			"manual":      {Skipped: 1},
+		}},
+		{Time: day.Add(24 * time.Hour), Tags: map[string]*tagTotals{
+			"integration": {Run: 1, Duration: 4 * time.Second},
+		}},
+	}
+
+	tags := tagHistory(runs, 20)
+	if len(tags) != 2 {
+		t.Fatalf("Unexpected stats %+v", tags)
+	}
+	integration, manual := tags[0], tags[1]
+	if integration.Runs != 3 || integration.Run != 5 || integration.Skipped != 2 || integration.Failed != 1 {
+		t.Errorf("Unexpected integration stats %+v", integration)
+	}
+	if integration.AvgDuration != 2400*time.Millisecond {
+		t.Errorf("Expected an average of 2.4s, got %s", integration.AvgDuration)
+	}
+	// 1s per test in the oldest run against 3s in the newest
+	if integration.Trend == nil || *integration.Trend != 200 || integration.Note != "slower" {
+		t.Errorf("Expected integration to grow slower by 200%%, got %+v", integration)
+	}
+	if manual.SkipRate != 1 || manual.Trend != nil || manual.Note != "always skipped" {
+		t.Errorf("Unexpected manual stats %+v", manual)
+	}
+}
+
+func TestStats(t *testing.T) {
+	dir := t.TempDir()
+	report := filepath.Join(dir, "report.json")
+	data, _ := json.Marshal(runReport{Decisions: []testDecision{
+		{Tag: "integration", Test: "TestA", Duration: time.Second},
+		{Tag: "manual", Test: "TestB", Skipped: true},
+	}})
+	if err := ioutil.WriteFile(report, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the report ran after the decision log
+	mtime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(report, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	log := filepath.Join(dir, "decisions.jsonl")
+	lines := `{"time":"2024-01-01T00:00:00Z","test":"TestA","tags":["integration","db"],"tag":"integration","skipped":false}
+{"time":"2024-01-01T00:00:01Z","test":"TestB","tags":["manual"],"tag":"manual","skipped":true,"reason":"in skip list"}
+`
+	if err := ioutil.WriteFile(log, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"stats", report, log}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	for _, want := range []string{"TAG", "db", "integration", "always skipped"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got\n%s", want, out)
+		}
+	}
+
+	stdout.Reset()
+	if code := run([]string{"stats", "-json", log, report}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	var tags []tagStats
+	if err := json.Unmarshal(stdout.Bytes(), &tags); err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 3 || tags[1].Tag != "integration" || tags[1].Runs != 2 || tags[1].Run != 2 {
+		t.Errorf("Unexpected stats %+v", tags)
+	}
+
+	if err := ioutil.WriteFile(log, []byte("{\"time\":\nnot json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"stats", log}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for a malformed log, got %d", code)
+	}
+}
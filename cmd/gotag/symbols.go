@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// testSymbol describes a test function for editor integrations
+type testSymbol struct {
+	testFunc
+	Skip   bool   `json:"skip"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// symbols lists every tagged test function in the given files or
+// packages and whether the current selection would skip it
+func symbols(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("symbols", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "write JSON for editor plugins")
+	var s selection
+	s.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	tc, err := s.resolve(fs)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	funcs, err := scan(fs.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+
+	syms := make([]testSymbol, len(funcs))
+	for i, fn := range funcs {
+		syms[i] = testSymbol{testFunc: fn}
+		for _, tags := range fn.calls {
+			if skip, reason := tc.WouldSkip(tags...); skip {
+				syms[i].Skip = true
+				syms[i].Reason = fmt.Sprintf("%s: %s", strings.Join(tags, ", "), reason)
+				break
+			}
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(syms); err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	for _, sym := range syms {
+		status := "will run"
+		if sym.Skip {
+			status = "will skip: " + sym.Reason
+		}
+		fmt.Fprintf(stdout, "%s:%d %s [%s] %s\n", sym.File, sym.Line, sym.Name, strings.Join(sym.Tags, ", "), status)
+	}
+	return 0
+}
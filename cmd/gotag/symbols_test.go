@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestSymbols(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"symbols", "-json", "-skip", "slow,postgres", "testdata/sample"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+
+	var syms []testSymbol
+	if err := json.Unmarshal(stdout.Bytes(), &syms); err != nil {
+		t.Fatal(err)
+	}
+	if len(syms) != 5 {
+		t.Fatalf("Expected 5 tagged functions, got %+v", syms)
+	}
+	byName := make(map[string]testSymbol)
+	for _, sym := range syms {
+		byName[sym.Name] = sym
+	}
+	if byName["TestIntegration"].Tags[0] != "integration" || byName["TestIntegration"].Skip {
+		t.Errorf("Unexpected symbol %+v", byName["TestIntegration"])
+	}
+	if !byName["TestSlow"].Skip {
+		t.Errorf("Expected TestSlow to be skipped, got %+v", byName["TestSlow"])
+	}
+	if sym := byName["TestPostgres"]; len(sym.Tags) != 2 || !sym.Skip {
+		t.Errorf("Expected TestPostgres to be skipped, got %+v", sym)
+	}
+	if byName["BenchmarkDB"].Tags[0] != "db" {
+		t.Errorf("Unexpected symbol %+v", byName["BenchmarkDB"])
+	}
+	if byName["FuzzParse"].Tags[0] != "fuzz-long" {
+		t.Errorf("Unexpected symbol %+v", byName["FuzzParse"])
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/boxtown/gotag"
+)
+
+// runs go test with the given arguments and additional environment
+// variables, returning its exit code. A variable so tests need not
+// build packages
+var goTest = func(args, env []string, stdout, stderr io.Writer) (int, error) {
+	cmd := exec.Command("go", append([]string{"test"}, args...)...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	if exit, ok := err.(*exec.ExitError); ok {
+		if exit.ExitCode() < 0 {
+			// killed by a signal
+			return 1, nil
+		}
+		return exit.ExitCode(), nil
+	}
+	return 0, err
+}
+
+// runs go test like goTest, printing its output only if it fails
+func quietTest(args, env []string, stdout, stderr io.Writer) (int, error) {
+	var out bytes.Buffer
+	code, err := goTest(args, env, &out, &out)
+	if code != 0 || err != nil {
+		out.WriteTo(stdout)
+	}
+	return code, err
+}
+
+// test runs go test with the selection given by the gotag flags, which
+// reaches the test binaries through the GOTAG_* environment variables
+// read by the default context. Every other argument, such as packages,
+// -v, -run, -count or -race, is passed through to go test in order, as
+// is everything following --. The output of go test is rendered as
+// given by -format. Returns the exit code of go test, or 2 if the
+// selection is malformed
+func test(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dryRun := fs.Bool("n", false, "log what would be skipped without skipping any tests, implies -v")
+	var f formatter
+	f.register(fs)
+	quiet := fs.Bool("q", false, "only print the output of go test if it fails")
+	var s selection
+	s.registerRunAs(fs, "only")
+	own, passthrough := splitArgs(fs, args)
+	if err := fs.Parse(own); err != nil {
+		return 2
+	}
+	tc, err := s.resolve(fs)
+	if err == nil {
+		err = f.init(stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	env := s.environ(tc)
+	if *dryRun {
+		// decisions are logged by the tests, which go test only shows with -v
+		env = append(env, gotag.EnvDryRun+"=true")
+		passthrough = append([]string{"-v"}, passthrough...)
+	}
+
+	tester := goTest
+	if *quiet {
+		tester = quietTest
+	}
+	passthrough, env = f.wrap(passthrough, env)
+	code, err := tester(passthrough, env, f.writer(stdout), stderr)
+	if err == nil {
+		err = f.render(stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	return code
+}
+
+// returns the GOTAG_* variables passing the resolved
+// selection to test binaries as NAME=value pairs
+func (s *selection) environ(tc *gotag.TestContext) []string {
+	var env []string
+	for _, v := range envVars(tc) {
+		env = append(env, v[0]+"="+v[1])
+	}
+	if s.profile != "" {
+		// for tests loading the config files themselves
+		env = append(env, gotag.EnvProfile+"="+s.profile)
+	}
+	return env
+}
+
+// separates the arguments naming flags defined on the flag set, along
+// with their values, from the arguments to pass through to go test
+func splitArgs(fs *flag.FlagSet, args []string) ([]string, []string) {
+	var own, passthrough []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			passthrough = append(passthrough, args[i+1:]...)
+			break
+		}
+		name := strings.TrimLeft(arg, "-")
+		if !strings.HasPrefix(arg, "-") || name == "" {
+			passthrough = append(passthrough, arg)
+			continue
+		}
+		hasValue := strings.Contains(name, "=")
+		if hasValue {
+			name = name[:strings.Index(name, "=")]
+		}
+		f := fs.Lookup(name)
+		if f == nil {
+			passthrough = append(passthrough, arg)
+			continue
+		}
+		own = append(own, arg)
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			own = append(own, args[i])
+		}
+	}
+	return own, passthrough
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestTestPassthrough(t *testing.T) {
+	var got, gotEnv []string
+	defer func(fn func([]string, []string, io.Writer, io.Writer) (int, error)) { goTest = fn }(goTest)
+	goTest = func(args, env []string, stdout, stderr io.Writer) (int, error) {
+		got, gotEnv = args, env
+		return 1, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-skip", "integration", "./...", "-v", "-run", "TestA", "-fuzzy", "-race", "--", "-skip", "TestB"}, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("Expected the go test exit code 1, got %d: %s", code, stderr.String())
+	}
+	want := []string{"./...", "-v", "-run", "TestA", "-race", "-skip", "TestB"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected go test arguments %v, got %v", want, got)
+	}
+	if !contains(gotEnv, "GOTAG_SKIP=integration") || !contains(gotEnv, "GOTAG_FUZZY=true") {
+		t.Errorf("Expected the selection in the environment, got %v", gotEnv)
+	}
+
+	code = run([]string{"test", "-distance", "x", "./..."}, &stdout, &stderr)
+	if code != 2 {
+		t.Errorf("Expected exit code 2 for a malformed flag, got %d", code)
+	}
+}
+
+func TestTestDryRun(t *testing.T) {
+	var got, gotEnv []string
+	defer func(fn func([]string, []string, io.Writer, io.Writer) (int, error)) { goTest = fn }(goTest)
+	goTest = func(args, env []string, stdout, stderr io.Writer) (int, error) {
+		got, gotEnv = args, env
+		return 0, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-n", "-skip", "integration", "./..."}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if want := []string{"-v", "./..."}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected go test arguments %v, got %v", want, got)
+	}
+	if !contains(gotEnv, "GOTAG_DRY_RUN=true") {
+		t.Errorf("Expected a dry run in the environment, got %v", gotEnv)
+	}
+}
+
+func TestTestQuiet(t *testing.T) {
+	defer func(fn func([]string, []string, io.Writer, io.Writer) (int, error)) { goTest = fn }(goTest)
+	code := 0
+	goTest = func(args, env []string, stdout, stderr io.Writer) (int, error) {
+		io.WriteString(stdout, "ok  \texample.com/a\n")
+		return code, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	if run([]string{"test", "-q", "./..."}, &stdout, &stderr) != 0 || stdout.Len() != 0 {
+		t.Errorf("Expected no output from passing tests, got %s", stdout.String())
+	}
+	code = 1
+	if run([]string{"test", "-q", "./..."}, &stdout, &stderr) != 1 || stdout.Len() == 0 {
+		t.Errorf("Expected the output of failing tests, got %s", stdout.String())
+	}
+	if code := run([]string{"test", "-selector", "speed in (", "./..."}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for a malformed selection, got %d", code)
+	}
+}
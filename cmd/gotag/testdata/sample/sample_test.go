@@ -0,0 +1,33 @@
+package sample
+
+import (
+	"testing"
+
+	"github.com/boxtown/gotag"
+)
+
+const slow = "slow"
+
+func TestIntegration(t *testing.T) {
+	gotag.Test(gotag.Integration, t, func(t gotag.T) {})
+}
+
+func TestSlow(t *testing.T) {
+	gotag.TestFor(slow, t, func(t *testing.T) {})
+}
+
+func BenchmarkDB(b *testing.B) {
+	gotag.Benchmark("db", b, func(b gotag.B) {})
+}
+
+func TestPostgres(t *testing.T) {
+	gotag.TestTags([]string{gotag.Integration, "postgres"}, t, func(t gotag.T) {})
+}
+
+func TestUntagged(t *testing.T) {}
+
+func TestIntegrationLegacy(t *testing.T) {}
+
+func FuzzParse(f *testing.F) {
+	gotag.Fuzz("fuzz-long", f, func(f gotag.F) {})
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// testEvent is a single event of `go test -json` output
+type testEvent struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// matches the line logged by tagged tests when GOTAG_TRACE is set
+var tagMarker = regexp.MustCompile(`gotag: tag=("(?:[^"\\]|\\.)*")`)
+
+// identifies a test by the import path of its package and its name,
+// which is how `go test -json` reports it
+type testKey struct{ pkg, test string }
+
+// scans the test files matched by the patterns for the tags of each
+// test function, keyed by its package and name so that functions of
+// the same name in different packages are told apart. A variable so
+// tests need not scan
+var scanTestTags = func(patterns []string) (map[testKey][]string, error) {
+	funcs, err := scan(patterns)
+	if err != nil {
+		return nil, err
+	}
+	pkgs, err := listPackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+	importPaths := make(map[string]string, len(pkgs))
+	for _, pkg := range pkgs {
+		if !pkg.DepOnly {
+			importPaths[pkg.Dir] = pkg.ImportPath
+		}
+	}
+	byTest := make(map[testKey][]string)
+	for _, fn := range funcs {
+		dir, err := filepath.Abs(filepath.Dir(fn.File))
+		if err != nil {
+			return nil, err
+		}
+		key := testKey{importPaths[dir], fn.Name}
+		for _, tag := range fn.Tags {
+			if !contains(byTest[key], tag) {
+				byTest[key] = append(byTest[key], tag)
+			}
+		}
+	}
+	return byTest, nil
+}
+
+// timings reads `go test -json` output from stdin and attributes test
+// durations to tags. The tags of a test are those it logged if
+// GOTAG_TRACE was set, or else derived from its name
+func timings(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("timings", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "write a report that gotag report can read instead of a table")
+	patterns := fs.String("scan", "./...", "comma separated packages whose test files are scanned for tagged tests")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	named, err := scanTestTags(strings.Split(*patterns, ","))
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	in := stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 2
+		}
+		defer f.Close()
+		in = f
+	}
+
+	r, err := readTestEvents(in, named)
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 1
+	}
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			fmt.Fprintf(stderr, "gotag: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tRUN\tSKIPPED\tFAILED\tDURATION")
+	for _, s := range summarize(r, nil) {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", s.Tag, s.Run, s.Skipped, s.Failed, s.Duration)
+	}
+	w.Flush()
+	return 0
+}
+
+// builds a report from `go test -json` output, attributing each test
+// that logged a tag marker to its tag and every other test to the tags
+// derived from its name by tagsFromName, given the tags of the test
+// functions by package and name. Durations are measured between the run and end
+// events of a test so they are comparable across packages
+func readTestEvents(r io.Reader, named map[testKey][]string) (*runReport, error) {
+	tags := make(map[testKey][]string)
+	started := make(map[testKey]time.Time)
+	var order []testKey
+	ended := make(map[testKey]testEvent)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e testEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			// go test interleaves non json build output
+			continue
+		}
+		if e.Test == "" {
+			continue
+		}
+		key := testKey{e.Package, e.Test}
+		switch e.Action {
+		case "run":
+			started[key] = e.Time
+			order = append(order, key)
+		case "output":
+			if m := tagMarker.FindStringSubmatch(e.Output); m != nil {
+				if tag, err := strconv.Unquote(m[1]); err == nil {
+					tags[key] = append(tags[key], tag)
+				}
+			}
+		case "pass", "fail", "skip":
+			ended[key] = e
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var known []string
+	for _, tags := range named {
+		for _, tag := range tags {
+			if !contains(known, tag) {
+				known = append(known, tag)
+			}
+		}
+	}
+	sort.Strings(known)
+
+	report := &runReport{}
+	for _, key := range order {
+		e := ended[key]
+		d := testDecision{
+			Test:    key.pkg + "." + key.test,
+			Skipped: e.Action == "skip",
+			Failed:  e.Action == "fail",
+		}
+		if start, ok := started[key]; ok && !e.Time.IsZero() {
+			d.Duration = e.Time.Sub(start)
+		} else {
+			d.Duration = time.Duration(e.Elapsed * float64(time.Second))
+		}
+		tagged, ok := tags[key]
+		if !ok {
+			tagged = tagsFromName(key, named, known)
+		}
+		// tests with several tags count under each of them
+		for _, tag := range tagged {
+			d.Tag = tag
+			report.Decisions = append(report.Decisions, d)
+		}
+	}
+	sort.SliceStable(report.Decisions, func(i, j int) bool {
+		return report.Decisions[i].Tag < report.Decisions[j].Tag
+	})
+	return report, nil
+}
+
+// returns the tags of a test derived from its name without any
+// instrumentation: those of its test function for a top level test,
+// and for a subtest the known tags prefixing an element of its name,
+// such as integration in TestStore/integration:insert. Subtests are
+// not attributed the tags of their test function, whose duration
+// already includes theirs
+func tagsFromName(key testKey, named map[testKey][]string, known []string) []string {
+	elems := strings.Split(key.test, "/")
+	if len(elems) == 1 {
+		return named[key]
+	}
+	var tags []string
+	for _, elem := range elems[1:] {
+		for _, tag := range known {
+			if elem == tag || strings.HasPrefix(elem, tag+":") || strings.HasPrefix(elem, tag+"_") {
+				if !contains(tags, tag) {
+					tags = append(tags, tag)
+				}
+			}
+		}
+	}
+	return tags
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testJSON = `{"Time":"2024-01-01T00:00:00Z","Action":"run","Package":"p","Test":"TestA"}
+{"Time":"2024-01-01T00:00:00.1Z","Action":"output","Package":"p","Test":"TestA","Output":"    lib.go:1: gotag: tag=\"integration\"\n"}
+{"Time":"2024-01-01T00:00:02Z","Action":"pass","Package":"p","Test":"TestA","Elapsed":2}
+{"Time":"2024-01-01T00:00:00Z","Action":"run","Package":"q","Test":"TestB"}
+{"Time":"2024-01-01T00:00:00Z","Action":"output","Package":"q","Test":"TestB","Output":"    lib.go:1: gotag: tag=\"integration\"\n"}
+{"Time":"2024-01-01T00:00:00Z","Action":"skip","Package":"q","Test":"TestB","Elapsed":0}
+{"Time":"2024-01-01T00:00:00Z","Action":"run","Package":"q","Test":"TestUntagged"}
+{"Time":"2024-01-01T00:00:01Z","Action":"pass","Package":"q","Test":"TestUntagged","Elapsed":1}
+# build output
+`
+
+func TestReadTestEvents(t *testing.T) {
+	r, err := readTestEvents(strings.NewReader(testJSON), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Decisions) != 2 {
+		t.Fatalf("Expected 2 tagged tests, got %+v", r.Decisions)
+	}
+	a := r.Decisions[0]
+	if a.Tag != "integration" || a.Test != "p.TestA" || a.Duration != 2*time.Second {
+		t.Errorf("Unexpected decision %+v", a)
+	}
+	if !r.Decisions[1].Skipped {
+		t.Errorf("Expected TestB to be skipped")
+	}
+}
+
+const untracedJSON = `{"Time":"2024-01-01T00:00:00Z","Action":"run","Package":"p","Test":"TestStore"}
+{"Time":"2024-01-01T00:00:00Z","Action":"run","Package":"p","Test":"TestStore/db:insert"}
+{"Time":"2024-01-01T00:00:01Z","Action":"pass","Package":"p","Test":"TestStore/db:insert","Elapsed":1}
+{"Time":"2024-01-01T00:00:00Z","Action":"run","Package":"p","Test":"TestStore/unit"}
+{"Time":"2024-01-01T00:00:00Z","Action":"pass","Package":"p","Test":"TestStore/unit","Elapsed":0}
+{"Time":"2024-01-01T00:00:03Z","Action":"pass","Package":"p","Test":"TestStore","Elapsed":3}
+`
+
+func TestReadTestEventsByName(t *testing.T) {
+	named := map[testKey][]string{
+		{"p", "TestStore"}: {"integration"},
+		{"q", "TestStore"}: {"slow"},
+		{"p", "TestOther"}: {"db"},
+	}
+	r, err := readTestEvents(strings.NewReader(untracedJSON), named)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Decisions) != 2 {
+		t.Fatalf("Expected 2 tagged tests, got %+v", r.Decisions)
+	}
+	db, integration := r.Decisions[0], r.Decisions[1]
+	if db.Tag != "db" || db.Test != "p.TestStore/db:insert" || db.Duration != time.Second {
+		t.Errorf("Unexpected decision %+v", db)
+	}
+	if integration.Tag != "integration" || integration.Test != "p.TestStore" || integration.Duration != 3*time.Second {
+		t.Errorf("Unexpected decision %+v", integration)
+	}
+}
+
+func TestScanTestTags(t *testing.T) {
+	root := t.TempDir()
+	for pkg, tag := range map[string]string{"a": "integration", "b": "slow"} {
+		dir := filepath.Join(root, pkg)
+		src := "package " + pkg + "\n\nimport \"testing\"\n\n//gotag:" + tag + "\nfunc TestStore(t *testing.T) {}\n"
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "store_test.go"), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func(fn func([]string) ([]goPackage, error)) { listPackages = fn }(listPackages)
+	listPackages = func(patterns []string) ([]goPackage, error) {
+		return []goPackage{
+			{ImportPath: "example.com/a", Dir: filepath.Join(root, "a")},
+			{ImportPath: "example.com/b", Dir: filepath.Join(root, "b")},
+		}, nil
+	}
+
+	named, err := scanTestTags([]string{root + "/..."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := named[testKey{"example.com/a", "TestStore"}], named[testKey{"example.com/b", "TestStore"}]
+	if strings.Join(a, ",") != "integration" || strings.Join(b, ",") != "slow" {
+		t.Errorf("Expected the tests of each package to keep their tags, got %v", named)
+	}
+}
+
+func TestTimings(t *testing.T) {
+	defer func(scan func([]string) (map[testKey][]string, error)) { scanTestTags = scan }(scanTestTags)
+	var scanned []string
+	scanTestTags = func(patterns []string) (map[testKey][]string, error) {
+		scanned = patterns
+		return map[testKey][]string{{"p", "TestStore"}: {"integration"}}, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := timings([]string{"-scan", "./a,./b"}, strings.NewReader(untracedJSON), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if strings.Join(scanned, " ") != "./a ./b" {
+		t.Errorf("Expected the given packages to be scanned, got %v", scanned)
+	}
+	if !strings.Contains(stdout.String(), "integration") {
+		t.Errorf("Expected integration in the output, got\n%s", stdout.String())
+	}
+}
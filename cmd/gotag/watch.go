@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// closed to stop watching. A variable so tests can end the watch loop
+var watchStop <-chan struct{}
+
+// watch runs go test like test, then runs it again whenever a file
+// below the current directory changes. The selection is resolved once,
+// so every run uses the same tags. Runs until interrupted
+func watch(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	interval := fs.Duration("interval", 500*time.Millisecond, "how often to check files for changes")
+	var f formatter
+	f.register(fs)
+	var s selection
+	s.registerRunAs(fs, "only")
+	own, passthrough := splitArgs(fs, args)
+	if err := fs.Parse(own); err != nil {
+		return 2
+	}
+	tc, err := s.resolve(fs)
+	if err == nil {
+		err = f.init(stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gotag: %v\n", err)
+		return 2
+	}
+	args, env := f.wrap(passthrough, s.environ(tc))
+
+	rerun := func() error {
+		f.events.Reset()
+		if _, err := goTest(args, env, f.writer(stdout), stderr); err != nil {
+			return err
+		}
+		return f.render(stdout)
+	}
+	snap, err := snapshot(".")
+	if err == nil {
+		err = rerun()
+	}
+	for err == nil {
+		fmt.Fprintln(stderr, "gotag: watching for changes")
+		var changed bool
+		for !changed && err == nil {
+			select {
+			case <-watchStop:
+				return 0
+			case <-time.After(*interval):
+			}
+			var next map[string]fileState
+			if next, err = snapshot("."); err == nil {
+				changed = !sameSnapshot(snap, next)
+				snap = next
+			}
+		}
+		if err == nil {
+			err = rerun()
+		}
+	}
+	fmt.Fprintf(stderr, "gotag: %v\n", err)
+	return 2
+}
+
+// fileState is the state of a watched file
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// returns the state of every file below root, ignoring hidden
+// files and directories such as .git along with vendor directories
+func snapshot(root string) (map[string]fileState, error) {
+	snap := make(map[string]fileState)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if path != root && (strings.HasPrefix(name, ".") && !strings.HasPrefix(name, ".gotag") || name == "vendor") {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() {
+			snap[path] = fileState{info.ModTime(), info.Size()}
+		}
+		return nil
+	})
+	return snap, err
+}
+
+// reports whether no file was added, removed or modified between the snapshots
+func sameSnapshot(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, state := range a {
+		if other, ok := b[path]; !ok || !other.modTime.Equal(state.modTime) || other.size != state.size {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a_test.go")
+	if err := ioutil.WriteFile(file, []byte("package a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	defer func(c <-chan struct{}) { watchStop = c }(watchStop)
+	watchStop = stop
+	runs := make(chan []string, 10)
+	defer func(fn func([]string, []string, io.Writer, io.Writer) (int, error)) { goTest = fn }(goTest)
+	goTest = func(args, env []string, stdout, stderr io.Writer) (int, error) {
+		runs <- env
+		return 1, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	done := make(chan int)
+	go func() {
+		done <- run([]string{"watch", "-interval", "5ms", "-skip", "integration", "./..."}, &stdout, &stderr)
+	}()
+	if env := <-runs; !contains(env, "GOTAG_SKIP=integration") {
+		t.Errorf("Expected the selection in the environment, got %v", env)
+	}
+	if err := ioutil.WriteFile(file, []byte("package a_test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case env := <-runs:
+		if !contains(env, "GOTAG_SKIP=integration") {
+			t.Errorf("Expected the selection to be preserved, got %v", env)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the tests to run again after a change")
+	}
+	close(stop)
+	if code := <-done; code != 0 {
+		t.Errorf("Expected exit code 0, got %d: %s", code, stderr.String())
+	}
+}
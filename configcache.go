@@ -0,0 +1,117 @@
+package gotag
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// parsed configs keyed by absolute path
+var configCache = struct {
+	sync.Mutex
+	entries map[string]cachedConfig
+}{entries: make(map[string]cachedConfig)}
+
+type cachedConfig struct {
+	modTime time.Time
+	size    int64
+	config  *Config
+}
+
+// InvalidateConfigCache discards every parsed config file. Config files
+// are cached by path and modification time, so this is only needed by
+// tools that rewrite a config within the same modification time
+func InvalidateConfigCache() {
+	configCache.Lock()
+	defer configCache.Unlock()
+	configCache.entries = make(map[string]cachedConfig)
+}
+
+// loads the config file at the given path with the given loader, reusing
+// the previously parsed config if the file has not been modified since.
+// Returns ErrNoConfig if the file could not be opened and ConfigErrors
+// located in the file if it is malformed
+func loadCachedConfig(path string, load func(r io.Reader) (*Config, error)) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, ErrNoConfig
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	configCache.Lock()
+	entry, ok := configCache.entries[abs]
+	configCache.Unlock()
+	if ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return entry.config.clone(), nil
+	}
+
+	config, err := load(f)
+	if errs, ok := err.(ConfigErrors); ok {
+		return nil, errs.in(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	configCache.Lock()
+	configCache.entries[abs] = cachedConfig{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		config:  config.clone(),
+	}
+	configCache.Unlock()
+	return config, nil
+}
+
+// returns a deep copy of the config
+func (c *Config) clone() *Config {
+	config := *c
+	config.Skip = append([]string(nil), c.Skip...)
+	config.SkipEntries = append([]SkipEntry(nil), c.SkipEntries...)
+	config.Run = append([]string(nil), c.Run...)
+	config.MustRun = append([]string(nil), c.MustRun...)
+	config.Quarantine = append([]string(nil), c.Quarantine...)
+	config.SkipIf = append([]SkipRule(nil), c.SkipIf...)
+	config.Tags = append([]Tag(nil), c.Tags...)
+	config.ShortSkips = append([]string(nil), c.ShortSkips...)
+	if c.Depends != nil {
+		config.Depends = make(map[string][]string, len(c.Depends))
+		for tag, required := range c.Depends {
+			config.Depends[tag] = append([]string(nil), required...)
+		}
+	}
+	if c.Groups != nil {
+		config.Groups = make(map[string][]string, len(c.Groups))
+		for name, members := range c.Groups {
+			config.Groups[name] = append([]string(nil), members...)
+		}
+	}
+	if c.BuildTags != nil {
+		config.BuildTags = make(map[string]string, len(c.BuildTags))
+		for build, tag := range c.BuildTags {
+			config.BuildTags[build] = tag
+		}
+	}
+	if c.Profiles != nil {
+		config.Profiles = make(map[string]*Config, len(c.Profiles))
+		for name, profile := range c.Profiles {
+			config.Profiles[name] = profile.clone()
+		}
+	}
+	if c.Timeouts != nil {
+		config.Timeouts = make(map[string]string, len(c.Timeouts))
+		for tag, d := range c.Timeouts {
+			config.Timeouts[tag] = d
+		}
+	}
+	return &config
+}
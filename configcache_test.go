@@ -0,0 +1,58 @@
+package gotag
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestConfigCache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".gotag.json")
+	if err := ioutil.WriteFile(path, []byte(`{"skip": ["tagA"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	tc, err := LoadFrom(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := tc.SkippedTags(); len(tags) != 1 || tags[0] != "tagA" {
+		t.Fatalf("Unexpected skipped tags %v", tags)
+	}
+
+	// rewrite keeping the same size and modification time
+	if err := ioutil.WriteFile(path, []byte(`{"skip": ["tagB"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	tc, _ = LoadFrom(dir)
+	if tags := tc.SkippedTags(); tags[0] != "tagA" {
+		t.Errorf("Expected cached config, got %v", tags)
+	}
+
+	InvalidateConfigCache()
+	tc, _ = LoadFrom(dir)
+	if tags := tc.SkippedTags(); tags[0] != "tagB" {
+		t.Errorf("Expected reloaded config, got %v", tags)
+	}
+
+	// a modification time change reloads the config
+	if err := ioutil.WriteFile(path, []byte(`{"skip": ["tagC"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	tc, _ = LoadFrom(dir)
+	if tags := tc.SkippedTags(); tags[0] != "tagC" {
+		t.Errorf("Expected modified config, got %v", tags)
+	}
+}
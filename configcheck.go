@@ -0,0 +1,348 @@
+package gotag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ConfigError is a problem found in a config file
+type ConfigError struct {
+	// Path is the config file, if known, and Line the
+	// line of the problem, 0 if unknown
+	Path    string
+	Line    int
+	Message string
+}
+
+func (e ConfigError) Error() string {
+	var b strings.Builder
+	if e.Path != "" {
+		b.WriteString(e.Path + ":")
+	}
+	if e.Line > 0 {
+		fmt.Fprintf(&b, "%d:", e.Line)
+	}
+	if b.Len() > 0 {
+		b.WriteString(" ")
+	}
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// ConfigErrors lists the problems found in a config file. Loading a
+// config file fails with ConfigErrors if it is malformed, has unknown
+// fields or invalid values such as a negative distance, empty tags or
+// tags registered or skipped with a reason twice
+type ConfigErrors []ConfigError
+
+func (e ConfigErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// returns a copy of the errors located in the file at path
+func (e ConfigErrors) in(path string) ConfigErrors {
+	located := make(ConfigErrors, len(e))
+	for i, err := range e {
+		err.Path = path
+		located[i] = err
+	}
+	return located
+}
+
+// LoadConfigFile loads the JSON or YAML config file at the path, chosen
+// by its extension like WithConfigFile, or the skip file, see SkipFile,
+// without merging the configs it extends. Returns ConfigErrors, located
+// in the file, if it is malformed
+func LoadConfigFile(path string) (*Config, error) {
+	if filepath.Base(path) == SkipFile {
+		config, err := loadCachedConfig(path, loadSkipFile)
+		if err == ErrNoConfig {
+			return nil, fmt.Errorf("Could not open skip file %s", path)
+		}
+		return config, err
+	}
+	load := loadJSONConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		load = loadYAMLConfig
+	}
+	config, err := loadCachedConfig(path, load)
+	if err == ErrNoConfig {
+		return nil, fmt.Errorf("Could not open config file %s", path)
+	}
+	return config, err
+}
+
+// returns the line of the offset within data
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// returns the line first naming the field in data, or 0 if none does
+func lineOf(data []byte, field string) int {
+	re := regexp.MustCompile(`(^|[\s{,"'-])` + regexp.QuoteMeta(field) + `["']?\s*:`)
+	loc := re.FindIndex(data)
+	if loc == nil {
+		return 0
+	}
+	return lineAt(data, int64(loc[0])+1)
+}
+
+// returns data from the line first naming the field on, along with the
+// number of lines before it, so that lines within a nested object are
+// found. Returns data whole if no line names the field
+func from(data []byte, field string) ([]byte, int) {
+	line := lineOf(data, field)
+	if line == 0 {
+		return data, 0
+	}
+	for i := 1; i < line; i++ {
+		data = data[bytes.IndexByte(data, '\n')+1:]
+	}
+	return data, line - 1
+}
+
+// converts an error decoding json data into ConfigErrors
+func jsonConfigError(data []byte, err error) error {
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		return ConfigErrors{{Line: lineAt(data, e.Offset), Message: e.Error()}}
+	case *offsetError:
+		return ConfigErrors{{Line: lineAt(data, e.offset), Message: e.Error()}}
+	case *json.UnmarshalTypeError:
+		// the offset is that of the fields decoded as usual
+		// so the field is looked up instead
+		field := e.Field[strings.LastIndex(e.Field, ".")+1:]
+		return ConfigErrors{{Line: lineOf(data, field),
+			Message: fmt.Sprintf("cannot decode %s into field '%s' of type %s", e.Value, e.Field, e.Type)}}
+	}
+	return ConfigErrors{{Message: err.Error()}}
+}
+
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// converts an error decoding yaml data into ConfigErrors
+func yamlConfigError(err error) error {
+	messages := []string{err.Error()}
+	if e, ok := err.(*yaml.TypeError); ok {
+		messages = e.Errors
+	}
+	errs := make(ConfigErrors, len(messages))
+	for i, message := range messages {
+		errs[i].Message = message
+		if m := yamlLine.FindStringSubmatch(message); m != nil {
+			errs[i].Line, _ = strconv.Atoi(m[1])
+			errs[i].Message = m[2]
+		}
+	}
+	return errs
+}
+
+// checks the fields and values of a config decoded from data, raw being
+// the generic decoding of data or of its fields other than skip and run
+func checkConfig(data []byte, raw interface{}, config *Config) error {
+	var errs ConfigErrors
+	checkFields(data, "", raw, reflect.TypeOf(*config), &errs)
+	checkValues(data, 0, "", config, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Line < errs[j].Line
+	})
+	return errs
+}
+
+// reports the keys of the object that match no field of the struct
+// type, recursing into profiles and lists of objects
+func checkFields(data []byte, prefix string, value interface{}, typ reflect.Type, errs *ConfigErrors) {
+	object, ok := asObject(value)
+	if !ok {
+		return
+	}
+	fields := fieldTypes(typ)
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			*errs = append(*errs, ConfigError{Line: lineOf(data, key),
+				Message: fmt.Sprintf("unknown field '%s%s'%s", prefix, key, closestField(key, fields))})
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Map:
+			if elem := field.Elem(); elem.Kind() == reflect.Ptr && elem.Elem().Kind() == reflect.Struct {
+				entries, _ := asObject(object[key])
+				for name, v := range entries {
+					checkFields(data, prefix+key+"."+name+".", v, elem.Elem(), errs)
+				}
+			}
+		case reflect.Slice:
+			elem := field.Elem()
+			if key == "skip" {
+				// entries of the skip list may be objects
+				elem = reflect.TypeOf(SkipEntry{})
+			}
+			if elem.Kind() != reflect.Struct {
+				continue
+			}
+			items, _ := object[key].([]interface{})
+			for i, v := range items {
+				checkFields(data, fmt.Sprintf("%s%s[%d].", prefix, key, i), v, elem, errs)
+			}
+		}
+	}
+}
+
+// returns the types of the fields of the struct type by json name
+func fieldTypes(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = f.Type
+		}
+	}
+	return fields
+}
+
+// returns the object as a map, whether decoded from json or yaml
+func asObject(value interface{}) (map[string]interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, true
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			object[fmt.Sprint(key)] = value
+		}
+		return object, true
+	}
+	return nil, false
+}
+
+// suggests the field closest to the unknown key, if any is close
+func closestField(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if d := Levenshtein(key, name); d < bestDistance || d == bestDistance && name < best {
+			best, bestDistance = name, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean '%s'?", best)
+}
+
+// reports invalid values of the config and its profiles
+// data being the text of the config following its first offset lines
+func checkValues(data []byte, offset int, prefix string, c *Config, errs *ConfigErrors) {
+	report := func(field, format string, args ...interface{}) {
+		line := lineOf(data, field)
+		if line > 0 {
+			line += offset
+		}
+		*errs = append(*errs, ConfigError{Line: line, Message: prefix + fmt.Sprintf(format, args...)})
+	}
+	if c.EditDistance < 0 {
+		report("distance", "distance must not be negative, got %d", c.EditDistance)
+	}
+	lists := []struct {
+		field string
+		tags  []string
+	}{
+		{"skip", c.Skip},
+		{"run", c.Run},
+		{"must_run", c.MustRun},
+		{"quarantine", c.Quarantine},
+		{"short_skips", c.ShortSkips},
+	}
+	for _, list := range lists {
+		for _, tag := range list.tags {
+			if strings.TrimSpace(tag) == "" {
+				report(list.field, "%s holds an empty tag", list.field)
+				break
+			}
+		}
+	}
+
+	skipped := make(map[string]bool, len(c.SkipEntries))
+	for _, entry := range c.SkipEntries {
+		switch {
+		case entry.Tag == "":
+			report("skip", "skip holds an entry without a tag")
+		case skipped[entry.Tag]:
+			report("skip", "tag '%s' appears twice in skip", entry.Tag)
+		}
+		skipped[entry.Tag] = true
+	}
+	for _, rule := range c.SkipIf {
+		if rule.Tag == "" {
+			report("skip_if", "skip_if holds a rule without a tag")
+		}
+	}
+	registered := make(map[string]bool, len(c.Tags))
+	for _, tag := range c.Tags {
+		switch {
+		case tag.Name == "":
+			report("tags", "tags holds a tag without a name")
+		case registered[tag.Name]:
+			report("tags", "tag '%s' is registered twice in tags", tag.Name)
+		}
+		registered[tag.Name] = true
+	}
+	for _, field := range []string{"groups", "depends"} {
+		m := c.Groups
+		if field == "depends" {
+			m = c.Depends
+		}
+		names := make([]string, 0, len(m))
+		for name := range m {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, tag := range m[name] {
+				if strings.TrimSpace(tag) == "" {
+					report(field, "%s of '%s' holds an empty tag", field, name)
+					break
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	profiles, n := from(data, "profiles")
+	for _, name := range names {
+		if profile := c.Profiles[name]; profile != nil {
+			text, m := from(profiles, name)
+			checkValues(text, offset+n+m, fmt.Sprintf("%sprofile '%s': ", prefix, name), profile, errs)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package gotag
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writes the config to a file named name in a new directory and loads it
+func loadConfigText(t *testing.T, name, text string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadConfigFile(path)
+}
+
+// checks that err holds the located errors, ignoring their paths
+func expectConfigErrors(t *testing.T, err error, want ...string) {
+	t.Helper()
+	errs, ok := err.(ConfigErrors)
+	if !ok {
+		t.Fatalf("Expected ConfigErrors, got %v", err)
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("Expected %d errors, got %v", len(want), errs)
+	}
+	for i, e := range errs {
+		e.Path = ""
+		if e.Error() != want[i] {
+			t.Errorf("Expected error %q, got %q", want[i], e.Error())
+		}
+	}
+}
+
+func TestUnknownFieldsYAML(t *testing.T) {
+	_, err := loadConfigText(t, ".gotag.yml", `fuzzy: true
+skp: [tagA]
+skip:
+  - {tag: tagB, reasn: flaky}
+profiles:
+  ci:
+    dry_rnu: true
+`)
+	expectConfigErrors(t, err,
+		"2: unknown field 'skp', did you mean 'skip'?",
+		"4: unknown field 'skip[0].reasn', did you mean 'reason'?",
+		"7: unknown field 'profiles.ci.dry_rnu', did you mean 'dry_run'?",
+	)
+}
+
+func TestUnknownFieldsJSON(t *testing.T) {
+	_, err := loadConfigText(t, ".gotag.json", `{
+  "fuzzy": true,
+  "tags": [{"name": "db", "ownr": "qa"}],
+  "completely_unrelated": 1
+}`)
+	expectConfigErrors(t, err,
+		"3: unknown field 'tags[0].ownr', did you mean 'owner'?",
+		"4: unknown field 'completely_unrelated'",
+	)
+}
+
+func TestInvalidConfigValues(t *testing.T) {
+	_, err := loadConfigText(t, ".gotag.yml", `distance: -1
+run: [tagA, ""]
+skip:
+  - {tag: tagB, reason: flaky}
+  - {tag: tagB, reason: slow}
+tags:
+  - {name: db}
+  - {name: db}
+profiles:
+  ci:
+    distance: -2
+`)
+	expectConfigErrors(t, err,
+		"1: distance must not be negative, got -1",
+		"2: run holds an empty tag",
+		"3: tag 'tagB' appears twice in skip",
+		"6: tag 'db' is registered twice in tags",
+		"11: profile 'ci': distance must not be negative, got -2",
+	)
+}
+
+func TestConfigSyntaxErrors(t *testing.T) {
+	_, err := loadConfigText(t, ".gotag.json", "{\n  \"fuzzy\": true,\n  \"skip\": [\"tagA\",]\n}")
+	errs, ok := err.(ConfigErrors)
+	if !ok || len(errs) != 1 || errs[0].Line != 3 {
+		t.Errorf("Expected a syntax error on line 3, got %v", err)
+	}
+
+	_, err = loadConfigText(t, ".gotag.yml", "fuzzy: true\ndistance: far\n")
+	errs, ok = err.(ConfigErrors)
+	if !ok || len(errs) != 1 || errs[0].Line != 2 {
+		t.Errorf("Expected a type error on line 2, got %v", err)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	config, err := loadConfigText(t, ".gotag.yml", "skip: [tagA, {tag: tagB, reason: flaky}]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Skip) != 1 || len(config.SkipEntries) != 1 {
+		t.Errorf("Unexpected config %+v", config)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".gotag.json")
+	if err := ioutil.WriteFile(path, []byte(`{"fuzy": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadConfigFile(path)
+	if err == nil || !strings.HasPrefix(err.Error(), path+":1: unknown field 'fuzy'") {
+		t.Errorf("Expected an error located in %s, got %v", path, err)
+	}
+	if _, err := LoadFrom(dir); err == nil || !strings.Contains(err.Error(), path+":1:") {
+		t.Errorf("Expected LoadFrom to fail with the located error, got %v", err)
+	}
+
+	if _, err := LoadConfigFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
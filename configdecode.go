@@ -0,0 +1,219 @@
+package gotag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// decodes a json config, streaming the skip and run lists so that
+// very large machine generated lists are deduplicated and interned
+// as they are read rather than after being fully decoded
+func decodeJSONConfig(r io.Reader) (*Config, error) {
+	config, _, err := decodeJSONConfigFields(r)
+	return config, err
+}
+
+// decodes a json config like decodeJSONConfig, also returning the
+// fields other than the skip and run lists undecoded
+func decodeJSONConfigFields(r io.Reader) (*Config, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, nil, err
+	}
+
+	var config Config
+	in := make(interner)
+	rest := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string)
+		switch key {
+		case "skip":
+			config.Skip, err = decodeTagList(dec, in, &config.SkipEntries)
+		case "run":
+			config.Run, err = decodeTagList(dec, in, nil)
+		default:
+			var raw json.RawMessage
+			err = dec.Decode(&raw)
+			rest[key] = raw
+		}
+		if err != nil {
+			return nil, nil, atOffset(dec, err)
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, nil, err
+	}
+
+	// the remaining fields are small so they are
+	// decoded as usual
+	if len(rest) > 0 {
+		bytes, err := json.Marshal(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(bytes, &config); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &config, rest, nil
+}
+
+// offsetError is an error at an offset of the decoded json
+type offsetError struct {
+	offset int64
+	err    error
+}
+
+func (e *offsetError) Error() string {
+	return e.err.Error()
+}
+
+// returns the error along with the current offset of the decoder
+func atOffset(dec *json.Decoder, err error) error {
+	if _, ok := err.(*json.SyntaxError); ok {
+		return err
+	}
+	return &offsetError{offset: dec.InputOffset(), err: err}
+}
+
+// decodes a json array of tags one element at a time. Entries given
+// as objects are appended to entries unless it is nil
+func decodeTagList(dec *json.Decoder, in interner, entries *[]SkipEntry) ([]string, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok == nil {
+		return nil, nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return nil, fmt.Errorf("Expected a list of tags, got %v", tok)
+	}
+
+	var tags []string
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if d, ok := tok.(json.Delim); ok && d == '{' && entries != nil {
+			skip, err := decodeSkipEntry(dec)
+			if err != nil {
+				return nil, err
+			}
+			*entries = append(*entries, skip)
+			continue
+		}
+		tag, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("Expected a tag, got %v", tok)
+		}
+		if seen[tag] {
+			continue
+		}
+		tag = in.intern(tag)
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags, expectDelim(dec, ']')
+}
+
+// decodes the fields of a skip entry whose opening
+// brace has been read
+func decodeSkipEntry(dec *json.Decoder) (SkipEntry, error) {
+	var skip SkipEntry
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return skip, err
+		}
+		key, _ := tok.(string)
+		if tok, err = dec.Token(); err != nil {
+			return skip, err
+		}
+		value, ok := tok.(string)
+		if !ok {
+			return skip, fmt.Errorf("Expected a string for '%s' of a skip entry, got %v", key, tok)
+		}
+		switch key {
+		case "tag":
+			skip.Tag = value
+		case "until":
+			skip.Until = value
+		case "reason":
+			skip.Reason = value
+		default:
+			return skip, fmt.Errorf("unknown field '%s' in skip entry", key)
+		}
+	}
+	return skip, expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("Expected '%v', got %v", delim, tok)
+	}
+	return nil
+}
+
+// interner shares a single copy of each distinct tag
+// between the lists of a config
+type interner map[string]string
+
+func (in interner) intern(s string) string {
+	if interned, ok := in[s]; ok {
+		return interned
+	}
+	in[s] = s
+	return s
+}
+
+// returns the interned tags with duplicates removed,
+// preserving the order of first appearance
+func (in interner) unique(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	unique := tags[:0]
+	for _, tag := range tags {
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		unique = append(unique, in.intern(tag))
+	}
+	return unique
+}
+
+// MemoryUsage returns an estimate of the number of bytes
+// used to hold the tags registered with the TestContext
+func (tc *TestContext) MemoryUsage() int {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return tc.skip.memoryUsage() + tc.runOnly.memoryUsage()
+}
+
+// estimates the bytes held by the set, counting the string headers
+// and bucket overhead per entry plus the string contents
+func (s *tagSet) memoryUsage() int {
+	const entryOverhead = 64
+	n := 0
+	for canonical, original := range s.originals {
+		n += entryOverhead + len(canonical)
+		if original != canonical {
+			n += len(original)
+		}
+	}
+	return n
+}
@@ -0,0 +1,85 @@
+package gotag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestDecodeJSONConfig(t *testing.T) {
+	config, err := decodeJSONConfig(strings.NewReader(
+		`{"fuzzy": true, "skip": ["tagA", "tagB", "tagA"], "run": null, "distance": 3, "other": {}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Skip) != 2 || config.Skip[0] != "tagA" || config.Skip[1] != "tagB" {
+		t.Errorf("Unexpected skip list %v", config.Skip)
+	}
+	if config.Run != nil {
+		t.Errorf("Unexpected run list %v", config.Run)
+	}
+	if !config.Fuzzy || config.EditDistance != 3 {
+		t.Error("Expected remaining fields to be decoded")
+	}
+
+	if _, err := decodeJSONConfig(strings.NewReader(`{"skip": [1]}`)); err == nil {
+		t.Error("Expected an error for a non string tag")
+	}
+}
+
+func TestInternerUnique(t *testing.T) {
+	in := make(interner)
+	tags := in.unique([]string{"tagA", "tagB", "tagA", "tagC", "tagB"})
+	if strings.Join(tags, ",") != "tagA,tagB,tagC" {
+		t.Errorf("Unexpected tags %v", tags)
+	}
+}
+
+func BenchmarkLoadLargeConfig(b *testing.B) {
+	config := Config{Fuzzy: true}
+	for i := 0; i < 50000; i++ {
+		// every tenth entry is a duplicate
+		n := i
+		if i%10 == 9 {
+			n--
+		}
+		config.Skip = append(config.Skip, fmt.Sprintf("quarantined-test-%d", n))
+	}
+	var jsonBuf bytes.Buffer
+	if err := json.NewEncoder(&jsonBuf).Encode(config); err != nil {
+		b.Fatal(err)
+	}
+	yamlBytes, err := yaml.Marshal(config)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("json", func(b *testing.B) {
+		b.ReportAllocs()
+		var tc *TestContext
+		for i := 0; i < b.N; i++ {
+			config, err := decodeJSONConfig(bytes.NewReader(jsonBuf.Bytes()))
+			if err != nil {
+				b.Fatal(err)
+			}
+			tc, _ = fromConfig(config)
+		}
+		b.ReportMetric(float64(tc.MemoryUsage()), "tag-bytes")
+	})
+	b.Run("yaml", func(b *testing.B) {
+		b.ReportAllocs()
+		var tc *TestContext
+		for i := 0; i < b.N; i++ {
+			config, err := loadYAMLConfig(bytes.NewReader(yamlBytes))
+			if err != nil {
+				b.Fatal(err)
+			}
+			tc, _ = fromConfig(config)
+		}
+		b.ReportMetric(float64(tc.MemoryUsage()), "tag-bytes")
+	})
+}
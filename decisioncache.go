@@ -0,0 +1,63 @@
+package gotag
+
+import "sync"
+
+// decisionCache memoizes the decisions of shouldSkip for tests with a
+// single tag, so that large table driven suites don't repeat fuzzy
+// scans. Entries are valid for the generation of the context and its
+// ancestors they were computed in, see invalidate
+type decisionCache struct {
+	mu      sync.RWMutex
+	entries map[string]cachedDecision
+}
+
+type cachedDecision struct {
+	generation uint64
+
+	// the exported fields and the short mode the decision depends on
+	mode     Mode
+	fuzzy    bool
+	distance int
+	short    bool
+
+	match, tag string
+	reason     skipReason
+}
+
+// returns the cached decision for the tag if it is still valid
+func (c *decisionCache) get(tag string, valid cachedDecision) (cachedDecision, bool) {
+	c.mu.RLock()
+	d, ok := c.entries[tag]
+	c.mu.RUnlock()
+	if !ok || d.generation != valid.generation || d.mode != valid.mode || d.fuzzy != valid.fuzzy ||
+		d.distance != valid.distance || d.short != valid.short {
+		return cachedDecision{}, false
+	}
+	return d, true
+}
+
+func (c *decisionCache) put(tag string, d cachedDecision) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]cachedDecision)
+	}
+	c.entries[tag] = d
+}
+
+// invalidates the cached decisions of the context and its children.
+// Must be called with the lock held by every method changing the tags,
+// groups, selector or defaults that decisions depend on
+func (tc *TestContext) invalidate() {
+	tc.generation.Add(1)
+}
+
+// returns the sum of the generations of the context and its ancestors,
+// which changes whenever any of them is invalidated
+func (tc *TestContext) chainGeneration() uint64 {
+	g := tc.generation.Load()
+	for p := tc.parent; p != nil; p = p.parent {
+		g += p.generation.Load()
+	}
+	return g
+}
@@ -0,0 +1,83 @@
+package gotag
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDecisionCacheInvalidation(t *testing.T) {
+	tc := New()
+	tc.Skip("integration")
+	if skip, _ := tc.WouldSkip("unit"); skip {
+		t.Fatal("Expected unit tests to run")
+	}
+	if len(tc.cache.entries) != 1 {
+		t.Errorf("Expected the decision to be cached, got %d entries", len(tc.cache.entries))
+	}
+
+	tc.Skip("unit")
+	if skip, _ := tc.WouldSkip("unit"); !skip {
+		t.Error("Expected Skip to invalidate the cached decision")
+	}
+	tc.Unskip("unit")
+	if skip, _ := tc.WouldSkip("unit"); skip {
+		t.Error("Expected Unskip to invalidate the cached decision")
+	}
+
+	if skip, _ := tc.WouldSkip("integratoin"); skip {
+		t.Fatal("Expected no fuzzy match while fuzzy matching is off")
+	}
+	tc.Fuzzy = true
+	if skip, _ := tc.WouldSkip("integratoin"); !skip {
+		t.Error("Expected enabling fuzzy matching to invalidate the cached decision")
+	}
+
+	child := tc.Child()
+	if skip, _ := child.WouldSkip("e2e"); skip {
+		t.Fatal("Expected e2e tests to run")
+	}
+	tc.Skip("e2e")
+	if skip, _ := child.WouldSkip("e2e"); !skip {
+		t.Error("Expected changes to the parent to invalidate the child's decisions")
+	}
+}
+
+func TestDecisionCacheAllocs(t *testing.T) {
+	tc := New()
+	tc.Fuzzy = true
+	for i := 0; i < 512; i++ {
+		tc.Skip(fmt.Sprintf("generated-tag-%03d", i))
+	}
+	mock := &mockT{}
+	fn := func(t T) {}
+	allocs := testing.AllocsPerRun(100, func() {
+		tc.Test("unrelated", mock, fn)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations for a cached fuzzy miss, got %v", allocs)
+	}
+}
+
+func BenchmarkDecisionCache(b *testing.B) {
+	tc := New()
+	tc.Fuzzy = true
+	for i := 0; i < 4096; i++ {
+		tc.Skip(benchTag(i, 16))
+	}
+	mock := &mockT{}
+	fn := func(t T) {}
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tc.Test("no-match-at-all", mock, fn)
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			tc.mu.RLock()
+			tc.decide([]string{"no-match-at-all"})
+			tc.mu.RUnlock()
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package gotag
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// EnvDecisionLog is the environment variable holding the path
+// that Main streams every gotag decision to as JSON lines
+const EnvDecisionLog = "GOTAG_DECISION_LOG"
+
+// Decision is a decision to run or skip a test, passed to reporters
+// and written as a line of the decision log
+type Decision struct {
+	Time time.Time `json:"time"`
+	Test string    `json:"test,omitempty"`
+	Tags []string  `json:"tags"`
+	// Tag is the tag that decided whether the test was skipped
+	// and Match the tag it matched by fuzzy matching, if any
+	Tag     string `json:"tag,omitempty"`
+	Match   string `json:"match,omitempty"`
+	Skipped bool   `json:"skipped"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// decisionLog serializes the lines written by parallel tests. It is
+// the Reporter returned by NewJSONLReporter
+type decisionLog struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// writes an entry as a single line, keeping the first write error
+func (l *decisionLog) write(e Decision) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	data = append(data, '\n')
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err == nil {
+		_, l.err = l.w.Write(data)
+	}
+}
+
+// LogDecisions streams every decision to run or skip a test to w as a
+// line of JSON holding the time, the test name, its tags, the tag that
+// decided, the fuzzy match if any, whether the test was skipped and
+// why, so that CI tooling can diff the tests excluded between runs.
+// Passing nil stops logging
+//
+//	{"time":"...","test":"TestCheckout","tags":["integration"],"tag":"integration","skipped":true,"reason":"in skip list"}
+func (tc *TestContext) LogDecisions(w io.Writer) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if w == nil {
+		tc.decisionLog = nil
+		return
+	}
+	tc.decisionLog = &decisionLog{w: w}
+}
+
+// LogDecisions streams every decision of the default context to w
+func LogDecisions(w io.Writer) {
+	Default().LogDecisions(w)
+}
+
+// WithDecisionLog streams every decision to w as JSON lines
+func WithDecisionLog(w io.Writer) Option {
+	return func(tc *TestContext) error {
+		tc.LogDecisions(w)
+		return nil
+	}
+}
+
+// WithDecisionLogFile sets the path that Main streams
+// every gotag decision to as JSON lines while tests run
+func WithDecisionLogFile(path string) Option {
+	return func(tc *TestContext) error {
+		tc.decisionLogPath = path
+		return nil
+	}
+}
+
+// creates the decision log file at path and starts logging to it.
+// Returns a func closing the file and reporting write errors
+func (tc *TestContext) openDecisionLog(path string) (func() error, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	tc.LogDecisions(f)
+	return func() error {
+		tc.mu.Lock()
+		l := tc.decisionLog
+		tc.decisionLog = nil
+		tc.mu.Unlock()
+		err := f.Close()
+		if l != nil && l.err != nil {
+			err = l.err
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,71 @@
+package gotag
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLogDecisions(t *testing.T) {
+	var buf bytes.Buffer
+	tc := New(WithDecisionLog(&buf))
+	tc.Skip("tagA")
+	tc.Fuzzy = true
+
+	mock := &mockT{}
+	tc.Test("tagA", mock, func(t T) {})
+	tc.Test("taga", mock, func(t T) {})
+	tc.Test("unit", mock, func(t T) {})
+
+	var entries []Decision
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e Decision
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Expected a JSON line, got %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected a line per decision, got %d", len(entries))
+	}
+	if e := entries[0]; !e.Skipped || e.Reason != "in skip list" || e.Time.IsZero() {
+		t.Errorf("Unexpected skip entry %+v", e)
+	}
+	if e := entries[1]; !e.Skipped || e.Match != "tagA" || e.Tag != "taga" {
+		t.Errorf("Unexpected fuzzy entry %+v", e)
+	}
+	if e := entries[2]; e.Skipped || len(e.Tags) != 1 || e.Tags[0] != "unit" {
+		t.Errorf("Unexpected run entry %+v", e)
+	}
+
+	tc.LogDecisions(nil)
+	tc.Test("tagA", mock, func(t T) {})
+	if buf.Len() != 0 {
+		t.Errorf("Expected no lines once logging stopped, got %q", buf.String())
+	}
+}
+
+func TestDecisionLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "decisions.jsonl")
+	tc := New()
+	tc.Skip("tagA")
+	closeLog, err := tc.openDecisionLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.Test("tagA", &mockT{}, func(t T) {})
+	if err := closeLog(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Count(data, []byte("\n")) != 1 {
+		t.Errorf("Expected a single line, got %q", data)
+	}
+}
@@ -0,0 +1,89 @@
+package gotag
+
+// Requires declares that tests under the tag, or a tag in its
+// namespace, depend on what the required tags test. If a required tag
+// is skipped the tag is skipped as well, and marking the tag by RunOnly
+// runs tests under its required tags too. Dependencies are transitive
+//
+//	tc.Requires(gotag.EndToEnd, gotag.Integration)
+func (tc *TestContext) Requires(tag string, required ...string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	key := tc.canonical(tag)
+	tc.dependencies[key] = append(tc.dependencies[key], required...)
+	tc.invalidate()
+}
+
+// Requires declares that tests under the tag depend on
+// the required tags within the default context
+func Requires(tag string, required ...string) {
+	Default().Requires(tag, required...)
+}
+
+// returns the first tag required, directly or not, by the canonical tag
+// or its namespaces that satisfies the predicate. Must be called with
+// at least a read lock held
+func (tc *TestContext) findRequired(key string, pred func(required string) bool) (string, bool) {
+	if len(tc.dependencies) == 0 {
+		return "", false
+	}
+	seen := make(map[string]bool)
+	var visit func(key string) (string, bool)
+	visit = func(key string) (string, bool) {
+		for i := 0; i <= len(key); i++ {
+			if i < len(key) && key[i] != '.' {
+				continue
+			}
+			for _, required := range tc.dependencies[key[:i]] {
+				rkey := tc.canonical(required)
+				if seen[rkey] {
+					continue
+				}
+				seen[rkey] = true
+				if pred(rkey) {
+					return required, true
+				}
+				if found, ok := visit(rkey); ok {
+					return found, true
+				}
+			}
+		}
+		return "", false
+	}
+	return visit(key)
+}
+
+// returns the first of the tags that requires a skipped tag, along
+// with that tag. Must be called with at least a read lock held
+func (tc *TestContext) requiresSkipped(tags []string) (string, string, bool) {
+	for _, tag := range tags {
+		required, ok := tc.findRequired(tc.canonical(tag), func(required string) bool {
+			return tc.inheritedCovers(skipSet, required)
+		})
+		if ok {
+			return tag, required, true
+		}
+	}
+	return "", "", false
+}
+
+// returns the first of the tags required by a tag marked by RunOnly.
+// Must be called with at least a read lock held
+func (tc *TestContext) requiredByRunOnly(tags []string) (string, bool) {
+	for _, tag := range tags {
+		key := tc.canonical(tag)
+		for dependent := range tc.dependencies {
+			if !tc.inheritedCovers(runOnlySet, dependent) {
+				continue
+			}
+			_, ok := tc.findRequired(dependent, func(required string) bool {
+				return key == required || len(key) > len(required) &&
+					key[len(required)] == '.' && key[:len(required)] == required
+			})
+			if ok {
+				return tag, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,82 @@
+package gotag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequiresSkip(t *testing.T) {
+	tc := New()
+	tc.Requires("e2e", "integration")
+	tc.Requires("smoke", "e2e.checkout")
+	tc.Skip("integration")
+
+	for tag, expected := range map[string]bool{
+		"e2e":          true,
+		"e2e.checkout": true,
+		"smoke":        true,
+		"integration":  true,
+		"unit":         false,
+	} {
+		if skipped, _ := tc.WouldSkip(tag); skipped != expected {
+			t.Errorf("Expected tag %s to be skipped: %v", tag, expected)
+		}
+	}
+	if _, why := tc.WouldSkip("smoke"); why != "required tag skipped" {
+		t.Errorf("Unexpected reason %s", why)
+	}
+
+	mock := &messageT{}
+	tc.Test("smoke", mock, func(t T) {})
+	if len(mock.messages) != 1 || mock.messages[0] != "skipped by gotag: tag 'smoke' requires skipped tag 'integration'" {
+		t.Errorf("Unexpected messages %q", mock.messages)
+	}
+
+	tc.Unskip("integration")
+	if skipped, _ := tc.WouldSkip("smoke"); skipped {
+		t.Error("Expected dependents to run once the required tag is unskipped")
+	}
+}
+
+func TestRequiresRunOnly(t *testing.T) {
+	tc := New()
+	tc.Requires("e2e", "integration")
+	tc.Requires("integration", "db", "e2e")
+	tc.RunOnly("e2e")
+
+	for tag, expected := range map[string]bool{
+		"e2e":            false,
+		"integration":    false,
+		"integration.pg": false,
+		"db":             false,
+		"unit":           true,
+	} {
+		if skipped, _ := tc.WouldSkip(tag); skipped != expected {
+			t.Errorf("Expected tag %s to be skipped: %v", tag, expected)
+		}
+	}
+}
+
+func TestRequiresConfig(t *testing.T) {
+	tc := New()
+	if err := tc.Apply(&Config{Depends: map[string][]string{"e2e": {"integration"}}, Skip: []string{"integration"}}); err != nil {
+		t.Fatal(err)
+	}
+	if skipped, _ := tc.WouldSkip("e2e"); !skipped {
+		t.Error("Expected the config dependencies to apply")
+	}
+	c := tc.Clone()
+	tc.Reset()
+	if skipped, _ := c.WouldSkip("e2e"); !skipped {
+		t.Error("Expected the clone to keep the dependencies")
+	}
+	if len(tc.dependencies) != 0 {
+		t.Error("Expected Reset to remove the dependencies")
+	}
+
+	merged := MergeConfigs(&Config{Depends: map[string][]string{"e2e": {"db"}, "smoke": {"unit"}}},
+		&Config{Depends: map[string][]string{"e2e": {"integration"}}})
+	if deps := strings.Join(merged.Depends["e2e"], ","); deps != "integration" || len(merged.Depends) != 2 {
+		t.Errorf("Expected the nearer dependencies to override, got %v", merged.Depends)
+	}
+}
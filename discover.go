@@ -0,0 +1,247 @@
+package gotag
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// EnvUserConfig is the environment variable holding the path of
+// the user level config file, or off to ignore it
+const EnvUserConfig = "GOTAG_USER_CONFIG"
+
+// returns the directory holding user level config files
+func userConfigDir() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config"), nil
+}
+
+// MergeConfigs merges the configs in increasing order of precedence,
+// the way Load merges the config files it discovers: tags accumulate
+// and options set by later configs override those of earlier ones.
+// Nil configs are ignored and none of the configs are modified
+func MergeConfigs(configs ...*Config) *Config {
+	merged := &Config{}
+	for _, config := range configs {
+		if config != nil {
+			merged.merge(config)
+		}
+	}
+	return merged
+}
+
+// ConfigFiles returns the paths of the config files Load discovers from
+// dir, farthest first so that later files take precedence: the user
+// level config, then the .gotag.json or .gotag.yml file and the skip
+// file of each directory from the repository root, marked by a .git
+// entry, down to dir
+func ConfigFiles(dir string) ([]string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// nearest first
+	var paths []string
+	for {
+		if path := filepath.Join(dir, SkipFile); exists(path) {
+			paths = append(paths, path)
+		}
+		for _, name := range []string{".gotag.json", ".gotag.yml"} {
+			if path := filepath.Join(dir, name); exists(path) {
+				// the JSON config takes precedence
+				paths = append(paths, path)
+				break
+			}
+		}
+		if exists(filepath.Join(dir, ".git")) {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	if user := userConfigFile(); user != "" {
+		paths = append(paths, user)
+	}
+
+	// farthest first
+	for i, j := 0, len(paths)-1; i < j; i, j = i+1, j-1 {
+		paths[i], paths[j] = paths[j], paths[i]
+	}
+	return paths, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// discovers the config files of dir with ConfigFiles and merges them
+// so that nearer configs override farther ones. Returns ErrNoConfig if
+// no config file was found
+func discoverConfig(dir string) (*Config, error) {
+	paths, err := ConfigFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	user := userConfigFile()
+	var configs []*Config
+	for _, path := range paths {
+		config, err := loadDiscovered(path)
+		if err == ErrNoConfig {
+			// removed since it was discovered
+			continue
+		}
+		if _, located := err.(ConfigErrors); err != nil && !located && path == user {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	if len(configs) == 0 {
+		return nil, ErrNoConfig
+	}
+	return MergeConfigs(configs...), nil
+}
+
+// loads a config file found by ConfigFiles, merging in the configs it
+// extends. Configs other than JSON ones are read as YAML
+func loadDiscovered(path string) (*Config, error) {
+	if filepath.Base(path) == SkipFile {
+		return loadCachedConfig(path, loadSkipFile)
+	}
+	load := loadYAMLConfig
+	if filepath.Ext(path) == ".json" {
+		load = loadJSONConfig
+	}
+	config, err := loadCachedConfig(path, load)
+	if err != nil {
+		return nil, err
+	}
+	return resolveExtends(config)
+}
+
+// returns the path of the user level config, gotag/config.yml or
+// gotag/config.json within $XDG_CONFIG_HOME or ~/.config unless
+// GOTAG_USER_CONFIG names another path, or "" if there is none
+func userConfigFile() string {
+	var paths []string
+	switch path := os.Getenv(EnvUserConfig); path {
+	case "off":
+		return ""
+	case "":
+		dir, err := userConfigDir()
+		if err != nil {
+			return ""
+		}
+		paths = []string{filepath.Join(dir, "gotag", "config.yml"), filepath.Join(dir, "gotag", "config.json")}
+	default:
+		paths = []string{path}
+	}
+	for _, path := range paths {
+		if exists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// merges a nearer config into c. Tags accumulate, fuzzy matching and
+// dry runs are enabled if either config enables them, and the edit
+// distance, the selector, the default, the mode, the shard, the
+// profile, groups, timeouts and build tag mappings are overridden if
+// the nearer config sets them, the same way Apply merges a config into
+// a context. Profiles of the same name are merged
+func (c *Config) merge(nearer *Config) {
+	c.Skip = append(c.Skip, nearer.Skip...)
+	c.SkipEntries = append(c.SkipEntries, nearer.SkipEntries...)
+	c.Run = append(c.Run, nearer.Run...)
+	c.SkipIf = append(c.SkipIf, nearer.SkipIf...)
+	c.ShortSkips = append(c.ShortSkips, nearer.ShortSkips...)
+	c.MustRun = append(c.MustRun, nearer.MustRun...)
+	c.Quarantine = append(c.Quarantine, nearer.Quarantine...)
+	if nearer.Fuzzy {
+		c.Fuzzy = true
+	}
+	if nearer.DryRun {
+		c.DryRun = true
+	}
+	if nearer.SuggestTags {
+		c.SuggestTags = true
+	}
+	if nearer.StrictTags {
+		c.StrictTags = true
+	}
+	if nearer.RequireRegistered {
+		c.RequireRegistered = true
+	}
+	if nearer.Strict {
+		c.Strict = true
+	}
+	c.Tags = append(c.Tags, nearer.Tags...)
+	if nearer.EditDistance > 0 {
+		c.EditDistance = nearer.EditDistance
+	}
+	if nearer.Selector != "" {
+		c.Selector = nearer.Selector
+	}
+	if nearer.Default != "" {
+		c.Default = nearer.Default
+	}
+	if nearer.Mode != "" {
+		c.Mode = nearer.Mode
+	}
+	if nearer.DistanceFunc != "" {
+		c.DistanceFunc = nearer.DistanceFunc
+	}
+	if nearer.Shard != "" {
+		c.Shard = nearer.Shard
+	}
+	if nearer.Webhook != "" {
+		c.Webhook = nearer.Webhook
+	}
+	for tag, required := range nearer.Depends {
+		if c.Depends == nil {
+			c.Depends = make(map[string][]string)
+		}
+		c.Depends[tag] = required
+	}
+	for name, members := range nearer.Groups {
+		if c.Groups == nil {
+			c.Groups = make(map[string][]string)
+		}
+		c.Groups[name] = members
+	}
+	for build, tag := range nearer.BuildTags {
+		if c.BuildTags == nil {
+			c.BuildTags = make(map[string]string)
+		}
+		c.BuildTags[build] = tag
+	}
+	if nearer.Profile != "" {
+		c.Profile = nearer.Profile
+	}
+	for name, profile := range nearer.Profiles {
+		if c.Profiles == nil {
+			c.Profiles = make(map[string]*Config)
+		}
+		c.Profiles[name] = MergeConfigs(c.Profiles[name], profile)
+	}
+	for tag, d := range nearer.Timeouts {
+		if c.Timeouts == nil {
+			c.Timeouts = make(map[string]string)
+		}
+		c.Timeouts[tag] = d
+	}
+}
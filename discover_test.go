@@ -0,0 +1,144 @@
+package gotag
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadDiscoversParentConfigs(t *testing.T) {
+	t.Setenv(EnvUserConfig, "off")
+	root := t.TempDir()
+	pkg := filepath.Join(root, "internal", "pkg")
+	if err := os.MkdirAll(pkg, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(root, ".gotag.yml"): "skip: [integration]\ndistance: 3\n",
+		filepath.Join(pkg, ".gotag.json"): `{"skip": ["slow"], "distance": 1}`,
+	}
+	for path, content := range files {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(pkg); err != nil {
+		t.Fatal(err)
+	}
+
+	tc, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "integration,slow" {
+		t.Errorf("Expected tags from both configs, got %s", tags)
+	}
+	if tc.EditDistance != 1 {
+		t.Errorf("Expected the nearer distance of 1, got %d", tc.EditDistance)
+	}
+
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	tc, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "integration" {
+		t.Errorf("Expected only the root config, got %s", tags)
+	}
+}
+
+func TestConfigFiles(t *testing.T) {
+	root := t.TempDir()
+	pkg := filepath.Join(root, "pkg")
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(pkg, 0755); err != nil {
+		t.Fatal(err)
+	}
+	user := filepath.Join(t.TempDir(), "config.yml")
+	t.Setenv(EnvUserConfig, user)
+	files := []string{
+		user,
+		filepath.Join(root, ".gotag.yml"),
+		filepath.Join(root, SkipFile),
+		filepath.Join(pkg, ".gotag.json"),
+	}
+	for _, path := range append(files, filepath.Join(pkg, ".gotag.yml")) {
+		if err := ioutil.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	paths, err := ConfigFiles(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(paths, "\n"), strings.Join(files, "\n"); got != want {
+		t.Errorf("Expected config files\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestLoadMergesUserConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(EnvUserConfig, "")
+	t.Setenv("XDG_CONFIG_HOME", home)
+	if err := os.Mkdir(filepath.Join(home, "gotag"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	user := "skip: [docker]\ndistance: 4\nmode: intersect\n"
+	if err := ioutil.WriteFile(filepath.Join(home, "gotag", "config.yml"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, ".gotag.yml"), []byte("skip: [integration]\ndistance: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := discoverConfig(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(config.Skip, ","); tags != "docker,integration" {
+		t.Errorf("Expected the user's skips to add to the repository's, got %s", tags)
+	}
+	if config.EditDistance != 1 || config.Mode != "intersect" {
+		t.Errorf("Expected the repository config to override the user config, got %+v", config)
+	}
+
+	t.Setenv(EnvUserConfig, "off")
+	if config, err = discoverConfig(root); err != nil || len(config.Skip) != 1 {
+		t.Errorf("Expected the user config to be ignored, got %+v, %v", config, err)
+	}
+}
+
+func TestMergeConfigs(t *testing.T) {
+	a := &Config{Skip: []string{"tagA"}, EditDistance: 3, Groups: map[string][]string{"g": {"tagA"}}}
+	b := &Config{Skip: []string{"tagB"}, Selector: "speed=fast"}
+	merged := MergeConfigs(a, nil, b)
+	if tags := strings.Join(merged.Skip, ","); tags != "tagA,tagB" {
+		t.Errorf("Expected accumulated tags, got %s", tags)
+	}
+	if merged.EditDistance != 3 || merged.Selector != "speed=fast" || len(merged.Groups) != 1 {
+		t.Errorf("Unexpected merged config %+v", merged)
+	}
+	if len(a.Skip) != 1 {
+		t.Error("Expected the merged configs not to be modified")
+	}
+}
@@ -0,0 +1,162 @@
+package gotag
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DistanceFunc measures how far apart two tags are for fuzzy
+// matching. Tags match if their distance is at most EditDistance
+type DistanceFunc func(a, b string) int
+
+// Levenshtein counts the insertions, deletions and substitutions of
+// runes turning one tag into the other. It is the default DistanceFunc
+func Levenshtein(a, b string) int {
+	return levenshtein(a, b)
+}
+
+// DamerauLevenshtein counts the insertions, deletions, substitutions
+// and transpositions of adjacent runes turning one tag into the other,
+// so that typos such as intgeration are a single edit away
+func DamerauLevenshtein(a, b string) int {
+	if a == b {
+		return 0
+	}
+	r1, r2 := []rune(a), []rune(b)
+	// three rows of the optimal string alignment matrix
+	rows := make([]int, 3*(len(r2)+1))
+	prev2, prev, cur := rows[:len(r2)+1], rows[len(r2)+1:2*(len(r2)+1)], rows[2*(len(r2)+1):]
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(r1); i++ {
+		cur[0] = i
+		for j := 1; j <= len(r2); j++ {
+			cost := 1
+			if r1[i-1] == r2[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && r1[i-1] == r2[j-2] && r1[i-2] == r2[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(r2)]
+}
+
+// JaroWinkler scores how different two tags are from their
+// Jaro-Winkler similarity, which favors tags sharing a prefix, in
+// tenths: tags within a distance of 1 are at least 90% similar
+func JaroWinkler(a, b string) int {
+	return int(math.Round((1 - jaroWinkler([]rune(a), []rune(b))) * 10))
+}
+
+// returns the Jaro-Winkler similarity of the runes, between 0 and 1.
+// See https://en.wikipedia.org/wiki/Jaro%E2%80%93Winkler_distance
+func jaroWinkler(r1, r2 []rune) float64 {
+	if len(r1) == 0 && len(r2) == 0 {
+		return 1
+	}
+	window := max(len(r1), len(r2))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	matched1 := make([]bool, len(r1))
+	matched2 := make([]bool, len(r2))
+	matches := 0
+	for i, r := range r1 {
+		for j := max(0, i-window); j < min(len(r2), i+window+1); j++ {
+			if !matched2[j] && r2[j] == r {
+				matched1[i], matched2[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	transpositions, j := 0, 0
+	for i, r := range r1 {
+		if !matched1[i] {
+			continue
+		}
+		for !matched2[j] {
+			j++
+		}
+		if r != r2[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	jaro := (m/float64(len(r1)) + m/float64(len(r2)) + (m-float64(transpositions/2))/m) / 3
+	prefix := 0
+	for prefix < min(4, len(r1), len(r2)) && r1[prefix] == r2[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// PrefixMatch matches tags if either is a prefix of the other, whatever
+// the EditDistance, so that abbreviations such as integ match
+func PrefixMatch(a, b string) int {
+	if strings.HasPrefix(a, b) || strings.HasPrefix(b, a) {
+		return 0
+	}
+	return math.MaxInt32
+}
+
+// ParseDistanceFunc returns the built in DistanceFunc with the given
+// name: levenshtein, damerau-levenshtein, jaro-winkler or prefix
+func ParseDistanceFunc(name string) (DistanceFunc, error) {
+	switch name {
+	case "levenshtein":
+		return Levenshtein, nil
+	case "damerau-levenshtein":
+		return DamerauLevenshtein, nil
+	case "jaro-winkler":
+		return JaroWinkler, nil
+	case "prefix":
+		return PrefixMatch, nil
+	default:
+		return nil, fmt.Errorf("Invalid distance function '%s', expected levenshtein, damerau-levenshtein, jaro-winkler or prefix", name)
+	}
+}
+
+// SetDistanceFunc sets how far apart tags are for fuzzy matching.
+// Passing nil restores the default, Levenshtein
+//
+//	tc.SetDistanceFunc(gotag.DamerauLevenshtein)
+func (tc *TestContext) SetDistanceFunc(f DistanceFunc) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.distanceFunc = f
+	tc.invalidate()
+}
+
+// SetDistanceFunc sets how far apart tags are for
+// fuzzy matching within the default context
+func SetDistanceFunc(f DistanceFunc) {
+	Default().SetDistanceFunc(f)
+}
+
+// WithDistanceFunc sets how far apart tags are for fuzzy matching
+func WithDistanceFunc(f DistanceFunc) Option {
+	return func(tc *TestContext) error {
+		tc.SetDistanceFunc(f)
+		return nil
+	}
+}
+
+// returns the distance between the tags. Must be
+// called with at least a read lock held
+func (tc *TestContext) distance(a, b string) int {
+	if tc.distanceFunc == nil {
+		return levenshtein(a, b)
+	}
+	return tc.distanceFunc(a, b)
+}
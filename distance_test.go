@@ -0,0 +1,97 @@
+package gotag
+
+import "testing"
+
+func TestDistanceFuncs(t *testing.T) {
+	cases := []struct {
+		name     string
+		f        DistanceFunc
+		a, b     string
+		expected int
+	}{
+		{"levenshtein", Levenshtein, "intgeration", "integration", 2},
+		{"damerau-levenshtein", DamerauLevenshtein, "intgeration", "integration", 1},
+		{"damerau-levenshtein", DamerauLevenshtein, "ca", "abc", 3},
+		{"damerau-levenshtein", DamerauLevenshtein, "café", "cafe", 1},
+		{"damerau-levenshtein", DamerauLevenshtein, "", "unit", 4},
+		{"jaro-winkler", JaroWinkler, "MARTHA", "MARHTA", 0},
+		{"jaro-winkler", JaroWinkler, "DWAYNE", "DUANE", 2},
+		{"jaro-winkler", JaroWinkler, "unit", "integration", 4},
+		{"jaro-winkler", JaroWinkler, "abc", "xyz", 10},
+		{"prefix", PrefixMatch, "integ", "integration", 0},
+		{"prefix", PrefixMatch, "integration", "integ", 0},
+	}
+	for _, c := range cases {
+		if d := c.f(c.a, c.b); d != c.expected {
+			t.Errorf("Expected %s distance of %d between %s and %s, got %d", c.name, c.expected, c.a, c.b, d)
+		}
+	}
+	if PrefixMatch("integ", "unit") <= 100 {
+		t.Error("Expected tags without a common prefix to be far apart")
+	}
+}
+
+func TestParseDistanceFunc(t *testing.T) {
+	for _, name := range []string{"levenshtein", "damerau-levenshtein", "jaro-winkler", "prefix"} {
+		if f, err := ParseDistanceFunc(name); err != nil || f == nil {
+			t.Errorf("Expected %s to parse, got %v", name, err)
+		}
+	}
+	if _, err := ParseDistanceFunc("hamming"); err == nil {
+		t.Error("Expected an unknown distance function to be rejected")
+	}
+}
+
+func TestSetDistanceFunc(t *testing.T) {
+	tc := New()
+	tc.Skip("integration")
+	tc.Fuzzy = true
+	tc.EditDistance = 1
+
+	mock := &mockT{}
+	tc.Test("intgeration", mock, func(t T) {})
+	if mock.skipped != 0 {
+		t.Error("Expected transposed letters to be two edits apart by default")
+	}
+	tc.SetDistanceFunc(DamerauLevenshtein)
+	tc.Test("intgeration", mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Error("Expected transposed letters to be a single edit apart")
+	}
+	if c := tc.Clone(); c.distanceFunc == nil {
+		t.Error("Expected the clone to keep the distance function")
+	}
+
+	tc.SetDistanceFunc(PrefixMatch)
+	tc.Test("integ", mock, func(t T) {})
+	if mock.skipped != 2 {
+		t.Error("Expected a prefix of a skipped tag to be skipped")
+	}
+	tc.SetDistanceFunc(nil)
+	tc.Test("integ", mock, func(t T) {})
+	if mock.skipped != 2 {
+		t.Error("Expected nil to restore the default distance")
+	}
+}
+
+func TestApplyDistanceFunc(t *testing.T) {
+	tc := New()
+	if err := tc.Apply(&Config{DistanceFunc: "soundex", Skip: []string{"tagA"}}); err == nil {
+		t.Error("Expected an invalid distance function to be rejected")
+	}
+	if len(tc.SkippedTags()) != 0 {
+		t.Error("Expected an invalid config not to be applied")
+	}
+	if err := tc.Apply(&Config{DistanceFunc: "prefix", Skip: []string{"integration"}, Fuzzy: true}); err != nil {
+		t.Fatal(err)
+	}
+	mock := &mockT{}
+	tc.Test("integ", mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Error("Expected the configured distance function to be used")
+	}
+	merged := MergeConfigs(&Config{DistanceFunc: "prefix"}, &Config{DistanceFunc: "jaro-winkler"}, &Config{})
+	if merged.DistanceFunc != "jaro-winkler" {
+		t.Errorf("Expected the nearer distance function, got %s", merged.DistanceFunc)
+	}
+}
@@ -0,0 +1,125 @@
+// Package dockercheck provides requirement predicates for tests
+// depending on containers, so that their tags are skipped on hosts
+// without Docker rather than failing
+//
+//	func TestMain(m *testing.M) {
+//		gotag.Require("docker", dockercheck.RequireDockerDaemon())
+//		gotag.Require("postgres", dockercheck.RequireImage("postgres:15"))
+//		gotag.Require("stack", dockercheck.RequireComposeFile("docker-compose.test.yml"))
+//		os.Exit(gotag.Main(m))
+//	}
+//
+// The daemon is reached through its API, at DOCKER_HOST if set or at
+// the default socket otherwise, so the docker command is only needed
+// by RequireComposeFile
+package dockercheck
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Timeout bounds each check made by the predicates
+var Timeout = 10 * time.Second
+
+// the socket the daemon listens on when DOCKER_HOST is not set
+const defaultHost = "unix:///var/run/docker.sock"
+
+// RequireDockerDaemon returns a predicate that holds if
+// a docker daemon answers on DOCKER_HOST
+func RequireDockerDaemon() func() bool {
+	return func() bool {
+		return get("/_ping") == http.StatusOK
+	}
+}
+
+// RequireImage returns a predicate that holds if a docker daemon answers
+// and has every given image, such as postgres:15, without pulling them.
+// Images are pulled beforehand, e.g. by a CI step, to run their tests
+func RequireImage(images ...string) func() bool {
+	return func() bool {
+		for _, image := range images {
+			if get("/images/"+image+"/json") != http.StatusOK {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// RequireComposeFile returns a predicate that holds if a docker daemon
+// answers, the compose file exists and docker compose, or docker-compose,
+// validates it
+func RequireComposeFile(path string) func() bool {
+	return func() bool {
+		if _, err := os.Stat(path); err != nil {
+			return false
+		}
+		if get("/_ping") != http.StatusOK {
+			return false
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+		defer cancel()
+		if compose(ctx, "docker", "compose", "-f", path, "config", "-q").Run() == nil {
+			return true
+		}
+		return compose(ctx, "docker-compose", "-f", path, "config", "-q").Run() == nil
+	}
+}
+
+// builds a docker compose command. A variable for tests
+var compose = exec.CommandContext
+
+// requests the path of the docker API, returning the status
+// code or 0 if the daemon could not be reached
+func get(path string) int {
+	client, base, ok := daemon()
+	if !ok {
+		return 0
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+path, nil)
+	if err != nil {
+		return 0
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// returns a client for the daemon at DOCKER_HOST and the base URL of
+// its API, or false if the address is not supported
+func daemon() (*http.Client, string, bool) {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = defaultHost
+	}
+	switch {
+	case strings.HasPrefix(host, "unix://"):
+		socket := strings.TrimPrefix(host, "unix://")
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		return &http.Client{Transport: transport}, "http://docker", true
+	case strings.HasPrefix(host, "tcp://"):
+		scheme := "http://"
+		if os.Getenv("DOCKER_TLS_VERIFY") != "" {
+			scheme = "https://"
+		}
+		return http.DefaultClient, scheme + strings.TrimPrefix(host, "tcp://"), true
+	default:
+		return nil, "", false
+	}
+}
@@ -0,0 +1,86 @@
+package dockercheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeDaemon serves the endpoints of the docker API the predicates use
+func fakeDaemon(t *testing.T, images ...string) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_ping" {
+			w.Write([]byte("OK"))
+			return
+		}
+		for _, image := range images {
+			if r.URL.Path == "/images/"+image+"/json" {
+				w.Write([]byte("{}"))
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("DOCKER_HOST", "tcp://"+strings.TrimPrefix(srv.URL, "http://"))
+	t.Setenv("DOCKER_TLS_VERIFY", "")
+}
+
+func TestRequireDockerDaemon(t *testing.T) {
+	fakeDaemon(t)
+	if !RequireDockerDaemon()() {
+		t.Error("Expected the daemon to be found")
+	}
+	t.Setenv("DOCKER_HOST", "unix://"+filepath.Join(t.TempDir(), "docker.sock"))
+	if RequireDockerDaemon()() {
+		t.Error("Expected a missing socket not to hold")
+	}
+	t.Setenv("DOCKER_HOST", "ssh://user@host")
+	if RequireDockerDaemon()() {
+		t.Error("Expected an unsupported host not to hold")
+	}
+}
+
+func TestRequireImage(t *testing.T) {
+	fakeDaemon(t, "postgres:15", "redis")
+	if !RequireImage("postgres:15", "redis")() {
+		t.Error("Expected the images to be found")
+	}
+	if RequireImage("postgres:15", "mysql:8")() {
+		t.Error("Expected a missing image not to hold")
+	}
+}
+
+func TestRequireComposeFile(t *testing.T) {
+	fakeDaemon(t)
+	defer func(f func(context.Context, string, ...string) *exec.Cmd) { compose = f }(compose)
+	var ran []string
+	valid := true
+	compose = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		ran = append(ran, name)
+		if valid && name == "docker-compose" {
+			return exec.CommandContext(ctx, "true")
+		}
+		return exec.CommandContext(ctx, "false")
+	}
+
+	path := filepath.Join(t.TempDir(), "docker-compose.test.yml")
+	if RequireComposeFile(path)() {
+		t.Error("Expected a missing compose file not to hold")
+	}
+	if err := os.WriteFile(path, []byte("services: {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !RequireComposeFile(path)() || strings.Join(ran, ",") != "docker,docker-compose" {
+		t.Errorf("Expected docker-compose to validate the file, ran %v", ran)
+	}
+	valid = false
+	if RequireComposeFile(path)() {
+		t.Error("Expected an invalid compose file not to hold")
+	}
+}
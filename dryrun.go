@@ -0,0 +1,12 @@
+package gotag
+
+import "strings"
+
+// logs the decision a dry run would have made for a test
+func (tc *TestContext) logDryRun(l Logger, tags []string, s skippable, tag, match string, reason skipReason, distance int) {
+	if reason.skipped() {
+		logTo(l, s, "gotag: dry run, would skip: %v", tc.skipMessage(tags, tag, match, reason, distance)...)
+		return
+	}
+	logTo(l, s, "gotag: dry run, would run tags '%s'", strings.Join(tags, ", "))
+}
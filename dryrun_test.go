@@ -0,0 +1,59 @@
+package gotag
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestDryRun(t *testing.T) {
+	var logged []string
+	tc := New(WithDryRun(), WithLogger(LoggerFunc(func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})))
+	tc.Skip(Integration)
+	tc.Timeout("unit", time.Nanosecond)
+
+	mock := &mockT{}
+	ran := 0
+	tc.Test(Integration, mock, func(t T) { ran++ })
+	tc.Test("unit", mock, func(t T) {
+		if _, ok := t.(*mockT); !ok {
+			t.Errorf("Expected the test not to be wrapped during a dry run, got %T", t)
+		}
+		ran++
+	})
+	if mock.skipped != 0 || ran != 2 {
+		t.Errorf("Expected every test to run during a dry run, %d skipped and %d ran", mock.skipped, ran)
+	}
+	want := []string{
+		"gotag: dry run, would skip: skipped by gotag: tag 'integration' is in skip list",
+		"gotag: dry run, would run tags 'unit'",
+	}
+	if fmt.Sprint(logged) != fmt.Sprint(want) {
+		t.Errorf("Expected %q to be logged, got %q", want, logged)
+	}
+}
+
+func TestDryRunRegistered(t *testing.T) {
+	var logged []string
+	tc := New(WithDryRun(), WithLogger(LoggerFunc(func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})))
+	tc.Register("TestCheckout", Integration)
+	tc.Skip(Integration)
+	tc.recording = true
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	skip := fs.String("test.skip", "", "")
+	if err := tc.skipRegistered(fs); err != nil {
+		t.Fatal(err)
+	}
+	if *skip != "" || len(tc.decisions) != 0 {
+		t.Errorf("Expected no test to be excluded during a dry run, got -test.skip=%s", *skip)
+	}
+	if len(logged) != 1 || logged[0] != "gotag: dry run, would skip registered test TestCheckout" {
+		t.Errorf("Unexpected log %q", logged)
+	}
+}
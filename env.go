@@ -0,0 +1,124 @@
+package gotag
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+const (
+	// EnvSkip is the environment variable holding a comma
+	// separated list of tags to skip
+	EnvSkip = "GOTAG_SKIP"
+
+	// EnvRun is the environment variable holding a comma
+	// separated list of tags to run
+	EnvRun = "GOTAG_RUN"
+
+	// EnvFuzzy is the environment variable that enables
+	// fuzzy matching when set to a true boolean value
+	EnvFuzzy = "GOTAG_FUZZY"
+
+	// EnvDistance is the environment variable holding the
+	// edit distance used for fuzzy matching
+	EnvDistance = "GOTAG_DISTANCE"
+
+	// EnvSelector is the environment variable holding
+	// a label selector, see ParseSelector
+	EnvSelector = "GOTAG_SELECTOR"
+
+	// EnvQuarantine is the environment variable holding a comma
+	// separated list of tags to quarantine
+	EnvQuarantine = "GOTAG_QUARANTINE"
+
+	// EnvShard is the environment variable holding the shard
+	// of tagged tests to run, e.g. 2/5, see Shard
+	EnvShard = "GOTAG_SHARD"
+
+	// EnvMode is the environment variable holding the mode
+	// deciding how skipped and run tags interact, see Mode
+	EnvMode = "GOTAG_MODE"
+
+	// EnvDryRun is the environment variable that enables
+	// dry runs when set to a true boolean value, see DryRun
+	EnvDryRun = "GOTAG_DRY_RUN"
+
+	// EnvTrace is the environment variable that, when set, makes every
+	// tagged test log its tag so that tools reading `go test -json`
+	// output can attribute test results to tags that can't be derived
+	// from test names, such as those of table driven cases
+	EnvTrace = "GOTAG_TRACE"
+)
+
+var traceTags = os.Getenv(EnvTrace) != ""
+
+// LoadEnv configures the TestContext instance from the GOTAG_SKIP,
+// GOTAG_RUN, GOTAG_FUZZY, GOTAG_DISTANCE, GOTAG_SELECTOR,
+// GOTAG_QUARANTINE, GOTAG_SHARD, GOTAG_MODE and GOTAG_DRY_RUN
+// environment variables.
+// Unset variables leave the context untouched. Returns an error if
+// a variable holds a malformed value
+func (tc *TestContext) LoadEnv() error {
+	config, err := envConfig()
+	if err != nil {
+		return err
+	}
+	return tc.Apply(config)
+}
+
+// LoadDefault configures the default context from the .gotag config
+// files discovered by Load, if any exist, followed by environment
+// variables. Returns an error if a config file
+// or environment variable is malformed
+func LoadDefault() error {
+	return Default().loadDefault()
+}
+
+func (tc *TestContext) loadDefault() error {
+	config, err := discoverConfig(".")
+	if err != nil && err != ErrNoConfig {
+		return err
+	}
+	if config != nil {
+		if err := tc.Apply(config); err != nil {
+			return err
+		}
+	}
+	return tc.LoadEnv()
+}
+
+// reads a config from environment variables
+func envConfig() (*Config, error) {
+	var config Config
+	config.Skip = splitTags(os.Getenv(EnvSkip))
+	config.Run = splitTags(os.Getenv(EnvRun))
+	config.Quarantine = splitTags(os.Getenv(EnvQuarantine))
+	if v := os.Getenv(EnvFuzzy); v != "" {
+		fuzzy, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value '%s' for %s: %v", v, EnvFuzzy, err)
+		}
+		config.Fuzzy = fuzzy
+	}
+	if v := os.Getenv(EnvDryRun); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value '%s' for %s: %v", v, EnvDryRun, err)
+		}
+		config.DryRun = dryRun
+	}
+	if v := os.Getenv(EnvDistance); v != "" {
+		distance, err := strconv.Atoi(v)
+		if err == nil && distance < 0 {
+			err = fmt.Errorf("distance must not be negative")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value '%s' for %s: %v", v, EnvDistance, err)
+		}
+		config.EditDistance = distance
+	}
+	config.Selector = os.Getenv(EnvSelector)
+	config.Shard = os.Getenv(EnvShard)
+	config.Mode = os.Getenv(EnvMode)
+	return &config, nil
+}
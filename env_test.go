@@ -0,0 +1,45 @@
+package gotag
+
+import "testing"
+
+func TestLoadEnv(t *testing.T) {
+	t.Setenv(EnvSkip, "tagA,tagB")
+	t.Setenv(EnvFuzzy, "true")
+	t.Setenv(EnvDistance, "1")
+
+	tc := New()
+	if err := tc.LoadEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if !tc.Fuzzy {
+		t.Error("Expected fuzzy matching to be enabled")
+	}
+	if tc.EditDistance != 1 {
+		t.Errorf("Expected edit distance of 1, got %d", tc.EditDistance)
+	}
+
+	mock := &mockT{}
+	tc.Test("tagA", mock, func(t T) {})
+	tc.Test("tagb", mock, func(t T) {})
+	tc.Test("other", mock, func(t T) {})
+	if mock.skipped != 2 {
+		t.Error("Wrong number of tests skipped")
+	}
+}
+
+func TestLoadEnvInvalid(t *testing.T) {
+	for _, v := range []string{"far", "-1"} {
+		t.Setenv(EnvDistance, v)
+
+		tc := New()
+		if err := tc.LoadEnv(); err == nil {
+			t.Errorf("Expected an error for distance '%s'", v)
+		}
+	}
+
+	t.Setenv(EnvDistance, "")
+	t.Setenv(EnvFuzzy, "sometimes")
+	if err := New().LoadEnv(); err == nil {
+		t.Error("Expected an error for a malformed fuzzy flag")
+	}
+}
@@ -0,0 +1,127 @@
+package gotag
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// orders the locks of every context, so that tests
+// acquiring several of them can't deadlock
+var lockSeq atomic.Uint64
+
+// exclusiveLock is the named lock shared by the tags
+// given to a single MutuallyExclusive call
+type exclusiveLock struct {
+	seq  uint64
+	tags []string
+	mu   sync.Mutex
+
+	// guards holder, the name of the test holding mu
+	state  sync.Mutex
+	holder string
+}
+
+// MutuallyExclusive makes tests under any of the tags, or a tag in
+// their namespaces, never run concurrently with one another, such as
+// tests writing to and migrating the same database. Tests under the
+// same tag are serialized as well, so a single tag can be given to
+// serialize its tests. The lock is taken before the test function is
+// invoked and held until it returns, including retries, and subtests
+// of a test holding it don't wait for it. Clones and children of the
+// context share its locks
+//
+//	tc.MutuallyExclusive("db-write", "db-migrate")
+func (tc *TestContext) MutuallyExclusive(tags ...string) {
+	if len(tags) == 0 {
+		return
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	l := &exclusiveLock{seq: lockSeq.Add(1), tags: append([]string(nil), tags...)}
+	for _, tag := range tags {
+		key := tc.canonical(tag)
+		tc.exclusions[key] = append(tc.exclusions[key], l)
+	}
+}
+
+// MutuallyExclusive makes tests under any of the tags never
+// run concurrently within the default context
+func MutuallyExclusive(tags ...string) {
+	Default().MutuallyExclusive(tags...)
+}
+
+// returns the locks of the tags and their namespaces in the order they
+// must be acquired. Must be called with at least a read lock held
+func (tc *TestContext) locksFor(tags []string) []*exclusiveLock {
+	if len(tc.exclusions) == 0 {
+		return nil
+	}
+	var locks []*exclusiveLock
+	for _, tag := range tags {
+		key := tc.canonical(tag)
+		for i := 0; i <= len(key); i++ {
+			if i < len(key) && key[i] != '.' {
+				continue
+			}
+			for _, l := range tc.exclusions[key[:i]] {
+				if !containsLock(locks, l) {
+					locks = append(locks, l)
+				}
+			}
+		}
+	}
+	sort.Slice(locks, func(i, j int) bool {
+		return locks[i].seq < locks[j].seq
+	})
+	return locks
+}
+
+func containsLock(locks []*exclusiveLock, l *exclusiveLock) bool {
+	for _, held := range locks {
+		if held == l {
+			return true
+		}
+	}
+	return false
+}
+
+// wraps fn so that it runs holding the locks
+func exclusiveFn(locks []*exclusiveLock, fn interface{}) func(s skippable) {
+	return func(s skippable) {
+		var name string
+		if n, ok := s.(interface{ Name() string }); ok {
+			name = n.Name()
+		}
+		for _, l := range locks {
+			if l.acquire(name) {
+				defer l.release()
+			}
+		}
+		call(fn, s)
+	}
+}
+
+// takes the lock for the named test, unless a test it is a subtest of
+// already holds it, reporting whether the lock must be released
+func (l *exclusiveLock) acquire(name string) bool {
+	l.state.Lock()
+	holder := l.holder
+	l.state.Unlock()
+	if name != "" && holder != "" && strings.HasPrefix(name, holder+"/") {
+		return false
+	}
+	l.mu.Lock()
+	l.state.Lock()
+	l.holder = name
+	l.state.Unlock()
+	return true
+}
+
+func (l *exclusiveLock) release() {
+	l.state.Lock()
+	l.holder = ""
+	l.state.Unlock()
+	l.mu.Unlock()
+}
@@ -0,0 +1,82 @@
+package gotag
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMutuallyExclusive(t *testing.T) {
+	tc := New()
+	tc.MutuallyExclusive("db-write", "db-migrate")
+
+	var running, overlaps atomic.Int32
+	exclusive := func(t T) {
+		if running.Add(1) > 1 {
+			overlaps.Add(1)
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+	}
+	t.Run("group", func(t *testing.T) {
+		for _, tag := range []string{"db-write", "db-migrate", "db-write.users", "db-migrate"} {
+			tag := tag
+			t.Run(tag, func(t *testing.T) {
+				t.Parallel()
+				tc.Test(tag, t, exclusive)
+			})
+		}
+	})
+	if overlaps.Load() != 0 {
+		t.Errorf("Expected mutually exclusive tests not to overlap, %d did", overlaps.Load())
+	}
+	if tc.locksFor([]string{"unit"}) != nil {
+		t.Error("Expected tests under other tags not to take a lock")
+	}
+}
+
+func TestMutuallyExclusiveSubtests(t *testing.T) {
+	tc := New()
+	tc.MutuallyExclusive("db-write", "db-migrate")
+
+	ran := false
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t.Run("parent", func(t *testing.T) {
+			tc.Test("db-write", t, func(t T) {
+				tc.Run("db-migrate", t, "child", func(t T) { ran = true })
+			})
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a subtest not to wait for the lock its parent holds")
+	}
+	if !ran {
+		t.Error("Expected the subtest to run")
+	}
+}
+
+func TestLocksFor(t *testing.T) {
+	tc := New()
+	tc.MutuallyExclusive("b", "c")
+	tc.MutuallyExclusive("a", "c")
+
+	locks := tc.locksFor([]string{"c", "a", "b"})
+	if len(locks) != 2 || locks[0].seq > locks[1].seq {
+		t.Errorf("Expected 2 locks in order, got %v", locks)
+	}
+	if c := tc.Clone(); len(c.locksFor([]string{"a"})) != 1 || c.locksFor([]string{"a"})[0] != locks[1] {
+		t.Error("Expected clones to share locks")
+	}
+	tc.CaseInsensitive(true)
+	if len(tc.locksFor([]string{"C"})) != 2 {
+		t.Error("Expected locks to be renormalized")
+	}
+	tc.Reset()
+	if tc.locksFor([]string{"c"}) != nil {
+		t.Error("Expected Reset to remove the locks")
+	}
+}
@@ -0,0 +1,71 @@
+package gotag
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// returns the time the skip expires at
+func (s SkipEntry) deadline() (time.Time, error) {
+	if d, err := time.ParseInLocation("2006-01-02", s.Until, time.Local); err == nil {
+		return d.AddDate(0, 0, 1), nil
+	}
+	d, err := time.Parse(time.RFC3339, s.Until)
+	if err != nil {
+		return d, fmt.Errorf("Invalid deadline '%s' for tag '%s', expected a date such as 2025-09-01", s.Until, s.Tag)
+	}
+	return d, nil
+}
+
+// SkipUntil skips the tag until the deadline passes, after which tests
+// under it run again, so that temporary skips don't become permanent.
+// The reason is shown in the skip message. Main warns about skips
+// that have expired, or fails the run if Strict is true
+//
+//	tc.SkipUntil("flaky-s3", time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), "AWS outage")
+func (tc *TestContext) SkipUntil(tag string, deadline time.Time, reason string) {
+	tc.skipTemporarily(SkipEntry{Tag: tag, Until: deadline.Format(time.RFC3339), Reason: reason}, deadline)
+}
+
+// SkipUntil skips the tag until the deadline
+// passes within the default context
+func SkipUntil(tag string, deadline time.Time, reason string) {
+	Default().SkipUntil(tag, deadline, reason)
+}
+
+func (tc *TestContext) skipTemporarily(skip SkipEntry, deadline time.Time) {
+	if !time.Now().Before(deadline) {
+		tc.mu.Lock()
+		tc.expired = append(tc.expired, skip)
+		tc.mu.Unlock()
+		return
+	}
+	tc.Skip(skip.Tag)
+	tc.mu.Lock()
+	tc.skipEntries[tc.canonical(skip.Tag)] = skip
+	tc.mu.Unlock()
+}
+
+// ExpiredSkips returns the skips whose deadline had passed
+// when they were made, whose tags are therefore not skipped
+func (tc *TestContext) ExpiredSkips() []SkipEntry {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return append([]SkipEntry(nil), tc.expired...)
+}
+
+// warns about expired skips on w, returning an error
+// instead if the run must fail
+func (tc *TestContext) checkExpired(w io.Writer) error {
+	tc.mu.RLock()
+	expired, strict := tc.expired, tc.Strict
+	tc.mu.RUnlock()
+	for _, skip := range expired {
+		if strict {
+			return fmt.Errorf("expired skip of tag '%s' %s", skip.Tag, skip)
+		}
+		fmt.Fprintf(w, "gotag: WARNING: skip of tag '%s' %s has expired, running it\n", skip.Tag, skip)
+	}
+	return nil
+}
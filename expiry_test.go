@@ -0,0 +1,84 @@
+package gotag
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestSkipUntil(t *testing.T) {
+	tc := New()
+	tc.SkipUntil("flaky", time.Now().Add(time.Hour), "AWS outage")
+	tc.SkipUntil("stale", time.Now().Add(-time.Hour), "fixed since")
+
+	ran := false
+	s := &messageT{}
+	tc.Test("flaky", s, func(T) { ran = true })
+	if ran || len(s.messages) != 1 {
+		t.Fatal("Expected the test under a temporary skip to be skipped")
+	}
+	if !strings.Contains(s.messages[0], ": AWS outage") {
+		t.Errorf("Expected the reason in the skip message, got %s", s.messages[0])
+	}
+	tc.Test("stale", &mockT{}, func(T) { ran = true })
+	if !ran {
+		t.Error("Expected the test under an expired skip to run")
+	}
+
+	var out bytes.Buffer
+	if err := tc.checkExpired(&out); err != nil || !strings.Contains(out.String(), "'stale'") {
+		t.Errorf("Expected a warning about the expired skip, got %q, %v", out.String(), err)
+	}
+	tc.Strict = true
+	if err := tc.checkExpired(&out); err == nil || !strings.Contains(err.Error(), "expired skip") {
+		t.Errorf("Expected strict mode to fail on the expired skip, got %v", err)
+	}
+	if expired := tc.ExpiredSkips(); len(expired) != 1 || expired[0].Reason != "fixed since" {
+		t.Errorf("Unexpected expired skips %v", expired)
+	}
+}
+
+func TestTemporarySkipConfig(t *testing.T) {
+	data := "skip:\n  - integration\n  - {tag: flaky, until: 2999-01-01, reason: \"AWS outage\"}\n  - {tag: stale, until: 2000-01-01}\ndistance: 3\n"
+	var config Config
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Skip) != 1 || len(config.SkipEntries) != 2 || config.EditDistance != 3 {
+		t.Fatalf("Unexpected config %+v", config)
+	}
+	if skip := config.SkipEntries[0]; skip.Tag != "flaky" || skip.Until != "2999-01-01" || skip.Reason != "AWS outage" {
+		t.Errorf("Unexpected temporary skip %+v", skip)
+	}
+
+	data = `{"skip": ["integration", {"tag": "flaky", "until": "2999-01-01"}], "distance": 3}`
+	var fromJSON Config
+	err := json.Unmarshal([]byte(data), &fromJSON)
+	if err != nil || len(fromJSON.Skip) != 1 || len(fromJSON.SkipEntries) != 1 {
+		t.Errorf("Unexpected config %+v, %v", fromJSON, err)
+	}
+	streamed, err := decodeJSONConfig(strings.NewReader(data))
+	if err != nil || len(streamed.Skip) != 1 || len(streamed.SkipEntries) != 1 || streamed.EditDistance != 3 {
+		t.Errorf("Unexpected streamed config %+v, %v", streamed, err)
+	}
+
+	tc := New()
+	if err := tc.Apply(&config); err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "flaky,integration" {
+		t.Errorf("Expected only the active skip to apply, got %s", tags)
+	}
+	if expired := tc.ExpiredSkips(); len(expired) != 1 || expired[0].Tag != "stale" {
+		t.Errorf("Unexpected expired skips %v", expired)
+	}
+
+	config.SkipEntries = []SkipEntry{{Tag: "flaky", Until: "next week"}}
+	if err := New().Apply(&config); err == nil {
+		t.Error("Expected an error for a malformed deadline")
+	}
+}
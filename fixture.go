@@ -0,0 +1,110 @@
+package gotag
+
+import "sync"
+
+// fixture is a resource created by its provider at most once, the first
+// time a test gets it, and torn down by Teardown
+type fixture struct {
+	name    string
+	provide func() (interface{}, func(), error)
+	once    sync.Once
+	value   interface{}
+	release func()
+	err     error
+}
+
+// Fixtures gives a test run by TestWith the resources provided to
+// its context
+type Fixtures struct {
+	tc *TestContext
+	t  T
+}
+
+// Provide registers the provider of the named fixture. The provider
+// runs once, when a test first gets the fixture, and returns the
+// resource, a function releasing it, which may be nil, and an error
+// failing every test getting the fixture. Resources are released by
+// Teardown, or by Main once tests have run, in the reverse order they
+// were created
+//
+//	tc.Provide("postgres", func() (interface{}, func(), error) {
+//		db, err := sql.Open("pgx", os.Getenv("DATABASE_URL"))
+//		if err != nil {
+//			return nil, nil, err
+//		}
+//		return db, func() { db.Close() }, nil
+//	})
+func (tc *TestContext) Provide(name string, provider func() (interface{}, func(), error)) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.fixtures[name] = &fixture{name: name, provide: provider}
+}
+
+// Provide registers the provider of the named
+// fixture within the default context
+func Provide(name string, provider func() (interface{}, func(), error)) {
+	Default().Provide(name, provider)
+}
+
+// TestWith runs a test under the tag like Test, passing it the fixtures
+// of the context. Fixtures are only created by the tests getting them,
+// so skipped tests never create them
+//
+//	tc.TestWith(gotag.Integration, t, func(t gotag.T, fix gotag.Fixtures) {
+//		db := fix.Get("postgres").(*sql.DB)
+//	})
+func (tc *TestContext) TestWith(tag string, t T, testFn func(t T, fix Fixtures)) {
+	tc.run([]string{tag}, t, func(t T) {
+		testFn(t, Fixtures{tc: tc, t: t})
+	})
+}
+
+// TestWith runs a test under the tag within the
+// default context, passing it the fixtures
+func TestWith(tag string, t T, testFn func(t T, fix Fixtures)) {
+	Default().TestWith(tag, t, testFn)
+}
+
+// Get returns the named fixture, creating it if no test has yet. The
+// test fails if no fixture of the name is provided or its provider
+// returned an error
+func (f Fixtures) Get(name string) interface{} {
+	f.tc.mu.RLock()
+	fix, ok := f.tc.fixtures[name]
+	f.tc.mu.RUnlock()
+	if !ok {
+		f.t.Fatalf("gotag: no fixture named '%s' is provided", name)
+		return nil
+	}
+	fix.once.Do(func() {
+		f.tc.mu.Lock()
+		f.tc.created = append(f.tc.created, fix)
+		f.tc.mu.Unlock()
+		fix.value, fix.release, fix.err = fix.provide()
+	})
+	if fix.err != nil {
+		f.t.Fatalf("gotag: fixture '%s' failed: %v", name, fix.err)
+		return nil
+	}
+	return fix.value
+}
+
+// releases the fixtures created so far in the reverse order they were
+// created, resetting them so they are created again when next needed
+func (tc *TestContext) releaseFixtures() {
+	tc.mu.Lock()
+	created := tc.created
+	tc.created = nil
+	for _, fix := range created {
+		if tc.fixtures[fix.name] == fix {
+			tc.fixtures[fix.name] = &fixture{name: fix.name, provide: fix.provide}
+		}
+	}
+	tc.mu.Unlock()
+
+	for i := len(created) - 1; i >= 0; i-- {
+		if created[i].release != nil {
+			created[i].release()
+		}
+	}
+}
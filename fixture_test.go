@@ -0,0 +1,79 @@
+package gotag
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestFixtures(t *testing.T) {
+	tc := New()
+	tc.Skip("slow")
+	var created, released []string
+	provide := func(name string) func() (interface{}, func(), error) {
+		return func() (interface{}, func(), error) {
+			created = append(created, name)
+			return name + "-conn", func() { released = append(released, name) }, nil
+		}
+	}
+	tc.Provide("postgres", provide("postgres"))
+	tc.Provide("redis", provide("redis"))
+	tc.Provide("s3", provide("s3"))
+
+	mock := &failingT{}
+	tc.TestWith("integration", mock, func(t T, fix Fixtures) {
+		if db := fix.Get("postgres"); db != "postgres-conn" {
+			t.Errorf("Unexpected fixture %v", db)
+		}
+		fix.Get("redis")
+		fix.Get("postgres")
+	})
+	tc.TestWith("integration", mock, func(t T, fix Fixtures) { fix.Get("postgres") })
+	tc.TestWith("slow", mock, func(t T, fix Fixtures) { fix.Get("s3") })
+	if fmt.Sprint(created) != "[postgres redis]" {
+		t.Errorf("Expected fixtures to be created once when first needed, got %v", created)
+	}
+
+	tc.Teardown()
+	if fmt.Sprint(released) != "[redis postgres]" {
+		t.Errorf("Expected fixtures to be released in reverse order, got %v", released)
+	}
+	tc.TestWith("integration", mock, func(t T, fix Fixtures) { fix.Get("postgres") })
+	if len(created) != 3 {
+		t.Error("Expected a released fixture to be created again")
+	}
+	if mock.fatal != "" {
+		t.Errorf("Unexpected failure %s", mock.fatal)
+	}
+}
+
+func TestFixtureErrors(t *testing.T) {
+	tc := New()
+	calls := 0
+	tc.Provide("docker", func() (interface{}, func(), error) {
+		calls++
+		return nil, nil, errors.New("daemon not running")
+	})
+
+	mock := &failingT{}
+	tc.TestWith("integration", mock, func(t T, fix Fixtures) { fix.Get("docker") })
+	if mock.fatal != "gotag: fixture 'docker' failed: daemon not running" {
+		t.Errorf("Unexpected failure %q", mock.fatal)
+	}
+	tc.TestWith("integration", mock, func(t T, fix Fixtures) { fix.Get("docker") })
+	if calls != 1 {
+		t.Errorf("Expected the provider to run once, ran %d times", calls)
+	}
+	tc.TestWith("integration", mock, func(t T, fix Fixtures) { fix.Get("kafka") })
+	if mock.fatal != "gotag: no fixture named 'kafka' is provided" {
+		t.Errorf("Unexpected failure %q", mock.fatal)
+	}
+	// releasing a fixture that failed is a no-op
+	tc.Teardown()
+
+	c := tc.Clone()
+	c.TestWith("integration", mock, func(t T, fix Fixtures) { fix.Get("docker") })
+	if calls != 2 {
+		t.Error("Expected the clone to create its own fixtures")
+	}
+}
@@ -0,0 +1,145 @@
+package gotag
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+)
+
+// SkipFlag returns a flag.Value that marks a comma separated
+// list of tags to be skipped within the TestContext instance.
+// This allows flag.Var(tc.SkipFlag(), "skip-tags", "...") to be used
+// directly by test binaries
+func (tc *TestContext) SkipFlag() flag.Value {
+	return &tagList{tags: tc.SkippedTags, add: tc.Skip}
+}
+
+// RunFlag returns a flag.Value that marks a comma separated
+// list of tags to be run within the TestContext instance
+func (tc *TestContext) RunFlag() flag.Value {
+	return &tagList{tags: tc.RunTags, add: tc.RunOnly}
+}
+
+// tagList implements flag.Value for a comma separated
+// list of tags
+type tagList struct {
+	tags func() []string
+	add  func(...string)
+}
+
+func (l *tagList) String() string {
+	// the flag package calls String on a zero value
+	// when printing defaults
+	if l == nil || l.tags == nil {
+		return ""
+	}
+	return strings.Join(l.tags(), ",")
+}
+
+func (l *tagList) Set(value string) error {
+	l.add(splitTags(value)...)
+	return nil
+}
+
+// lockedFlag implements flag.Value for an option of the TestContext
+// instance, reading and writing it with the lock held so that parsing
+// flags doesn't race with running tests
+type lockedFlag struct {
+	tc     *TestContext
+	get    func() string
+	set    func(string) error
+	isBool bool
+}
+
+func (f *lockedFlag) String() string {
+	if f == nil || f.tc == nil {
+		return ""
+	}
+	f.tc.mu.RLock()
+	defer f.tc.mu.RUnlock()
+	return f.get()
+}
+
+func (f *lockedFlag) Set(value string) error {
+	f.tc.mu.Lock()
+	defer f.tc.mu.Unlock()
+	return f.set(value)
+}
+
+func (f *lockedFlag) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// returns a flag.Value setting the given bool option of tc
+func (tc *TestContext) boolFlag(p *bool) flag.Value {
+	return &lockedFlag{
+		tc:  tc,
+		get: func() string { return strconv.FormatBool(*p) },
+		set: func(value string) error {
+			v, err := strconv.ParseBool(value)
+			if err == nil {
+				*p = v
+			}
+			return err
+		},
+		isBool: true,
+	}
+}
+
+// returns a flag.Value setting the given int option of tc
+func (tc *TestContext) intFlag(p *int) flag.Value {
+	return &lockedFlag{
+		tc:  tc,
+		get: func() string { return strconv.Itoa(*p) },
+		set: func(value string) error {
+			v, err := strconv.Atoi(value)
+			if err == nil {
+				*p = v
+			}
+			return err
+		},
+	}
+}
+
+// splits a comma separated list of tags, trimming
+// whitespace and dropping empty entries
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// RegisterFlags defines -gotag.skip, -gotag.run, -gotag.fuzzy,
+// -gotag.distance, -gotag.verbose, -gotag.mode and -gotag.dry-run on
+// the given flag set, configuring the TestContext instance when the
+// flag set is parsed. Does nothing if the flag set already defines
+// -gotag.skip
+func (tc *TestContext) RegisterFlags(fs *flag.FlagSet) {
+	if fs.Lookup("gotag.skip") != nil {
+		return
+	}
+	mode := &lockedFlag{
+		tc:  tc,
+		get: func() string { return tc.Mode.String() },
+		set: func(value string) error { return tc.Mode.Set(value) },
+	}
+	fs.Var(tc.SkipFlag(), "gotag.skip", "comma separated list of tags to skip")
+	fs.Var(tc.RunFlag(), "gotag.run", "comma separated list of tags to run, causes skipped tags to be ignored")
+	fs.Var(tc.boolFlag(&tc.Fuzzy), "gotag.fuzzy", "enable fuzzy matching of tags")
+	fs.Var(tc.intFlag(&tc.EditDistance), "gotag.distance", "maximum edit distance for fuzzy matching")
+	fs.Var(tc.boolFlag(&tc.Verbose), "gotag.verbose", "print why tags were fuzzy matched")
+	fs.Var(mode, "gotag.mode", "how skipped and run tags interact: run-only-wins, skip-wins or intersect")
+	fs.Var(tc.boolFlag(&tc.DryRun), "gotag.dry-run", "log what would be skipped without skipping any tests")
+}
+
+// RegisterFlags defines the -gotag.* flags on the given flag set for the
+// default context. Main, Init and the autoload package register them
+// on flag.CommandLine, so selections can be passed to `go test` directly
+func RegisterFlags(fs *flag.FlagSet) {
+	Default().RegisterFlags(fs)
+}
@@ -0,0 +1,96 @@
+package gotag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestSkipFlag(t *testing.T) {
+	tc := New()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(tc.SkipFlag(), "skip-tags", "")
+	if err := fs.Parse([]string{"-skip-tags", "tagA, tagB,,"}); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := &mockT{}
+	tc.Test("tagA", mock, func(t T) {})
+	tc.Test("tagB", mock, func(t T) {})
+	tc.Test("tagC", mock, func(t T) {})
+	if mock.skipped != 2 {
+		t.Error("Wrong number of tests skipped")
+	}
+	if len(tc.SkippedTags()) != 2 {
+		t.Error("Wrong number of skipped tags")
+	}
+}
+
+func TestRunFlag(t *testing.T) {
+	tc := New()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(tc.RunFlag(), "run-tags", "")
+	if err := fs.Parse([]string{"-run-tags", "tagA"}); err != nil {
+		t.Fatal(err)
+	}
+
+	mock := &mockT{}
+	tc.Test("tagA", mock, func(t T) {})
+	tc.Test("tagB", mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Error("Wrong number of tests skipped")
+	}
+	if s := fs.Lookup("run-tags").Value.String(); s != "tagA" {
+		t.Errorf("Expected flag value 'tagA', got '%s'", s)
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	tc := New()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	tc.RegisterFlags(fs)
+	err := fs.Parse([]string{
+		"-gotag.skip", "tagA",
+		"-gotag.fuzzy",
+		"-gotag.distance", "1",
+		"-gotag.verbose",
+		"-gotag.mode", "skip-wins",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tc.Fuzzy {
+		t.Error("Expected fuzzy matching to be enabled")
+	}
+	if tc.EditDistance != 1 {
+		t.Errorf("Expected edit distance of 1, got %d", tc.EditDistance)
+	}
+	if !tc.Verbose {
+		t.Error("Expected verbose output to be enabled")
+	}
+	if tc.Mode != SkipWins {
+		t.Errorf("Expected mode skip-wins, got %s", tc.Mode)
+	}
+	if s := fs.Lookup("gotag.distance").Value.String(); s != "1" {
+		t.Errorf("Expected flag value '1', got '%s'", s)
+	}
+
+	mock := &mockT{}
+	tc.Test("taga", mock, func(t T) {})
+	tc.Test("tagABC", mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Error("Wrong number of tests skipped")
+	}
+}
+
+func TestCommandLineFlags(t *testing.T) {
+	// registered by Main and Init so that importing the
+	// package doesn't create the default context
+	if flag.Lookup("gotag.skip") != nil {
+		t.Fatal("Expected flags not to be registered when the package is imported")
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	tc := New()
+	tc.RegisterFlags(fs)
+	// registering again must not panic
+	tc.RegisterFlags(fs)
+}
@@ -0,0 +1,66 @@
+package gotag
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultFuzzyThreshold is the number of registered tags at which
+// fuzzy matching is split across goroutines if FuzzyWorkers is set.
+// Below this size the cost of starting goroutines outweighs the gain.
+// BenchmarkCheckFuzzy shows the crossover point for a given machine
+const DefaultFuzzyThreshold = 1024
+
+func (tc *TestContext) fuzzyThreshold() int {
+	if tc.FuzzyThreshold > 0 {
+		return tc.FuzzyThreshold
+	}
+	return DefaultFuzzyThreshold
+}
+
+// splits the collection across FuzzyWorkers goroutines, returning the
+// original form of the first registered tag within the edit distance
+// of the given canonical tag. Workers stop early once a match is found
+// earlier in the collection than the tags they have left to check
+func (tc *TestContext) checkFuzzyParallel(tag string, collection *tagSet) (string, bool) {
+	candidates := collection.order
+	workers := tc.FuzzyWorkers
+	size := (len(candidates) + workers - 1) / workers
+	distance := tc.EditDistance
+	measure := tc.distanceFunc
+	if measure == nil {
+		measure = levenshtein
+	}
+
+	var best atomic.Int64
+	best.Store(int64(len(candidates)))
+	var wg sync.WaitGroup
+	for start := 0; start < len(candidates); start += size {
+		end := min(start+size, len(candidates))
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for j := start; j < end; j++ {
+				if int64(j) >= best.Load() {
+					return
+				}
+				if measure(candidates[j], tag) > distance {
+					continue
+				}
+				for {
+					current := best.Load()
+					if int64(j) >= current || best.CompareAndSwap(current, int64(j)) {
+						return
+					}
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	if j := best.Load(); j < int64(len(candidates)) {
+		return collection.original(candidates[j]), true
+	}
+	return "", false
+}
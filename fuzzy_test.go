@@ -0,0 +1,51 @@
+package gotag
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSkipFuzzyParallel(t *testing.T) {
+	tc := New()
+	tc.Fuzzy = true
+	tc.FuzzyWorkers = 4
+	tc.FuzzyThreshold = 10
+	for i := 0; i < 100; i++ {
+		tc.Skip(fmt.Sprintf("generated-tag-%03d", i))
+	}
+
+	mock := &mockT{}
+	tc.Test("generated-tag-05", mock, func(t T) {})
+	tc.Test("unrelated", mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Error("Wrong number of tests skipped")
+	}
+
+	// the first registered match wins regardless of scheduling
+	for i := 0; i < 10; i++ {
+		if match, _ := tc.checkFuzzy("generated-tag-09x", tc.skip); match != "generated-tag-000" {
+			t.Fatalf("Expected first registered match, got %s", match)
+		}
+	}
+}
+
+// compares serial and parallel fuzzy matching of a tag that matches
+// nothing, the worst case, to show where parallel matching pays off
+func BenchmarkCheckFuzzy(b *testing.B) {
+	for _, size := range []int{64, 256, 512, 1024, 4096, 16384} {
+		for _, workers := range []int{1, 4} {
+			tc := New()
+			tc.Fuzzy = true
+			tc.FuzzyWorkers = workers
+			tc.FuzzyThreshold = 1
+			for i := 0; i < size; i++ {
+				tc.Skip(fmt.Sprintf("generated-tag-%05d", i))
+			}
+			b.Run(fmt.Sprintf("size=%d/workers=%d", size, workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					tc.checkFuzzy("no-match-at-all", tc.skip)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package gotag
+
+import "testing"
+
+// TestFor executes a test under the given tag within the default context,
+// passing the concrete *testing.T or *testing.B through to the test body
+// so that helpers requiring the concrete type can be used
+func TestFor[TB testing.TB](tag string, tb TB, testFn func(tb TB)) {
+	TestForContext(Default(), tag, tb, testFn)
+}
+
+// TestForContext executes a test under the given tag within the context of
+// the given TestContext, passing tb through to the test body unchanged
+func TestForContext[TB testing.TB](tc *TestContext, tag string, tb TB, testFn func(tb TB)) {
+	tc.run([]string{tag}, tb, typedFn(func(s skippable) {
+		testFn(tb)
+	}))
+}
+
+// typedFn is the function of a test run with TestFor, whose body is
+// passed its own tb rather than the T gotag passes. Retry, Timeout and
+// Quarantine, which wrap that T, don't apply to it
+type typedFn func(s skippable)
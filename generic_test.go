@@ -0,0 +1,59 @@
+package gotag
+
+import (
+	"testing"
+	"time"
+)
+
+// mockTB satisfies testing.TB through the embedded interface
+// and counts skips
+type mockTB struct {
+	testing.TB
+	skipped int
+}
+
+func (tb *mockTB) Name() string        { return "mock" }
+func (tb *mockTB) Skip(...interface{}) { tb.skipped++ }
+func (tb *mockTB) SkipNow()            { tb.skipped++ }
+
+func TestTestFor(t *testing.T) {
+	tc := New()
+	tc.Skip("tagA")
+
+	mock := &mockTB{}
+	ran := 0
+	TestForContext(tc, "tagA", mock, func(tb *mockTB) { ran++ })
+	TestForContext(tc, "tagB", mock, func(tb *mockTB) {
+		if tb != mock {
+			t.Error("Expected the concrete value to be passed through")
+		}
+		ran++
+	})
+	if mock.skipped != 1 || ran != 1 {
+		t.Errorf("Expected 1 test skipped and 1 run, got %d and %d", mock.skipped, ran)
+	}
+
+	TestForContext(tc, "tagB", t, func(t *testing.T) {
+		t.Log("running with *testing.T")
+	})
+}
+
+func TestTestForWrappers(t *testing.T) {
+	tc := New()
+	tc.Retry("net", 3, 0)
+	tc.Timeout("net", time.Nanosecond)
+	tc.Quarantine("net")
+
+	ran := 0
+	TestForContext(tc, "net", t, func(tt *testing.T) {
+		// the timeout would fail the test if it applied
+		time.Sleep(10 * time.Millisecond)
+		if tt != t {
+			t.Error("Expected the concrete value to be passed through")
+		}
+		ran++
+	})
+	if ran != 1 {
+		t.Errorf("Expected a single attempt, got %d", ran)
+	}
+}
@@ -0,0 +1,49 @@
+package gotag
+
+// DefineGroup defines a group of tags. Skipping, running or requiring
+// the group's name, whether before or after the group is defined, marks
+// each of its members as well. Groups may contain other groups
+//
+//	tc.DefineGroup("ci-full", "unit", "integration", gotag.EndToEnd)
+func (tc *TestContext) DefineGroup(name string, members ...string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	key := tc.canonical(name)
+	tc.groups[key] = append(tc.groups[key], members...)
+	for _, set := range []*tagSet{tc.skip, tc.runOnly, tc.mustRun, tc.shortSkip, tc.quarantine} {
+		if set.has(key) {
+			tc.addTag(set, name)
+		}
+	}
+	tc.invalidate()
+}
+
+// DefineGroup defines a group of tags within the default context
+func DefineGroup(name string, members ...string) {
+	Default().DefineGroup(name, members...)
+}
+
+// adds the tag and, if it names a group, its members to
+// the set. Must be called with the lock held
+func (tc *TestContext) addTag(set *tagSet, tag string) {
+	tc.addExpanded(set, tag, nil)
+}
+
+func (tc *TestContext) addExpanded(set *tagSet, tag string, seen map[string]bool) {
+	key := tc.canonical(tag)
+	set.add(tag, key)
+	members, ok := tc.groups[key]
+	if !ok {
+		return
+	}
+	if seen == nil {
+		seen = make(map[string]bool)
+	}
+	if seen[key] {
+		return
+	}
+	seen[key] = true
+	for _, member := range members {
+		tc.addExpanded(set, member, seen)
+	}
+}
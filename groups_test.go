@@ -0,0 +1,49 @@
+package gotag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	tc := New()
+	tc.DefineGroup("ci-fast", "unit", "lint")
+	tc.DefineGroup("ci-full", "ci-fast", Integration)
+	tc.RunOnly("ci-full")
+	if tags := strings.Join(tc.RunTags(), ","); tags != "ci-fast,ci-full,integration,lint,unit" {
+		t.Errorf("Expected nested groups to be expanded, got %s", tags)
+	}
+
+	// groups defined after their name was marked
+	tc = New()
+	tc.Skip("slow")
+	tc.DefineGroup("slow", EndToEnd)
+	mock := &mockT{}
+	tc.Test(EndToEnd, mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Error("Expected group members to be skipped")
+	}
+
+	// cycles terminate
+	tc = New()
+	tc.DefineGroup("a", "b")
+	tc.DefineGroup("b", "a")
+	tc.Skip("a")
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "a,b" {
+		t.Errorf("Unexpected skipped tags %s", tags)
+	}
+}
+
+func TestConfigGroups(t *testing.T) {
+	config, err := loadYAMLConfig(strings.NewReader("groups:\n  ci-fast: [unit, lint]\nskip: [ci-fast]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := New()
+	if err := tc.Apply(config); err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "ci-fast,lint,unit" {
+		t.Errorf("Unexpected skipped tags %s", tags)
+	}
+}
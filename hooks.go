@@ -0,0 +1,189 @@
+package gotag
+
+import (
+	"fmt"
+	"sync"
+)
+
+// tagHooks holds the setup and teardown functions of a tag.
+// Setup functions run once, before the first test of the tag
+// that is not skipped
+type tagHooks struct {
+	tag string
+	// whether the hooks are those of every tag, see BeforeAll
+	all       bool
+	setups    []func() error
+	teardowns []func()
+	once      sync.Once
+	err       error
+}
+
+// OnSetup registers a function to run once before the first test under
+// the tag, or a tag in its namespace, that is not skipped. Expensive
+// fixtures are therefore only created when the tag actually runs. If
+// the function returns an error, every test under the tag fails with it
+func (tc *TestContext) OnSetup(tag string, fn func() error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	h := tc.hooksOf(tag)
+	h.setups = append(h.setups, fn)
+}
+
+// OnTeardown registers a function to run by Teardown, or by Main once
+// tests have run, if a test under the tag or a tag in its namespace ran
+func (tc *TestContext) OnTeardown(tag string, fn func()) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	h := tc.hooksOf(tag)
+	h.teardowns = append(h.teardowns, fn)
+}
+
+// BeforeAll registers a function to run once before the first tagged
+// test that is not skipped, whatever its tag, so shared infrastructure
+// is only started if a tagged test runs. It runs before the setup
+// functions of the test's tags. If the function returns an error,
+// every tagged test fails with it
+//
+//	func TestMain(m *testing.M) {
+//		gotag.BeforeAll(startCluster)
+//		gotag.AfterAll(stopCluster)
+//		os.Exit(gotag.Main(m))
+//	}
+func (tc *TestContext) BeforeAll(fn func() error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	h := tc.allHooksOf()
+	h.setups = append(h.setups, fn)
+}
+
+// AfterAll registers a function to run by Teardown, or by Main once
+// tests have run, if the functions registered by BeforeAll ran. It
+// runs after the teardown functions of every tag
+func (tc *TestContext) AfterAll(fn func()) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	h := tc.allHooksOf()
+	h.teardowns = append(h.teardowns, fn)
+}
+
+// BeforeAll registers a function to run once before the first
+// tagged test that runs within the default context
+func BeforeAll(fn func() error) {
+	Default().BeforeAll(fn)
+}
+
+// AfterAll registers a function to run once tagged
+// tests have run within the default context
+func AfterAll(fn func()) {
+	Default().AfterAll(fn)
+}
+
+// must be called with the lock held
+func (tc *TestContext) allHooksOf() *tagHooks {
+	if tc.allHooks == nil {
+		tc.allHooks = &tagHooks{all: true}
+	}
+	return tc.allHooks
+}
+
+// Teardown releases the fixtures created so far, see Provide, then runs
+// the teardown functions of every tag that ran, in the reverse order
+// the tags first ran, and resets them so their setup functions run
+// again before the next test under them
+func (tc *TestContext) Teardown() {
+	tc.releaseFixtures()
+	tc.mu.Lock()
+	used := tc.used
+	tc.used = nil
+	for _, h := range used {
+		fresh := &tagHooks{
+			tag:       h.tag,
+			all:       h.all,
+			setups:    h.setups,
+			teardowns: h.teardowns,
+		}
+		if h.all {
+			tc.allHooks = fresh
+		} else {
+			tc.hooks[tc.canonical(h.tag)] = fresh
+		}
+	}
+	tc.mu.Unlock()
+
+	for i := len(used) - 1; i >= 0; i-- {
+		for _, fn := range used[i].teardowns {
+			fn()
+		}
+	}
+}
+
+// OnSetup registers a setup function for the tag within the default context
+func OnSetup(tag string, fn func() error) {
+	Default().OnSetup(tag, fn)
+}
+
+// OnTeardown registers a teardown function for the tag within the default context
+func OnTeardown(tag string, fn func()) {
+	Default().OnTeardown(tag, fn)
+}
+
+// must be called with the lock held
+func (tc *TestContext) hooksOf(tag string) *tagHooks {
+	key := tc.canonical(tag)
+	h, ok := tc.hooks[key]
+	if !ok {
+		h = &tagHooks{tag: tag}
+		tc.hooks[key] = h
+	}
+	return h
+}
+
+// runs the setup functions of the tags and their namespaces that have
+// not run yet, returning the first error any of them returned
+func (tc *TestContext) setup(tags []string) error {
+	tc.mu.RLock()
+	if len(tc.hooks) == 0 && tc.allHooks == nil {
+		tc.mu.RUnlock()
+		return nil
+	}
+	var hooks []*tagHooks
+	if tc.allHooks != nil {
+		hooks = append(hooks, tc.allHooks)
+	}
+	for _, tag := range tags {
+		key := tc.canonical(tag)
+		for i := 0; i <= len(key); i++ {
+			if i < len(key) && key[i] != '.' {
+				continue
+			}
+			if h, ok := tc.hooks[key[:i]]; ok {
+				hooks = append(hooks, h)
+			}
+		}
+	}
+	tc.mu.RUnlock()
+
+	for _, h := range hooks {
+		h.once.Do(func() {
+			tc.mu.Lock()
+			tc.used = append(tc.used, h)
+			tc.mu.Unlock()
+			for _, fn := range h.setups {
+				err := fn()
+				if err == nil {
+					continue
+				}
+				if h.all {
+					h.err = fmt.Errorf("BeforeAll function failed: %v", err)
+				} else {
+					h.err = fmt.Errorf("setup of tag '%s' failed: %v", h.tag, err)
+				}
+				return
+			}
+		})
+		if h.err != nil {
+			return h.err
+		}
+	}
+	return nil
+}
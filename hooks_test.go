@@ -0,0 +1,113 @@
+package gotag
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	tc := New()
+	tc.Skip("skipped")
+	var events []string
+	tc.OnSetup("db", func() error {
+		events = append(events, "setup db")
+		return nil
+	})
+	tc.OnTeardown("db", func() { events = append(events, "teardown db") })
+	tc.OnSetup("skipped", func() error {
+		events = append(events, "setup skipped")
+		return nil
+	})
+	tc.OnTeardown("skipped", func() { events = append(events, "teardown skipped") })
+
+	mock := &mockT{}
+	tc.Test("db.postgres", mock, func(t T) { events = append(events, "test") })
+	tc.Test("db", mock, func(t T) { events = append(events, "test") })
+	tc.Test("skipped", mock, func(t T) {})
+	tc.Teardown()
+	tc.Test("db", mock, func(t T) { events = append(events, "test") })
+
+	want := "setup db,test,test,teardown db,setup db,test"
+	if got := strings.Join(events, ","); got != want {
+		t.Errorf("Expected events %s, got %s", want, got)
+	}
+}
+
+func TestHooksSetupError(t *testing.T) {
+	tc := New()
+	tc.OnSetup("db", func() error { return errors.New("no database") })
+
+	mock := &fatalT{}
+	tc.Test("db", mock, func(t T) { t.Error("Expected the test to not run") })
+	tc.Test("db", mock, func(t T) { t.Error("Expected the test to not run") })
+	if mock.fatals != 2 {
+		t.Errorf("Expected both tests to fail, got %d failures", mock.fatals)
+	}
+}
+
+// fatalT counts calls to Fatalf
+type fatalT struct {
+	mockT
+	fatals int
+}
+
+func (t *fatalT) Fatalf(string, ...interface{}) { t.fatals++ }
+
+func TestBeforeAfterAll(t *testing.T) {
+	tc := New()
+	tc.Skip("slow")
+	var events []string
+	tc.BeforeAll(func() error {
+		events = append(events, "before all")
+		return nil
+	})
+	tc.AfterAll(func() { events = append(events, "after all") })
+	tc.OnSetup("db", func() error {
+		events = append(events, "setup db")
+		return nil
+	})
+	tc.OnTeardown("db", func() { events = append(events, "teardown db") })
+
+	m := runnerFunc(func() int {
+		mock := &mockT{}
+		tc.Test("slow", mock, func(t T) { events = append(events, "slow") })
+		tc.Test("db", mock, func(t T) { events = append(events, "test") })
+		tc.Test("unit", mock, func(t T) { events = append(events, "test") })
+		return 0
+	})
+	if code := tc.main(m); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	want := "before all,setup db,test,test,teardown db,after all"
+	if got := strings.Join(events, ","); got != want {
+		t.Errorf("Expected events %s, got %s", want, got)
+	}
+
+	events = nil
+	tc.Teardown()
+	tc.Test("slow", &mockT{}, func(t T) {})
+	tc.Teardown()
+	if len(events) != 0 {
+		t.Errorf("Expected no hooks to run without a tagged test running, got %v", events)
+	}
+}
+
+func TestBeforeAllError(t *testing.T) {
+	tc := New()
+	tc.BeforeAll(func() error { return errors.New("no cluster") })
+	tc.OnSetup("db", func() error {
+		t.Error("Expected tag setup not to run")
+		return nil
+	})
+
+	mock := &fatalT{}
+	tc.Test("db", mock, func(t T) { t.Error("Expected the test to not run") })
+	tc.Test("unit", mock, func(t T) { t.Error("Expected the test to not run") })
+	if mock.fatals != 2 {
+		t.Errorf("Expected both tests to fail, got %d failures", mock.fatals)
+	}
+	if c := tc.Clone(); c.allHooks == nil || len(c.allHooks.setups) != 1 {
+		t.Error("Expected the clone to keep the hooks")
+	}
+}
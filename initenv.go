@@ -0,0 +1,7 @@
+//go:build !gotag_noenv
+
+package gotag
+
+// the default context reads GOTAG_* environment variables on first
+// use unless built with the gotag_noenv build tag
+const initEnv = true
@@ -0,0 +1,7 @@
+//go:build gotag_noenv
+
+package gotag
+
+// the default context ignores GOTAG_* environment variables
+// when built with the gotag_noenv build tag
+const initEnv = false
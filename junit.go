@@ -0,0 +1,107 @@
+package gotag
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
+// EnvJUnit is the environment variable holding the path
+// that Main writes its JUnit XML report to
+const EnvJUnit = "GOTAG_JUNIT"
+
+// WithJUnitFile sets the path that Main writes a JUnit XML report
+// of every gotag decision to once tests have run, so CI dashboards
+// show which tests gotag skipped and why
+func WithJUnitFile(path string) Option {
+	return func(tc *TestContext) error {
+		tc.junit = path
+		return nil
+	}
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+// junitSuite holds the decisions of a single tag
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Failures int         `xml:"failures,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+// writes every recorded decision to the given path as JUnit
+// XML with a test suite per tag, sorted by tag
+func (tc *TestContext) writeJUnit(path string) error {
+	tc.mu.Lock()
+	bytes, err := junitXML(tc.decisions)
+	tc.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bytes, 0644)
+}
+
+// returns the decisions as JUnit XML with a test suite per tag
+func junitXML(decisions []decision) ([]byte, error) {
+	suites := make(map[string]*junitSuite)
+	seconds := make(map[string]float64)
+	for _, d := range decisions {
+		suite, ok := suites[d.Tag]
+		if !ok {
+			suite = &junitSuite{Name: d.Tag}
+			suites[d.Tag] = suite
+		}
+		c := junitCase{
+			Name:      d.Test,
+			ClassName: d.Tag,
+			Time:      fmt.Sprintf("%.3f", d.Duration.Seconds()),
+		}
+		suite.Tests++
+		if d.Skipped {
+			suite.Skipped++
+			c.Skipped = &junitMessage{Message: "skipped by gotag: " + d.Reason}
+		} else if d.Failed {
+			suite.Failures++
+			c.Failure = &junitMessage{Message: "failed"}
+		}
+		seconds[d.Tag] += d.Duration.Seconds()
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	tags := make([]string, 0, len(suites))
+	for tag := range suites {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	var out junitSuites
+	for _, tag := range tags {
+		suite := suites[tag]
+		suite.Time = fmt.Sprintf("%.3f", seconds[tag])
+		out.Suites = append(out.Suites, *suite)
+	}
+
+	bytes, err := xml.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), bytes...), nil
+}
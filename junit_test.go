@@ -0,0 +1,47 @@
+package gotag
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestMainJUnit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	tc := New()
+	tc.Skip("tagA")
+
+	m := runnerFunc(func() int {
+		mock := &mockT{}
+		tc.Test("tagA", mock, func(t T) {})
+		tc.Test("tagB", mock, func(t T) {})
+		tc.Test("tagB", mock, func(t T) {})
+		return 0
+	})
+	if code := tc.main(m, WithJUnitFile(path)); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report junitSuites
+	if err := xml.Unmarshal(bytes, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Suites) != 2 {
+		t.Fatalf("Expected a suite per tag, got %+v", report.Suites)
+	}
+	a, b := report.Suites[0], report.Suites[1]
+	if a.Name != "tagA" || a.Tests != 1 || a.Skipped != 1 || a.Cases[0].Skipped == nil {
+		t.Errorf("Unexpected suite %+v", a)
+	}
+	if a.Cases[0].Skipped.Message != "skipped by gotag: in skip list" {
+		t.Errorf("Unexpected skip message '%s'", a.Cases[0].Skipped.Message)
+	}
+	if b.Name != "tagB" || b.Tests != 2 || b.Skipped != 0 {
+		t.Errorf("Unexpected suite %+v", b)
+	}
+}
@@ -0,0 +1,134 @@
+package gotag
+
+import (
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// kubeConfig holds the parts of a kubeconfig file the predicates use
+type kubeConfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Contexts       []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Clusters []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server string `yaml:"server"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+}
+
+// RequireKubeContext returns a predicate that holds if the kubeconfig,
+// read from the files listed by KUBECONFIG or from ~/.kube/config,
+// defines the named context, or a current context if name is empty
+//
+//	tc.Require("k8s", gotag.RequireKubeContext("kind-e2e"))
+func RequireKubeContext(name string) func() bool {
+	return func() bool {
+		config, ok := loadKubeConfig()
+		if !ok {
+			return false
+		}
+		context := name
+		if context == "" {
+			context = config.CurrentContext
+		}
+		_, ok = config.server(context)
+		return ok
+	}
+}
+
+// RequireClusterReachable returns a predicate that holds if a TCP
+// connection to the API server of the current kubeconfig context can
+// be established. Credentials are not checked
+func RequireClusterReachable() func() bool {
+	return func() bool {
+		config, ok := loadKubeConfig()
+		if !ok {
+			return false
+		}
+		server, ok := config.server(config.CurrentContext)
+		if !ok {
+			return false
+		}
+		u, err := url.Parse(server)
+		if err != nil || u.Host == "" {
+			return false
+		}
+		addr := u.Host
+		if u.Port() == "" {
+			port := "443"
+			if u.Scheme == "http" {
+				port = "80"
+			}
+			addr = net.JoinHostPort(u.Hostname(), port)
+		}
+		conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}
+}
+
+// reads the kubeconfig files, merging them the way kubectl does: the
+// first file to set the current context or define a name wins
+func loadKubeConfig() (*kubeConfig, bool) {
+	paths := filepath.SplitList(os.Getenv("KUBECONFIG"))
+	if len(paths) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, false
+		}
+		paths = []string{filepath.Join(home, ".kube", "config")}
+	}
+	merged := &kubeConfig{}
+	found := false
+	for _, path := range paths {
+		bytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var config kubeConfig
+		if err := yaml.Unmarshal(bytes, &config); err != nil {
+			return nil, false
+		}
+		found = true
+		if merged.CurrentContext == "" {
+			merged.CurrentContext = config.CurrentContext
+		}
+		merged.Contexts = append(merged.Contexts, config.Contexts...)
+		merged.Clusters = append(merged.Clusters, config.Clusters...)
+	}
+	return merged, found
+}
+
+// returns the API server of the named context
+func (c *kubeConfig) server(context string) (string, bool) {
+	if context == "" {
+		return "", false
+	}
+	for _, ctx := range c.Contexts {
+		if ctx.Name != context {
+			continue
+		}
+		for _, cluster := range c.Clusters {
+			if cluster.Name == ctx.Context.Cluster {
+				return cluster.Cluster.Server, cluster.Cluster.Server != ""
+			}
+		}
+		return "", false
+	}
+	return "", false
+}
@@ -0,0 +1,71 @@
+package gotag
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeKubeConfig(t *testing.T, current, server string) string {
+	config := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: %s
+contexts:
+- name: kind-e2e
+  context:
+    cluster: kind
+    user: admin
+clusters:
+- name: kind
+  cluster:
+    server: %s
+`, current, server)
+	path := filepath.Join(t.TempDir(), "config")
+	if err := ioutil.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRequireKubeContext(t *testing.T) {
+	path := writeKubeConfig(t, "kind-e2e", "https://127.0.0.1:6443")
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing")+string(os.PathListSeparator)+path)
+	if !RequireKubeContext("kind-e2e")() || !RequireKubeContext("")() {
+		t.Error("Expected the context to be found")
+	}
+	if RequireKubeContext("prod")() {
+		t.Error("Expected an undefined context not to hold")
+	}
+
+	t.Setenv("KUBECONFIG", writeKubeConfig(t, "", "https://127.0.0.1:6443"))
+	if RequireKubeContext("")() {
+		t.Error("Expected a missing current context not to hold")
+	}
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+	if RequireKubeContext("kind-e2e")() {
+		t.Error("Expected a missing kubeconfig not to hold")
+	}
+}
+
+func TestRequireClusterReachable(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := "https://" + l.Addr().String()
+	t.Setenv("KUBECONFIG", writeKubeConfig(t, "kind-e2e", server))
+	if !RequireClusterReachable()() {
+		t.Errorf("Expected %s to be reachable", server)
+	}
+	l.Close()
+	if RequireClusterReachable()() {
+		t.Errorf("Expected %s to be unreachable once closed", server)
+	}
+	t.Setenv("KUBECONFIG", writeKubeConfig(t, "other", server))
+	if RequireClusterReachable()() {
+		t.Error("Expected an undefined current context not to hold")
+	}
+}
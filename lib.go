@@ -1,12 +1,20 @@
 package gotag
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	yaml "gopkg.in/yaml.v2"
 )
@@ -19,9 +27,9 @@ const (
 	EndToEnd = "end-to-end"
 )
 
-// T is an interface that matches testing.T. This allows
-// gotag to actually be testable
-type T interface {
+// TB is an interface that matches the methods shared by testing.T,
+// testing.B and testing.F, see Tagged
+type TB interface {
 	Error(...interface{})
 	Errorf(string, ...interface{})
 	Fail()
@@ -31,62 +39,192 @@ type T interface {
 	Fatalf(string, ...interface{})
 	Log(...interface{})
 	Logf(string, ...interface{})
-	Parallel()
-	Run(string, func(*testing.T)) bool
 	Skip(...interface{})
 	SkipNow()
 	Skipf(string, ...interface{})
 	Skipped() bool
 }
 
+// T is an interface that matches testing.T. This allows
+// gotag to actually be testable
+type T interface {
+	TB
+	Parallel()
+	Run(string, func(*testing.T)) bool
+}
+
 // B is an interface that matches testing.B. This allows
 // gotag to actually be testable
 type B interface {
-	Error(...interface{})
-	Errorf(string, ...interface{})
-	Fail()
-	FailNow()
-	Failed() bool
-	Fatal(...interface{})
-	Fatalf(string, ...interface{})
-	Log(...interface{})
-	Logf(string, ...interface{})
+	TB
 	ReportAllocs()
 	ResetTimer()
-	Run(string, func(*testing.T)) bool
+	Run(string, func(*testing.B)) bool
 	RunParallel(func(*testing.PB))
 	SetBytes(int64)
 	SetParallelism(int)
-	Skip(...interface{})
-	SkipNow()
-	Skipf(string, ...interface{})
-	Skipped() bool
 	StartTimer()
 	StopTimer()
 }
 
-// ErrNoConfig is thrown by Load and LoadFrom when a .gotag.json or .gotag.yml
-// file could not be located
+// F is an interface that matches testing.F. This allows
+// gotag to actually be testable
+type F interface {
+	TB
+	Add(...interface{})
+	Fuzz(interface{})
+}
+
+// ErrNoConfig is thrown by Load and LoadFrom when a .gotag.json, .gotag.yml
+// or .gotagskip file could not be located
 var ErrNoConfig = errors.New("Could not locate configuration file")
 
+// ErrLateMutation is panicked by Skip and RunOnly on a strict TestContext
+// when called after a test or benchmark has already been executed
+var ErrLateMutation = errors.New("Tags modified after tests have started")
+
 // Config holds configuration information for a TestContext
 type Config struct {
 	Skip         []string `json:"skip" yaml:"skip"`
 	Run          []string `json:"run" yaml:"run"`
 	Fuzzy        bool     `json:"fuzzy" yaml:"fuzzy"`
 	EditDistance int      `json:"distance" yaml:"distance"`
+	MustRun      []string `json:"must_run" yaml:"must_run"`
+	Selector     string   `json:"selector" yaml:"selector"`
+	Default      string   `json:"default" yaml:"default"`
+	Mode         string   `json:"mode" yaml:"mode"`
+	Quarantine   []string `json:"quarantine" yaml:"quarantine"`
+	Shard        string   `json:"shard" yaml:"shard"`
+	DistanceFunc string   `json:"distance_func" yaml:"distance_func"`
+	SuggestTags  bool     `json:"suggest_tags" yaml:"suggest_tags"`
+	StrictTags   bool     `json:"strict_tags" yaml:"strict_tags"`
+
+	// Extends is the URL of a config this config builds on and
+	// ExtendsSHA256 the optional checksum its content is pinned to
+	Extends       string `json:"extends" yaml:"extends"`
+	ExtendsSHA256 string `json:"extends_sha256" yaml:"extends_sha256"`
+
+	Groups    map[string][]string `json:"groups" yaml:"groups"`
+	Depends   map[string][]string `json:"depends" yaml:"depends"`
+	Timeouts  map[string]string   `json:"timeouts" yaml:"timeouts"`
+	BuildTags map[string]string   `json:"build_tags" yaml:"build_tags"`
+
+	DryRun bool `json:"dry_run" yaml:"dry_run"`
+
+	// Profile names the profile applied by default and Profiles holds
+	// named configs merged on top of this one when selected
+	Profile  string             `json:"profile" yaml:"profile"`
+	Profiles map[string]*Config `json:"profiles" yaml:"profiles"`
+
+	SkipIf     []SkipRule `json:"skip_if" yaml:"skip_if"`
+	ShortSkips []string   `json:"short_skips" yaml:"short_skips"`
+
+	// Tags is the registry of tags, see RegisterTag
+	Tags              []Tag `json:"tags" yaml:"tags"`
+	RequireRegistered bool  `json:"require_registered" yaml:"require_registered"`
+
+	// SkipEntries are the entries of the skip list given as objects
+	SkipEntries []SkipEntry `json:"-" yaml:"-"`
+	Strict      bool        `json:"strict" yaml:"strict"`
+
+	// Webhook is the URL Main posts a summary to, see WithWebhook
+	Webhook string `json:"webhook" yaml:"webhook"`
 }
 
 // TestContext contains information necessary
-// to run or skip tests
+// to run or skip tests. Its methods are safe to call from
+// multiple goroutines, including tests that call t.Parallel,
+// but its exported fields should be set before tests start
 type TestContext struct {
-	skip    map[string]bool
-	runOnly map[string]bool
+	skip    *tagSet
+	runOnly *tagSet
+	mustRun *tagSet
+
+	// tags skipped in short mode, see SkipInShort
+	shortSkip *tagSet
+
+	quarantine *tagSet
+	// failures of quarantined tests in the order they occurred
+	quarantined []quarantineFailure
+
+	selector *Selector
+
+	// the context whose skipped and run tags a child inherits, see Child
+	parent *TestContext
+
+	defaultSkip bool
+
+	// whether tags are matched regardless of case, see CaseInsensitive
+	foldCase bool
+
+	shard shard
+
+	// measures tags for fuzzy matching, Levenshtein if nil
+	distanceFunc DistanceFunc
+
+	// whether mistyped tags are reported, see SuggestTags and StrictTags
+	suggestTags bool
+	strictTags  bool
+
+	// group members keyed by canonical group name
+	groups map[string][]string
+
+	prerequisites map[string]*prerequisite
+
+	// tags skipped on this host keyed by canonical tag, see SkipIf
+	hostSkips map[string]hostSkip
+
+	// locks of canonical tags, see MutuallyExclusive
+	exclusions map[string][]*exclusiveLock
+
+	// tags required by canonical tags, see Requires
+	dependencies map[string][]string
+
+	cache      decisionCache
+	generation atomic.Uint64
+
+	// tags of the running tests by test name
+	active map[string][]string
+
+	hooks map[string]*tagHooks
+	// hooks of every tag, see BeforeAll
+	allHooks *tagHooks
+
+	// fixtures by name and those created in the order they were, see Provide
+	fixtures map[string]*fixture
+	created  []*fixture
+
+	retries  map[string]retryPolicy
+	timeouts map[string]time.Duration
+
+	// priorities of canonical tags within suites, see Priority
+	priorities map[string]int
+
+	// tags of the top level tests registered by name, see Register
+	registered map[string][]string
+
+	// metadata of canonical tags, see RegisterTag
+	tagRegistry       map[string]Tag
+	requireRegistered bool
+
+	// skips with a reason or deadline keyed by canonical tag and those
+	// whose deadline had passed, see SkipWithReason and SkipUntil
+	skipEntries map[string]SkipEntry
+	expired     []SkipEntry
+
+	messages map[skipKey][]interface{}
+	// hooks of the tags that have run, in the order they first ran
+	used []*tagHooks
 
 	// Verbose will print information messages
 	// if set to true
 	Verbose bool
 
+	// Logger receives the information messages printed when Verbose
+	// is true. If nil, messages are logged by the test they concern
+	// through its Logf method, or written to stdout if it has none
+	Logger Logger
+
 	// EditDistance is the maximum distance between a test flag
 	// and a registered flag that will trigger a skip if Fuzzy
 	// is true
@@ -96,40 +234,105 @@ type TestContext struct {
 	// EditDistance of a registered skipped flag and output
 	// to stdout why the skip occurred
 	Fuzzy bool
+
+	// FuzzyWorkers is the number of goroutines fuzzy matching is
+	// split across when the number of registered tags reaches
+	// FuzzyThreshold. Values below 2 disable parallel matching
+	FuzzyWorkers int
+
+	// FuzzyThreshold is the number of registered tags at which
+	// fuzzy matching is split across FuzzyWorkers goroutines.
+	// Defaults to DefaultFuzzyThreshold if not positive
+	FuzzyThreshold int
+
+	// Mode decides whether skipped tags still apply while tags
+	// are marked by RunOnly. Defaults to RunOnlyWins
+	Mode Mode
+
+	// If InsertionOrder is true, SkippedTags and RunTags return
+	// tags in the order they were registered instead of sorted
+	InsertionOrder bool
+
+	// SkipMessage formats the message skipped tests are skipped with,
+	// shown by go test -v, from the tag that caused the skip and a
+	// description of why. Messages are cached so SkipMessage must
+	// return the same message for the same arguments. Defaults to
+	// DefaultSkipMessage if nil
+	SkipMessage func(tag, why string) string
+
+	// If DryRun is true, no test is skipped or run differently, every
+	// wrapper such as Quarantine, Retry and Timeout is disabled, and the
+	// decision gotag would have made is logged for each test instead
+	DryRun bool
+
+	// If Strict is true, calling Skip or RunOnly after a test
+	// has been executed panics with ErrLateMutation instead of
+	// printing a warning, and Main fails the run if a skip made
+	// by SkipUntil has expired
+	Strict bool
+
+	started   atomic.Bool
+	mu        sync.RWMutex
+	recording bool
+	decisions []decision
+	report    string
+	junit     string
+	metrics   string
+	webhook   string
+
+	decisionLog     *decisionLog
+	decisionLogPath string
+	// see AddReporter. Replaced rather than appended to in place
+	reporters []Reporter
 }
 
-// New constructs a new instance of TestContext
-func New() *TestContext {
-	return &TestContext{
-		skip:         make(map[string]bool),
-		runOnly:      make(map[string]bool),
-		EditDistance: 2,
+// New constructs a new instance of TestContext configured by the given
+// options, applied in order. New panics if an option returns an error,
+// such as a config file that could not be loaded
+//
+//	tc := gotag.New(gotag.WithSkip(gotag.Integration), gotag.WithFuzzy(2))
+func New(opts ...Option) *TestContext {
+	tc := &TestContext{
+		skip:          newTagSet(),
+		runOnly:       newTagSet(),
+		mustRun:       newTagSet(),
+		shortSkip:     newTagSet(),
+		quarantine:    newTagSet(),
+		prerequisites: make(map[string]*prerequisite),
+		active:        make(map[string][]string),
+		hooks:         make(map[string]*tagHooks),
+		fixtures:      make(map[string]*fixture),
+		retries:       make(map[string]retryPolicy),
+		timeouts:      make(map[string]time.Duration),
+		priorities:    make(map[string]int),
+		hostSkips:     make(map[string]hostSkip),
+		exclusions:    make(map[string][]*exclusiveLock),
+		dependencies:  make(map[string][]string),
+		registered:    make(map[string][]string),
+		tagRegistry:   make(map[string]Tag),
+		skipEntries:   make(map[string]SkipEntry),
+		messages:      make(map[skipKey][]interface{}),
+		groups:        make(map[string][]string),
+		EditDistance:  2,
+	}
+	for _, opt := range opts {
+		if err := opt(tc); err != nil {
+			panic(fmt.Sprintf("gotag: %v", err))
+		}
 	}
+	return tc
 }
 
-// Load attempts to load a test context from a .gotag config
-// file in the current working directory. Returns an error
-// if a config file could not be located or opened
+// Load attempts to load a test context from the .gotag config
+// files in the current working directory and its parents up to the
+// repository root, with nearer configs overriding farther ones.
+// Returns an error if no config file could be located or opened
 func Load() (*TestContext, error) {
-	f, err := os.Open(".gotag.json")
-	if err == nil {
-		defer f.Close()
-		config, err := loadJSONConfig(f)
-		if err != nil {
-			return nil, err
-		}
-		return fromConfig(config), nil
-	}
-	f, err = os.Open(".gotag.yml")
-	if err == nil {
-		defer f.Close()
-		config, err := loadYAMLConfig(f)
-		if err != nil {
-			return nil, err
-		}
-		return fromConfig(config), nil
+	config, err := discoverConfig(".")
+	if err != nil {
+		return nil, err
 	}
-	return nil, ErrNoConfig
+	return fromConfig(config)
 }
 
 // LoadFrom attempts to load a test context from a .gotag config
@@ -139,132 +342,633 @@ func LoadFrom(dir string) (*TestContext, error) {
 	if dir[len(dir)-1] != '/' {
 		dir = dir + "/"
 	}
-	f, err := os.Open(dir + ".gotag.json")
-	if err == nil {
-		defer f.Close()
-		config, err := loadJSONConfig(f)
-		if err != nil {
-			return nil, err
+	config, err := loadConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	return fromConfig(config)
+}
+
+// Apply merges the tags in the given config into the TestContext
+// instance. Fuzzy matching and dry runs are enabled if set by the
+// config and the edit distance and selector are overridden if the
+// config specifies them, as are the shard and mode. Groups and
+// dependencies are defined before any tags are marked. The profile
+// named by GOTAG_PROFILE, or else by the config, is merged on top of
+// the config first. Returns an error, without changing the context, if
+// the profile is unknown or the config's selector, default, mode,
+// shard, timeouts, skip deadlines, skip_if rules or tag patterns are
+// malformed
+func (tc *TestContext) Apply(config *Config) error {
+	config, err := config.withProfile("")
+	if err != nil {
+		return err
+	}
+	if config.Default != "" && config.Default != "skip" && config.Default != "run" {
+		return fmt.Errorf("Invalid default '%s', expected skip or run", config.Default)
+	}
+	if err := checkPatterns(config.Skip...); err != nil {
+		return err
+	}
+	if err := checkPatterns(config.Run...); err != nil {
+		return err
+	}
+	if err := checkPatterns(config.MustRun...); err != nil {
+		return err
+	}
+	if err := checkPatterns(config.Quarantine...); err != nil {
+		return err
+	}
+	if err := checkPatterns(config.ShortSkips...); err != nil {
+		return err
+	}
+	if err := checkTags(config.Tags); err != nil {
+		return err
+	}
+	if config.Webhook != "" && !strings.HasPrefix(config.Webhook, "https://") && !strings.HasPrefix(config.Webhook, "http://") {
+		return fmt.Errorf("Invalid webhook '%s', expected an http or https URL", config.Webhook)
+	}
+	deadlines := make([]time.Time, len(config.SkipEntries))
+	for i, skip := range config.SkipEntries {
+		if err := checkPatterns(skip.Tag); err != nil {
+			return err
+		}
+		if skip.Until == "" {
+			continue
+		}
+		var err error
+		if deadlines[i], err = skip.deadline(); err != nil {
+			return err
 		}
-		return fromConfig(config), nil
 	}
-	f, err = os.Open(dir + ".gotag.yml")
-	if err == nil {
-		defer f.Close()
-		config, err := loadYAMLConfig(f)
+	var mode Mode
+	if config.Mode != "" {
+		var err error
+		if mode, err = ParseMode(config.Mode); err != nil {
+			return err
+		}
+	}
+	var distance DistanceFunc
+	if config.DistanceFunc != "" {
+		var err error
+		if distance, err = ParseDistanceFunc(config.DistanceFunc); err != nil {
+			return err
+		}
+	}
+	var sh shard
+	if config.Shard != "" {
+		var err error
+		if sh, err = parseShard(config.Shard); err != nil {
+			return err
+		}
+	}
+	timeouts := make(map[string]time.Duration, len(config.Timeouts))
+	for tag, v := range config.Timeouts {
+		d, err := time.ParseDuration(v)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("Invalid timeout '%s' for tag '%s': %v", v, tag, err)
+		}
+		timeouts[tag] = d
+	}
+	hostConds := make([][]Condition, len(config.SkipIf))
+	for i, rule := range config.SkipIf {
+		var err error
+		if hostConds[i], err = rule.conditions(); err != nil {
+			return err
+		}
+	}
+	var sel *Selector
+	if config.Selector != "" {
+		var err error
+		if sel, err = ParseSelector(config.Selector); err != nil {
+			return err
+		}
+	}
+	names := make([]string, 0, len(config.Groups))
+	for name := range config.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tc.DefineGroup(name, config.Groups[name]...)
+	}
+	tags := make([]string, 0, len(config.Depends))
+	for tag := range config.Depends {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	for _, tag := range tags {
+		tc.Requires(tag, config.Depends[tag]...)
+	}
+	tc.Skip(config.Skip...)
+	for i, skip := range config.SkipEntries {
+		if skip.Until == "" {
+			tc.SkipWithReason(skip.Tag, skip.Reason)
+		} else {
+			tc.skipTemporarily(skip, deadlines[i])
 		}
-		return fromConfig(config), nil
 	}
-	return nil, ErrNoConfig
+	tc.RunOnly(config.Run...)
+	tc.RunBuildTags(config.BuildTags)
+	tc.MustRun(config.MustRun...)
+	tc.Quarantine(config.Quarantine...)
+	tc.SkipInShort(config.ShortSkips...)
+	tc.RegisterTag(config.Tags...)
+	for tag, d := range timeouts {
+		tc.Timeout(tag, d)
+	}
+	for i, rule := range config.SkipIf {
+		tc.SkipIf(rule.Tag, hostConds[i]...)
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if config.Fuzzy {
+		tc.Fuzzy = true
+	}
+	if config.DryRun {
+		tc.DryRun = true
+	}
+	if config.Mode != "" {
+		tc.Mode = mode
+	}
+	if config.EditDistance > 0 {
+		tc.EditDistance = config.EditDistance
+	}
+	if distance != nil {
+		tc.distanceFunc = distance
+	}
+	if config.SuggestTags {
+		tc.suggestTags = true
+	}
+	if config.StrictTags {
+		tc.strictTags = true
+	}
+	if config.RequireRegistered {
+		tc.requireRegistered = true
+	}
+	if config.Strict {
+		tc.Strict = true
+	}
+	if sel != nil {
+		tc.selector = sel
+	}
+	if sh.total > 0 {
+		tc.shard = sh
+	}
+	// a webhook given in code or the environment takes precedence
+	if config.Webhook != "" && tc.webhook == "" {
+		tc.webhook = config.Webhook
+	}
+	switch config.Default {
+	case "skip":
+		tc.defaultSkip = true
+	case "run":
+		tc.defaultSkip = false
+	}
+	tc.invalidate()
+	return nil
 }
 
 // Skip marks test tags to be skipped when testing
 // within the context of the TestContext instance
 func (tc *TestContext) Skip(tags ...string) {
+	tc.checkStarted("Skip", tags)
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
 	for _, tag := range tags {
-		tc.skip[tag] = true
+		tc.addTag(tc.skip, tag)
 	}
+	tc.invalidate()
 }
 
 // RunOnly marks specific tests to be run. If this method is called
 // with a non-empty argument, then only the given tests will run.
-// Marking tags as run only will by default make the context ignore skipped tags.
+// Marking tags as run only will by default make the context ignore skipped tags,
+// see Mode.
 func (tc *TestContext) RunOnly(tags ...string) {
+	tc.checkStarted("RunOnly", tags)
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
 	for _, tag := range tags {
-		tc.runOnly[tag] = true
+		tc.addTag(tc.runOnly, tag)
+	}
+	tc.invalidate()
+}
+
+// DefaultSkip sets whether tagged tests are skipped unless one of their
+// tags is marked to run with RunOnly, the way build tags opt files in.
+// Untagged tests are unaffected
+func (tc *TestContext) DefaultSkip(skip bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.defaultSkip = skip
+	tc.invalidate()
+}
+
+// MustRun marks tags that are required to run. Tests under these tags
+// are still skipped if the selection says so, but Main reports every
+// such skip as a violation and notifies the configured webhook
+func (tc *TestContext) MustRun(tags ...string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	for _, tag := range tags {
+		tc.addTag(tc.mustRun, tag)
 	}
 }
 
 // Test executes a test under the given tag with the given testing environment
 // within the context of the TestContext instance
 func (tc *TestContext) Test(tag string, t T, testFn func(t T)) {
-	tc.run(tag, t, func(s skippable) {
-		testFn(s.(T))
-	})
+	tc.run([]string{tag}, t, testFn)
+}
+
+// TestTags executes a test under all of the given tags with the given testing
+// environment within the context of the TestContext instance. The test is
+// skipped if any of its tags is skipped and, when run only tags are marked,
+// runs if any of its tags is marked
+func (tc *TestContext) TestTags(tags []string, t T, testFn func(t T)) {
+	tc.run(tags, t, testFn)
 }
 
 // Benchmark executes a benchmark under the given tag with the given benchmarking
 // environment within the context of the TestFlags instance
 func (tc *TestContext) Benchmark(tag string, b B, benchmarkFn func(b B)) {
-	tc.run(tag, b, func(s skippable) {
-		benchmarkFn(s.(B))
-	})
+	tc.run([]string{tag}, b, benchmarkFn)
+}
+
+// BenchmarkTags executes a benchmark under all of the given tags with the given
+// benchmarking environment within the context of the TestContext instance
+func (tc *TestContext) BenchmarkTags(tags []string, b B, benchmarkFn func(b B)) {
+	tc.run(tags, b, benchmarkFn)
+}
+
+// Tagged executes a test, benchmark or fuzz target under the given tag
+// within the context of the TestContext instance. It is a single entry
+// point for test bodies that only need the methods shared by testing.T,
+// testing.B and testing.F
+func (tc *TestContext) Tagged(tag string, tb TB, fn func(tb TB)) {
+	tc.run([]string{tag}, tb, fn)
 }
 
-// SkippedTags returns a slice of skipped tags for the TestContext
+// Fuzz executes a fuzz target under the given tag with the given fuzzing
+// environment within the context of the TestContext instance, so that
+// long running fuzz targets can be skipped in normal runs
+//
+//	func FuzzParse(f *testing.F) {
+//		gotag.Fuzz("fuzz-long", f, func(f gotag.F) {
+//			f.Add("seed")
+//			f.Fuzz(func(t *testing.T, s string) { Parse(s) })
+//		})
+//	}
+func (tc *TestContext) Fuzz(tag string, f F, fuzzFn func(f F)) {
+	tc.run([]string{tag}, f, fuzzFn)
+}
+
+// WouldSkip reports whether a test under the given tags would be
+// skipped within the context of the TestContext instance, and why
+func (tc *TestContext) WouldSkip(tags ...string) (bool, string) {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	_, _, reason := tc.shouldSkip(tags)
+	return reason.skipped(), reason.String()
+}
+
+// SkippedTags returns a sorted slice of skipped tags for the TestContext,
+// including those inherited from its parent if it is a Child
 func (tc *TestContext) SkippedTags() []string {
-	return keys(tc.skip)
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return tc.inheritedTags(skipSet)
 }
 
-// RunTags returns a slice of run tags for the TestContext
+// RunTags returns a sorted slice of run tags for the TestContext,
+// including those inherited from its parent if it is a Child
 func (tc *TestContext) RunTags() []string {
-	return keys(tc.runOnly)
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return tc.inheritedTags(runOnlySet)
 }
 
-func (tc *TestContext) run(tag string, s skippable, fn func(s skippable)) {
-	match, reason := tc.shouldSkip(tag)
+func (tc *TestContext) run(tags []string, s skippable, fn interface{}) {
+	tc.started.Store(true)
+	tc.mu.RLock()
+	match, tag, reason := tc.shouldSkip(tags)
+	if !reason.skipped() && tc.shard.total > 1 {
+		var name string
+		if n, ok := s.(interface{ Name() string }); ok {
+			name = n.Name()
+		}
+		if !tc.shard.has(tags, name) {
+			tag, reason = "", notInShard
+		}
+	}
+	var reqs []*prerequisite
+	var quarantined string
+	var retry retryPolicy
+	var budget time.Duration
+	var budgetTag string
+	var locks []*exclusiveLock
+	if !reason.skipped() {
+		reqs = tc.prerequisitesFor(tags)
+		quarantined = tc.quarantinedTag(tags)
+		retry = tc.retryPolicyFor(tags)
+		budget, budgetTag = tc.timeoutFor(tags)
+		locks = tc.locksFor(tags)
+	}
+	unknown, suggestions := tc.unknownTag(tags)
+	unregistered, mustRegister := tc.unregisteredTag(tags)
+	strictTags := tc.strictTags
+	verbose, distance, logger, dryRun := tc.Verbose, tc.EditDistance, tc.Logger, tc.DryRun
+	dlog, reporters := tc.decisionLog, tc.reporters
+	tc.mu.RUnlock()
+	// predicates may be slow so they are evaluated without the lock held
+	for _, req := range reqs {
+		if !req.met() {
+			tag, reason = req.name, requirementUnmet
+			break
+		}
+	}
+	if traceTags {
+		if l, ok := s.(interface{ Logf(string, ...interface{}) }); ok {
+			for _, tag := range tags {
+				l.Logf("gotag: tag=%q", tag)
+			}
+		}
+	}
+	if dlog != nil || len(reporters) > 0 {
+		// tags are copied so that they don't escape when no log is set
+		e := Decision{Time: time.Now(), Tags: append([]string(nil), tags...), Tag: tag, Match: match,
+			Skipped: reason.skipped()}
+		tc.mu.RLock()
+		e.Reason = tc.describeReason(tag, reason)
+		tc.mu.RUnlock()
+		if n, ok := s.(interface{ Name() string }); ok {
+			e.Test = n.Name()
+		}
+		if dlog != nil {
+			dlog.write(e)
+		}
+		for _, r := range reporters {
+			r.OnDecision(e)
+		}
+	}
+	if mustRegister && !dryRun {
+		if f, ok := s.(interface{ Fatalf(string, ...interface{}) }); ok {
+			f.Fatalf("gotag: tag '%s' is not registered, see RegisterTag", unregistered)
+			return
+		}
+	}
+	if unknown != "" {
+		msg := unknownTagMessage(unknown, suggestions)
+		if f, ok := s.(interface{ Fatalf(string, ...interface{}) }); ok && strictTags && !dryRun {
+			f.Fatalf("gotag: %s", msg)
+			return
+		}
+		logTo(logger, s, "gotag: WARNING: %s", msg)
+	}
+	if dryRun {
+		tc.logDryRun(logger, tags, s, tag, match, reason, distance)
+		tc.exec(tags, s, fn)
+		return
+	}
+	if _, typed := fn.(typedFn); typed {
+		budget, retry.attempts, quarantined = 0, 0, ""
+	}
+	if budget > 0 {
+		fn = timeoutFn(budget, budgetTag, fn)
+	}
+	if retry.attempts > 1 {
+		fn = retryFn(retry, fn)
+	}
+	if quarantined != "" {
+		fn = tc.quarantineFn(quarantined, fn)
+	}
+	if len(locks) > 0 {
+		fn = exclusiveFn(locks, fn)
+	}
+	if tc.recording {
+		// deferred so that the outcome is recorded even
+		// when the test exits through SkipNow or FailNow
+		defer tc.record(tags, s, reason, time.Now())
+	}
 	switch reason {
-	case foundInSkip, notInRunOnly:
-		s.SkipNow()
+	case foundInSkip, notInRunOnly, notSelected, requirementUnmet, notEnabled, notInShard, unsupportedHost, shortMode,
+		requiredSkipped:
+		s.Skip(tc.skipMessage(tags, tag, match, reason, distance)...)
 	case fuzzyMatchSkip:
-		if tc.Verbose {
-			fmt.Printf(
-				"Found registered skip tag '%s' within an edit distance of %d of tag '%s', skipping...\n",
-				match, tc.EditDistance, tag)
+		if verbose {
+			logTo(logger, s,
+				"Found registered skip tag '%s' within an edit distance of %d of tag '%s', skipping...",
+				match, distance, tag)
 		}
-		s.SkipNow()
+		s.Skip(tc.skipMessage(tags, tag, match, reason, distance)...)
 	case doNotSkipFuzzy:
-		if tc.Verbose {
-			fmt.Printf(
-				"Found registered run tag '%s' within an edit distance of %d of tag '%s', running...\n",
-				match, tc.EditDistance, tag)
+		if verbose {
+			logTo(logger, s,
+				"Found registered run tag '%s' within an edit distance of %d of tag '%s', running...",
+				match, distance, tag)
 		}
-		fn(s)
+		tc.exec(tags, s, fn)
 	default:
-		fn(s)
+		tc.exec(tags, s, fn)
 	}
 }
 
-func (tc *TestContext) shouldSkip(tag string) (string, skipReason) {
-	if len(tc.runOnly) > 0 {
-		run := tc.runOnly[tag]
-		if run {
-			return "", doNotSkip
+// runs the test, tracking its tags by name for subtests to inherit
+// and notifying the reporters of its start and end
+func (tc *TestContext) exec(tags []string, s skippable, fn interface{}) {
+	var name string
+	n, named := s.(interface{ Name() string })
+	if named {
+		name = n.Name()
+	}
+	tc.mu.Lock()
+	if named {
+		tc.active[name] = append([]string(nil), tags...)
+	}
+	reporters := tc.reporters
+	tc.mu.Unlock()
+	if named {
+		defer func() {
+			tc.mu.Lock()
+			delete(tc.active, name)
+			tc.mu.Unlock()
+		}()
+	}
+	if len(reporters) > 0 {
+		tags := append([]string(nil), tags...)
+		for _, r := range reporters {
+			r.OnTestStart(name, tags)
 		}
-		if !tc.Fuzzy {
-			return "", notInRunOnly
+		// deferred so that the end is reported even
+		// when the test exits through SkipNow or FailNow
+		defer func(start time.Time) {
+			result := TestResult{Test: name, Tags: tags, Duration: time.Since(start)}
+			if f, ok := s.(interface{ Failed() bool }); ok {
+				result.Failed = f.Failed()
+			}
+			for _, r := range reporters {
+				r.OnTestEnd(result)
+			}
+		}(time.Now())
+	}
+	if err := tc.setup(tags); err != nil {
+		if f, ok := s.(interface{ Fatalf(string, ...interface{}) }); ok {
+			f.Fatalf("gotag: %v", err)
+			return
 		}
+	}
+	call(fn, s)
+}
+
+// calls a test, benchmark or fuzz function, one of func(T), func(B),
+// func(F), func(TB) or func(skippable). Test functions are passed through unwrapped so
+// that they don't escape to the heap when skipped
+func call(fn interface{}, s skippable) {
+	switch fn := fn.(type) {
+	case func(T):
+		fn(s.(T))
+	case func(B):
+		fn(s.(B))
+	case func(F):
+		fn(s.(F))
+	case func(TB):
+		fn(s.(TB))
+	case func(skippable):
+		fn(s)
+	case typedFn:
+		fn(s)
+	}
+}
+
+// warns or panics if tags are modified after tests have started
+// since the outcome then depends on the order tests are run in
+func (tc *TestContext) checkStarted(method string, tags []string) {
+	if len(tags) == 0 || !tc.started.Load() {
+		return
+	}
+	if tc.Strict {
+		panic(ErrLateMutation)
+	}
+	fmt.Fprintf(os.Stderr,
+		"gotag: WARNING: %s(%s) called after tests have started, selections now depend on test order\n",
+		method, strings.Join(tags, ", "))
+}
+
+// returns the registered tag matched through fuzzy matching, the test
+// tag that decided the outcome and why. Must be called with at least
+// a read lock held. Decisions for a single tag are cached until the
+// context changes, see decisionCache
+func (tc *TestContext) shouldSkip(tags []string) (string, string, skipReason) {
+	if len(tags) != 1 {
+		return tc.decide(tags)
+	}
+	valid := cachedDecision{generation: tc.chainGeneration(), mode: tc.Mode, fuzzy: tc.Fuzzy,
+		distance: tc.EditDistance, short: tc.shortSkip.len() > 0 && testingShort()}
+	if d, ok := tc.cache.get(tags[0], valid); ok {
+		return d.match, d.tag, d.reason
+	}
+	valid.match, valid.tag, valid.reason = tc.decide(tags)
+	tc.cache.put(tags[0], valid)
+	return valid.match, valid.tag, valid.reason
+}
+
+// decides whether to skip a test with the tags, see shouldSkip. Exact
+// matches are resolved with a single lookup per set and no allocations,
+// falling back to fuzzy matching only on a miss
+func (tc *TestContext) decide(tags []string) (string, string, skipReason) {
+	if skip, ok := tc.hostSkipped(tags); ok {
+		return "", skip.tag, unsupportedHost
+	}
+	if tag, ok := tc.shortSkipped(tags); ok {
+		return "", tag, shortMode
+	}
+	runOnly := tc.inheritedLen(runOnlySet) > 0 || tc.defaultSkip
+	if tc.Mode == Intersect || (tc.Mode == SkipWins && tc.inheritedLen(skipSet) > 0) {
+		match, tag, reason := tc.checkSkip(tags)
+		if reason.skipped() || tc.Mode == SkipWins || !runOnly {
+			return match, tag, reason
+		}
+		return tc.checkRunOnly(tags)
+	}
+	if runOnly {
+		return tc.checkRunOnly(tags)
+	}
+	return tc.checkSkip(tags)
+}
 
-		match, runFuzzy := tc.checkFuzzy(tag, tc.runOnly)
-		if !runFuzzy {
-			return "", notInRunOnly
+// checks the tags against the tags marked by RunOnly.
+// Must be called with at least a read lock held
+func (tc *TestContext) checkRunOnly(tags []string) (string, string, skipReason) {
+	for _, tag := range tags {
+		if tc.inheritedCovers(runOnlySet, tc.canonical(tag)) {
+			return "", tag, tc.checkSelector(tags, doNotSkip)
+		}
+	}
+	if tag, ok := tc.requiredByRunOnly(tags); ok {
+		return "", tag, tc.checkSelector(tags, doNotSkip)
+	}
+	if tc.Fuzzy {
+		for _, tag := range tags {
+			if match, ok := tc.inheritedFuzzy(runOnlySet, tc.canonical(tag)); ok {
+				return match, tag, tc.checkSelector(tags, doNotSkipFuzzy)
+			}
 		}
-		return match, doNotSkipFuzzy
 	}
+	if tc.inheritedLen(runOnlySet) == 0 {
+		return "", "", notEnabled
+	}
+	return "", "", notInRunOnly
+}
 
-	skip := tc.skip[tag]
-	if skip {
-		return "", foundInSkip
+// checks the tags against the skipped tags.
+// Must be called with at least a read lock held
+func (tc *TestContext) checkSkip(tags []string) (string, string, skipReason) {
+	for _, tag := range tags {
+		if tc.inheritedCovers(skipSet, tc.canonical(tag)) {
+			return "", tag, foundInSkip
+		}
 	}
-	if !tc.Fuzzy {
-		return "", doNotSkip
+	if tag, required, ok := tc.requiresSkipped(tags); ok {
+		return required, tag, requiredSkipped
 	}
+	if tc.Fuzzy {
+		for _, tag := range tags {
+			if match, ok := tc.inheritedFuzzy(skipSet, tc.canonical(tag)); ok {
+				return match, tag, fuzzyMatchSkip
+			}
+		}
+	}
+	return "", "", tc.checkSelector(tags, doNotSkip)
+}
 
-	match, skipFuzzy := tc.checkFuzzy(tag, tc.skip)
-	if !skipFuzzy {
-		return "", doNotSkip
+// skips tests that would otherwise run if their
+// tags do not match the selector
+func (tc *TestContext) checkSelector(tags []string, reason skipReason) skipReason {
+	if tc.selector != nil && !tc.selector.Matches(tags...) {
+		return notSelected
 	}
-	return match, fuzzyMatchSkip
+	return reason
 }
 
-func (tc *TestContext) checkFuzzy(tag string, collection map[string]bool) (string, bool) {
-	for k := range collection {
-		if levenshtein(k, tag) > tc.EditDistance {
+// returns the original form of the first registered tag in the
+// collection within the edit distance of the given canonical tag
+func (tc *TestContext) checkFuzzy(tag string, collection *tagSet) (string, bool) {
+	if tc.FuzzyWorkers > 1 && collection.len() >= tc.fuzzyThreshold() {
+		return tc.checkFuzzyParallel(tag, collection)
+	}
+	// the index relies on the triangle inequality of the default distance
+	if tc.distanceFunc == nil && collection.len() >= fuzzyIndexThreshold {
+		if i, ok := collection.index.search(collection.order, tag, tc.EditDistance); ok {
+			return collection.original(collection.order[i]), true
+		}
+		return "", false
+	}
+	for _, k := range collection.order {
+		if tc.distance(k, tag) > tc.EditDistance {
 			continue
 		}
-		return k, true
+		return collection.original(k), true
 	}
 	return "", false
 }
@@ -272,46 +976,94 @@ func (tc *TestContext) checkFuzzy(tag string, collection map[string]bool) (strin
 // Skip marks test tags to be skipped when running tests
 // within the default context
 func Skip(tags ...string) {
-	tc.Skip(tags...)
+	Default().Skip(tags...)
 }
 
 // RunOnly marks specific tests to be run within the default context.
 // If this method is called with a non-empty argument, then only the
 // given tests will run. Marking tags as run only will by default make
-// the context ignore skipped tags.
+// the context ignore skipped tags, see Mode.
 func RunOnly(tags ...string) {
-	tc.RunOnly(tags...)
+	Default().RunOnly(tags...)
 }
 
 // Verbose sets the verbosity of the default context
 func Verbose(verbose bool) {
+	tc := Default()
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
 	tc.Verbose = verbose
 }
 
 // Fuzzy sets fuzzy matching for the default context
 func Fuzzy(fuzzy bool) {
+	tc := Default()
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
 	tc.Fuzzy = fuzzy
 }
 
+// DefaultSkip sets whether tagged tests are skipped unless
+// enabled with RunOnly within the default context
+func DefaultSkip(skip bool) {
+	Default().DefaultSkip(skip)
+}
+
 // Distance sets the fuzzy matching distance for the default context
 func Distance(distance int) {
+	tc := Default()
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
 	tc.EditDistance = distance
 }
 
 // Test executes a test under the given tag with the given testing
 // environment within the default context
 func Test(tag string, t T, testFn func(t T)) {
-	tc.Test(tag, t, testFn)
+	Default().Test(tag, t, testFn)
+}
+
+// TestTags executes a test under all of the given tags with the
+// given testing environment within the default context
+func TestTags(tags []string, t T, testFn func(t T)) {
+	Default().TestTags(tags, t, testFn)
 }
 
 // Benchmark executes a benchmark under the given tag with the
 // the given benchmarking environment within the default context
 func Benchmark(tag string, b B, benchmarkFn func(b B)) {
-	tc.Benchmark(tag, b, benchmarkFn)
+	Default().Benchmark(tag, b, benchmarkFn)
+}
+
+// BenchmarkTags executes a benchmark under all of the given tags
+// with the given benchmarking environment within the default context
+func BenchmarkTags(tags []string, b B, benchmarkFn func(b B)) {
+	Default().BenchmarkTags(tags, b, benchmarkFn)
+}
+
+// Tagged executes a test, benchmark or fuzz target under
+// the given tag within the default context
+func Tagged(tag string, tb TB, fn func(tb TB)) {
+	Default().Tagged(tag, tb, fn)
+}
+
+// Fuzz executes a fuzz target under the given tag with the
+// given fuzzing environment within the default context
+func Fuzz(tag string, f F, fuzzFn func(f F)) {
+	Default().Fuzz(tag, f, fuzzFn)
+}
+
+// scratch buffers for levenshtein so that parallel tests
+// matching fuzzily don't allocate on every call
+var rowPool = sync.Pool{
+	New: func() interface{} {
+		return new([]int)
+	},
 }
 
 // iterative implementation of levenshtein distance algorithm
-// between 2 strings.
+// between 2 strings. Distances are counted in runes so that
+// multi-byte characters count as a single edit.
 //
 // Sourced from https://en.wikipedia.org/wiki/Levenshtein_distance
 func levenshtein(s1, s2 string) int {
@@ -319,8 +1071,8 @@ func levenshtein(s1, s2 string) int {
 		return 0
 	}
 
-	n1 := len(s1)
-	n2 := len(s2)
+	n1 := utf8.RuneCountInString(s1)
+	n2 := utf8.RuneCountInString(s2)
 	if n1 == 0 {
 		return n2
 	}
@@ -328,15 +1080,26 @@ func levenshtein(s1, s2 string) int {
 		return n1
 	}
 
-	v0 := make([]int, n2+1)
-	v1 := make([]int, n2+1)
+	// a single pooled buffer holds the runes of both strings
+	// followed by the two rows of the distance matrix
+	buf := rowPool.Get().(*[]int)
+	defer rowPool.Put(buf)
+	size := n1 + n2 + 2*(n2+1)
+	if cap(*buf) < size {
+		*buf = make([]int, size)
+	}
+	all := (*buf)[:size]
+	r1 := appendRunes(all[:0], s1)
+	r2 := appendRunes(all[n1:n1], s2)
+	v0 := all[n1+n2 : n1+n2+n2+1]
+	v1 := all[n1+n2+n2+1:]
 	for i := 0; i < n2+1; i++ {
 		v0[i] = i
 	}
 	for i := 0; i < n1; i++ {
 		v1[0] = i + 1
 		for j := 0; j < n2; j++ {
-			if s1[i] == s2[j] {
+			if r1[i] == r2[j] {
 				v1[j+1] = min(v1[j]+1, v0[j+1]+1, v0[j])
 			} else {
 				v1[j+1] = min(v1[j]+1, v0[j+1]+1, v0[j]+1)
@@ -347,6 +1110,14 @@ func levenshtein(s1, s2 string) int {
 	return v1[n2]
 }
 
+// appends the runes of s to buf
+func appendRunes(buf []int, s string) []int {
+	for _, r := range s {
+		buf = append(buf, int(r))
+	}
+	return buf
+}
+
 // Returns the minimum of all passed in values.
 // Returns 0 if no values are passed in.
 func min(vals ...int) int {
@@ -363,56 +1134,89 @@ func min(vals ...int) int {
 	return min
 }
 
-// Returns the keys of a map as a slice
-func keys(m map[string]bool) []string {
-	s := make([]string, len(m))
-	i := 0
-	for k := range m {
-		s[i] = k
-		i++
+// creates a test context from a config
+func fromConfig(config *Config) (*TestContext, error) {
+	config, err := config.withProfile("")
+	if err != nil {
+		return nil, err
 	}
-	return s
-}
-
-// convert a slice of strings to a map
-func toMap(s []string) map[string]bool {
-	m := make(map[string]bool)
-	for _, v := range s {
-		m[v] = true
+	tc := New()
+	if err := tc.Apply(config); err != nil {
+		return nil, err
 	}
-	return m
+	tc.EditDistance = config.EditDistance
+	return tc, nil
 }
 
-// creates a test context from a config
-func fromConfig(config *Config) *TestContext {
-	return &TestContext{
-		skip:         toMap(config.Skip),
-		runOnly:      toMap(config.Run),
-		Fuzzy:        config.Fuzzy,
-		EditDistance: config.EditDistance,
+// attempts to read a .gotag.json or .gotag.yml config file with
+// the given path prefix, merging in the configs it extends and the
+// tags of the skip file next to it, see SkipFile
+func loadConfig(prefix string) (*Config, error) {
+	config, err := loadCachedConfig(prefix+".gotag.json", loadJSONConfig)
+	if err == ErrNoConfig {
+		config, err = loadCachedConfig(prefix+".gotag.yml", loadYAMLConfig)
+	}
+	if err == nil {
+		config, err = resolveExtends(config)
+	}
+	if err != nil && err != ErrNoConfig {
+		return nil, err
+	}
+	skips, skipErr := loadCachedConfig(prefix+SkipFile, loadSkipFile)
+	if skipErr == ErrNoConfig {
+		return config, err
+	}
+	if skipErr != nil {
+		return nil, skipErr
 	}
+	return MergeConfigs(config, skips), nil
 }
 
-// attempts to read a config from json
-func loadJSONConfig(f *os.File) (*Config, error) {
-	var config Config
-	err := json.NewDecoder(f).Decode(&config)
+// attempts to read a config from json. Returns ConfigErrors if the
+// config is malformed, has unknown fields or invalid values
+func loadJSONConfig(r io.Reader) (*Config, error) {
+	data, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
-	return &config, nil
+	config, rest, err := decodeJSONConfigFields(bytes.NewReader(data))
+	if err != nil {
+		return nil, jsonConfigError(data, err)
+	}
+	raw := make(map[string]interface{}, len(rest))
+	for key, value := range rest {
+		var v interface{}
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, err
+		}
+		raw[key] = v
+	}
+	if err := checkConfig(data, raw, config); err != nil {
+		return nil, err
+	}
+	return config, nil
 }
 
-// attempts to read a config from yaml
-func loadYAMLConfig(f *os.File) (*Config, error) {
-	bytes, err := ioutil.ReadAll(f)
+// attempts to read a config from yaml. Returns ConfigErrors if the
+// config is malformed, has unknown fields or invalid values
+func loadYAMLConfig(r io.Reader) (*Config, error) {
+	data, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
 
 	var config Config
-	err = yaml.Unmarshal(bytes, &config)
-	if err != nil {
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, yamlConfigError(err)
+	}
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, yamlConfigError(err)
+	}
+	in := make(interner)
+	config.Skip = in.unique(config.Skip)
+	config.Run = in.unique(config.Run)
+	if err := checkConfig(data, raw, &config); err != nil {
 		return nil, err
 	}
 	return &config, nil
@@ -425,16 +1229,79 @@ type skippable interface {
 
 type skipReason int
 
+func (r skipReason) String() string {
+	switch r {
+	case doNotSkipFuzzy:
+		return "fuzzy match in run list"
+	case foundInSkip:
+		return "in skip list"
+	case fuzzyMatchSkip:
+		return "fuzzy match in skip list"
+	case notInRunOnly:
+		return "not in run list"
+	case notSelected:
+		return "does not match selector"
+	case requirementUnmet:
+		return "requirement not met"
+	case notEnabled:
+		return "not enabled"
+	case notInShard:
+		return "not in shard"
+	case unsupportedHost:
+		return "unsupported host"
+	case shortMode:
+		return "short mode"
+	case requiredSkipped:
+		return "required tag skipped"
+	default:
+		return ""
+	}
+}
+
+func (r skipReason) skipped() bool {
+	return r == foundInSkip || r == fuzzyMatchSkip || r == notInRunOnly || r == notSelected ||
+		r == requirementUnmet || r == notEnabled || r == notInShard || r == unsupportedHost ||
+		r == shortMode || r == requiredSkipped
+}
+
 const (
 	doNotSkip skipReason = iota
 	doNotSkipFuzzy
 	foundInSkip
 	fuzzyMatchSkip
 	notInRunOnly
+	notSelected
+	requirementUnmet
+	notEnabled
+	notInShard
+	unsupportedHost
+	shortMode
+	requiredSkipped
+)
+
+var (
+	defaultOnce    sync.Once
+	defaultContext atomic.Pointer[TestContext]
 )
 
-var tc *TestContext
+// Default returns the default context used by the package level
+// functions, initializing it on first use
+func Default() *TestContext {
+	defaultOnce.Do(func() {
+		tc := New()
+		if initEnv {
+			if err := tc.LoadEnv(); err != nil {
+				fmt.Fprintf(os.Stderr, "gotag: ignoring environment: %v\n", err)
+			}
+		}
+		defaultContext.Store(tc)
+	})
+	return defaultContext.Load()
+}
 
-func init() {
-	tc = New()
+// SetDefault installs the given context, such as one returned by
+// Load, as the default context used by the package level functions
+func SetDefault(tc *TestContext) {
+	defaultOnce.Do(func() {})
+	defaultContext.Store(tc)
 }
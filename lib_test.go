@@ -78,6 +78,114 @@ func TestRunOverridesSkip(t *testing.T) {
 	}
 }
 
+func TestTestTags(t *testing.T) {
+	tc := New()
+	tc.Skip("slow")
+
+	mock := &mockT{}
+	tc.TestTags([]string{"integration", "postgres", "slow"}, mock, func(t T) {})
+	tc.TestTags([]string{"integration", "postgres"}, mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Error("Wrong number of tests skipped")
+	}
+
+	tc = New()
+	tc.RunOnly("postgres")
+	mock = &mockT{}
+	tc.TestTags([]string{"integration", "postgres"}, mock, func(t T) {})
+	tc.TestTags([]string{"integration", "mysql"}, mock, func(t T) {})
+	tc.TestTags(nil, mock, func(t T) {})
+	if mock.skipped != 2 {
+		t.Error("Wrong number of tests skipped")
+	}
+}
+
+func TestDefaultSkip(t *testing.T) {
+	tc := New()
+	tc.DefaultSkip(true)
+
+	mock := &mockT{}
+	tc.Test(Integration, mock, func(t T) {})
+	tc.Test("unit", mock, func(t T) {})
+	if mock.skipped != 2 {
+		t.Error("Wrong number of tests skipped")
+	}
+
+	tc.RunOnly(Integration)
+	mock = &mockT{}
+	tc.Test(Integration, mock, func(t T) {})
+	tc.Test("unit", mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Error("Wrong number of tests skipped")
+	}
+
+	tc = New()
+	if err := tc.Apply(&Config{Default: "skip"}); err != nil {
+		t.Fatal(err)
+	}
+	if skip, reason := tc.WouldSkip("unit"); !skip || reason != "not enabled" {
+		t.Errorf("Expected tagged tests to be skipped by default, got %v (%s)", skip, reason)
+	}
+	if err := tc.Apply(&Config{Default: "sometimes"}); err == nil {
+		t.Error("Expected an error for an invalid default")
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	previous := Default()
+	defer SetDefault(previous)
+
+	tc := New(WithSkip("set-default"))
+	SetDefault(tc)
+	mock := &mockT{}
+	Test("set-default", mock, func(t T) {})
+	if Default() != tc || mock.skipped != 1 {
+		t.Error("Expected the installed context to be used by package level functions")
+	}
+}
+
+func TestLateMutationStrict(t *testing.T) {
+	tc := New()
+	tc.Strict = true
+	tc.Skip("tagA")
+	tc.Test("tagB", &mockT{}, func(t T) {})
+
+	defer func() {
+		if r := recover(); r != ErrLateMutation {
+			t.Errorf("Expected ErrLateMutation panic, got %v", r)
+		}
+	}()
+	tc.Skip("tagB")
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		s1, s2   string
+		distance int
+	}{
+		{"", "", 0},
+		{"tag", "", 3},
+		{"", "tag", 3},
+		{"tagA", "taga", 1},
+		{"kitten", "sitting", 3},
+		{"integration", "end-to-end", 10},
+		{"café", "cafe", 1},
+		{"日本語", "日本", 1},
+	}
+	for _, c := range cases {
+		if d := levenshtein(c.s1, c.s2); d != c.distance {
+			t.Errorf("Expected distance %d between '%s' and '%s', got %d", c.distance, c.s1, c.s2, d)
+		}
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		levenshtein("integration", "end-to-end")
+	})
+	if allocs != 0 {
+		t.Errorf("Expected pooled buffers to avoid allocations, got %v", allocs)
+	}
+}
+
 type mockT struct {
 	skipped int
 }
@@ -97,3 +205,69 @@ func (t *mockT) Skip(...interface{})               { t.skipped++ }
 func (t *mockT) SkipNow()                          { t.skipped++ }
 func (t *mockT) Skipf(string, ...interface{})      { t.skipped++ }
 func (t *mockT) Skipped() bool                     { return false }
+
+type mockB struct {
+	skipped int
+}
+
+func (b *mockB) Error(...interface{})              {}
+func (b *mockB) Errorf(string, ...interface{})     {}
+func (b *mockB) Fail()                             {}
+func (b *mockB) FailNow()                          {}
+func (b *mockB) Failed() bool                      { return false }
+func (b *mockB) Fatal(...interface{})              {}
+func (b *mockB) Fatalf(string, ...interface{})     {}
+func (b *mockB) Log(...interface{})                {}
+func (b *mockB) Logf(string, ...interface{})       {}
+func (b *mockB) ReportAllocs()                     {}
+func (b *mockB) ResetTimer()                       {}
+func (b *mockB) Run(string, func(*testing.B)) bool { return false }
+func (b *mockB) RunParallel(func(*testing.PB))     {}
+func (b *mockB) SetBytes(int64)                    {}
+func (b *mockB) SetParallelism(int)                {}
+func (b *mockB) Skip(...interface{})               { b.skipped++ }
+func (b *mockB) SkipNow()                          { b.skipped++ }
+func (b *mockB) Skipf(string, ...interface{})      { b.skipped++ }
+func (b *mockB) Skipped() bool                     { return false }
+func (b *mockB) StartTimer()                       {}
+func (b *mockB) StopTimer()                        {}
+
+func FuzzSkipped(f *testing.F) {
+	tc := New()
+	tc.Skip("fuzz-long")
+	tc.Fuzz("fuzz-long", f, func(f F) {
+		f.Fatal("Expected fuzz-long to be skipped")
+	})
+}
+
+func FuzzTagged(f *testing.F) {
+	tc := New()
+	tc.Skip("fuzz-long")
+	ran := false
+	tc.Fuzz("fuzz-short", f, func(f F) {
+		f.Add("seed")
+		f.Fuzz(func(t *testing.T, s string) { ran = true })
+	})
+	if !ran {
+		f.Error("Expected the seed corpus to run")
+	}
+}
+
+func TestTagged(t *testing.T) {
+	tc := New()
+	tc.Skip("tagA")
+
+	mock := &mockT{}
+	ran := 0
+	tc.Tagged("tagA", mock, func(tb TB) { ran++ })
+	tc.Tagged("tagB", mock, func(tb TB) { ran++ })
+	if mock.skipped != 1 || ran != 1 {
+		t.Errorf("Expected 1 test skipped and 1 run, got %d and %d", mock.skipped, ran)
+	}
+
+	tc.Tagged("tagB", t, func(tb TB) {
+		if _, ok := tb.(*testing.T); !ok {
+			t.Errorf("Expected the *testing.T to be passed through, got %T", tb)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package gotag
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Logger receives the informational messages of a TestContext.
+// T and B satisfy Logger, so a test's t can be used directly
+type Logger interface {
+	Logf(format string, args ...interface{})
+}
+
+// LoggerFunc adapts a function such as t.Logf or log.Printf to a Logger
+type LoggerFunc func(format string, args ...interface{})
+
+// Logf calls f with the given arguments
+func (f LoggerFunc) Logf(format string, args ...interface{}) {
+	f(format, args...)
+}
+
+// WriterLogger returns a Logger that writes every message
+// to w on its own line
+func WriterLogger(w io.Writer) Logger {
+	return LoggerFunc(func(format string, args ...interface{}) {
+		msg := fmt.Sprintf(format, args...)
+		if !strings.HasSuffix(msg, "\n") {
+			msg += "\n"
+		}
+		io.WriteString(w, msg)
+	})
+}
+
+// SlogLogger returns a Logger that logs every message
+// to l at the info level
+func SlogLogger(l *slog.Logger) Logger {
+	return LoggerFunc(func(format string, args ...interface{}) {
+		l.Info(fmt.Sprintf(format, args...))
+	})
+}
+
+// logs a message concerning the test s with the given logger. Messages are
+// logged by the test itself if no logger is set, or written to stdout
+// for tests that can't log
+func logTo(l Logger, s skippable, format string, args ...interface{}) {
+	if l == nil {
+		if sl, ok := s.(Logger); ok {
+			l = sl
+		} else {
+			l = WriterLogger(os.Stdout)
+		}
+	}
+	l.Logf(format, args...)
+}
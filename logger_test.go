@@ -0,0 +1,45 @@
+package gotag
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var logged []string
+	tc := New(WithLogger(LoggerFunc(func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})))
+	tc.Skip("tagA")
+	tc.Fuzzy = true
+	tc.Verbose = true
+
+	mock := &mockT{}
+	tc.Test("tagA", mock, func(t T) {})
+	tc.Test("taga", mock, func(t T) {})
+	want := "Found registered skip tag 'tagA' within an edit distance of 2 of tag 'taga', skipping..."
+	if len(logged) != 1 || logged[0] != want {
+		t.Errorf("Expected %q to be logged, got %q", want, logged)
+	}
+}
+
+func TestWriterLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := WriterLogger(&buf)
+	l.Logf("one %d", 1)
+	l.Logf("two\n")
+	if buf.String() != "one 1\ntwo\n" {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	SlogLogger(slog.New(slog.NewTextHandler(&buf, nil))).Logf("tag %s", "x")
+	if !strings.Contains(buf.String(), `msg="tag x"`) {
+		t.Errorf("Unexpected output %q", buf.String())
+	}
+}
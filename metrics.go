@@ -0,0 +1,118 @@
+package gotag
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvMetrics is the environment variable holding the path that
+// Main writes per tag metrics to in the OpenMetrics text format
+const EnvMetrics = "GOTAG_METRICS"
+
+// durationBuckets are the upper bounds in seconds of the
+// buckets of the test duration histogram
+var durationBuckets = []float64{0.01, 0.1, 0.5, 1, 5, 10, 30, 60, 300}
+
+// WithMetricsFile sets the path that Main writes the number of tests
+// run, skipped and failed per tag and a histogram of their durations
+// to once tests have run, in the OpenMetrics text format, e.g. for a
+// Prometheus pushgateway to ingest
+func WithMetricsFile(path string) Option {
+	return func(tc *TestContext) error {
+		tc.metrics = path
+		return nil
+	}
+}
+
+// tagMetrics holds the counters and duration histogram of a tag
+type tagMetrics struct {
+	run, skipped, failed int
+	buckets              []int
+	sum                  time.Duration
+}
+
+// writes the metrics of the recorded decisions to the given path
+func (tc *TestContext) writeMetrics(path string) error {
+	var b bytes.Buffer
+	tc.exposeMetrics(&b)
+	return ioutil.WriteFile(path, b.Bytes(), 0644)
+}
+
+// writes the per tag metrics of the recorded decisions to w in the
+// OpenMetrics text format, sorted by tag
+func (tc *TestContext) exposeMetrics(w io.Writer) {
+	tc.mu.RLock()
+	byTag := make(map[string]*tagMetrics)
+	for _, d := range tc.decisions {
+		m, ok := byTag[d.Tag]
+		if !ok {
+			m = &tagMetrics{buckets: make([]int, len(durationBuckets))}
+			byTag[d.Tag] = m
+		}
+		if d.Skipped {
+			m.skipped++
+			continue
+		}
+		m.run++
+		if d.Failed {
+			m.failed++
+		}
+		m.sum += d.Duration
+		for i, le := range durationBuckets {
+			if d.Duration.Seconds() <= le {
+				m.buckets[i]++
+			}
+		}
+	}
+	tc.mu.RUnlock()
+
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	counters := []struct {
+		name, help string
+		value      func(m *tagMetrics) int
+	}{
+		{"gotag_tests_run", "Tests run per tag.", func(m *tagMetrics) int { return m.run }},
+		{"gotag_tests_skipped", "Tests skipped by gotag per tag.", func(m *tagMetrics) int { return m.skipped }},
+		{"gotag_tests_failed", "Tests failed per tag.", func(m *tagMetrics) int { return m.failed }},
+	}
+	for _, c := range counters {
+		fmt.Fprintf(w, "# TYPE %s counter\n# HELP %s %s\n", c.name, c.name, c.help)
+		for _, tag := range tags {
+			fmt.Fprintf(w, "%s_total{tag=%s} %d\n", c.name, labelValue(tag), c.value(byTag[tag]))
+		}
+	}
+
+	const histogram = "gotag_test_duration_seconds"
+	fmt.Fprintf(w, "# TYPE %s histogram\n# UNIT %s seconds\n# HELP %s Durations of the tests run per tag.\n",
+		histogram, histogram, histogram)
+	for _, tag := range tags {
+		m, label := byTag[tag], labelValue(tag)
+		for i, le := range durationBuckets {
+			fmt.Fprintf(w, "%s_bucket{tag=%s,le=\"%s\"} %d\n", histogram, label,
+				strconv.FormatFloat(le, 'g', -1, 64), m.buckets[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{tag=%s,le=\"+Inf\"} %d\n", histogram, label, m.run)
+		fmt.Fprintf(w, "%s_count{tag=%s} %d\n", histogram, label, m.run)
+		fmt.Fprintf(w, "%s_sum{tag=%s} %s\n", histogram, label,
+			strconv.FormatFloat(m.sum.Seconds(), 'g', -1, 64))
+	}
+	fmt.Fprintln(w, "# EOF")
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// returns the quoted label value, escaped as OpenMetrics requires
+func labelValue(v string) string {
+	return `"` + labelEscaper.Replace(v) + `"`
+}
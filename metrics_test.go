@@ -0,0 +1,70 @@
+package gotag
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMainMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gotag.prom")
+	tc := New()
+	tc.Skip("tagA")
+
+	m := runnerFunc(func() int {
+		mock := &mockT{}
+		tc.Test("tagA", mock, func(t T) {})
+		tc.Test("tagB", mock, func(t T) {})
+		tc.Test("tagB", mock, func(t T) {})
+		return 0
+	})
+	if code := tc.main(m, WithMetricsFile(path)); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(bytes)
+	for _, want := range []string{
+		"# TYPE gotag_tests_run counter\n",
+		`gotag_tests_run_total{tag="tagA"} 0` + "\n",
+		`gotag_tests_run_total{tag="tagB"} 2` + "\n",
+		`gotag_tests_skipped_total{tag="tagA"} 1` + "\n",
+		`gotag_tests_failed_total{tag="tagB"} 0` + "\n",
+		"# TYPE gotag_test_duration_seconds histogram\n",
+		`gotag_test_duration_seconds_bucket{tag="tagB",le="+Inf"} 2` + "\n",
+		`gotag_test_duration_seconds_count{tag="tagB"} 2` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected metrics to contain %q, got\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Error("Expected metrics to end with # EOF")
+	}
+}
+
+func TestMetricsHistogram(t *testing.T) {
+	tc := New()
+	tc.decisions = []decision{
+		{Tag: `odd"tag`, Duration: 50 * time.Millisecond},
+		{Tag: `odd"tag`, Duration: 2 * time.Second, Failed: true},
+	}
+	var b strings.Builder
+	tc.exposeMetrics(&b)
+	out := b.String()
+	for _, want := range []string{
+		`gotag_tests_failed_total{tag="odd\"tag"} 1`,
+		`gotag_test_duration_seconds_bucket{tag="odd\"tag",le="0.01"} 0`,
+		`gotag_test_duration_seconds_bucket{tag="odd\"tag",le="0.1"} 1`,
+		`gotag_test_duration_seconds_bucket{tag="odd\"tag",le="5"} 2`,
+		`gotag_test_duration_seconds_sum{tag="odd\"tag"} 2.05`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected metrics to contain %q, got\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package gotag
+
+import "fmt"
+
+// Mode decides how the tags marked by Skip and RunOnly
+// interact when both are set
+type Mode int
+
+const (
+	// RunOnlyWins ignores skipped tags while any tags are marked by
+	// RunOnly or tagged tests are skipped by default. This is the
+	// default mode
+	RunOnlyWins Mode = iota
+
+	// SkipWins ignores the tags marked by RunOnly, and DefaultSkip,
+	// while any tags are skipped
+	SkipWins
+
+	// Intersect runs only the tests under a tag marked by RunOnly
+	// that are not under a skipped tag
+	Intersect
+)
+
+var modeNames = map[Mode]string{
+	RunOnlyWins: "run-only-wins",
+	SkipWins:    "skip-wins",
+	Intersect:   "intersect",
+}
+
+// String returns the name of the mode as accepted by ParseMode
+func (m Mode) String() string {
+	if name, ok := modeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// Set parses the mode from its name, so that a Mode can be used as a flag
+func (m *Mode) Set(s string) error {
+	mode, err := ParseMode(s)
+	if err != nil {
+		return err
+	}
+	*m = mode
+	return nil
+}
+
+// ParseMode parses a mode from its name: run-only-wins,
+// skip-wins or intersect
+func ParseMode(s string) (Mode, error) {
+	for mode, name := range modeNames {
+		if s == name {
+			return mode, nil
+		}
+	}
+	return RunOnlyWins, fmt.Errorf("Invalid mode '%s', expected run-only-wins, skip-wins or intersect", s)
+}
@@ -0,0 +1,59 @@
+package gotag
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMode(t *testing.T) {
+	cases := []struct {
+		mode Mode
+		// whether tests under integration, flaky, both and neither are skipped
+		expected [4]bool
+	}{
+		{RunOnlyWins, [4]bool{false, true, false, true}},
+		{SkipWins, [4]bool{false, true, true, false}},
+		{Intersect, [4]bool{false, true, true, true}},
+	}
+	tagSets := [][]string{{Integration}, {"flaky"}, {Integration, "flaky"}, {"unit"}}
+	for _, c := range cases {
+		tc := New(WithMode(c.mode))
+		tc.RunOnly(Integration)
+		tc.Skip("flaky")
+		for i, tags := range tagSets {
+			if skipped, reason := tc.WouldSkip(tags...); skipped != c.expected[i] {
+				t.Errorf("%s: expected tags %v to be skipped: %t, got %t (%s)", c.mode, tags, c.expected[i], skipped, reason)
+			}
+		}
+	}
+}
+
+func TestModeWithoutSkips(t *testing.T) {
+	tc := New(WithMode(SkipWins))
+	tc.RunOnly(Integration)
+	if skipped, _ := tc.WouldSkip("unit"); !skipped {
+		t.Error("Expected RunOnly to apply while no tags are skipped")
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, mode := range []Mode{RunOnlyWins, SkipWins, Intersect} {
+		parsed, err := ParseMode(mode.String())
+		if err != nil || parsed != mode {
+			t.Errorf("Expected %s to parse, got %v, %v", mode, parsed, err)
+		}
+	}
+	if _, err := ParseMode("sometimes"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+	if err := New().Apply(&Config{Mode: "sometimes"}); err == nil {
+		t.Error("Expected Apply to reject an unknown mode")
+	}
+	tc := New()
+	if err := tc.Apply(&Config{Mode: "intersect"}); err != nil || tc.Mode != Intersect {
+		t.Errorf("Expected Apply to set the mode, got %s, %v", tc.Mode, err)
+	}
+	if s := fmt.Sprint(Mode(7)); s != "Mode(7)" {
+		t.Errorf("Unexpected name %s for an unknown mode", s)
+	}
+}
@@ -0,0 +1,159 @@
+package gotag
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Option configures a TestContext
+type Option func(tc *TestContext) error
+
+// WithSkip marks tags to be skipped
+func WithSkip(tags ...string) Option {
+	return func(tc *TestContext) error {
+		tc.Skip(tags...)
+		return nil
+	}
+}
+
+// WithSkipReason marks the tag to be skipped for the reason
+func WithSkipReason(tag, reason string) Option {
+	return func(tc *TestContext) error {
+		tc.SkipWithReason(tag, reason)
+		return nil
+	}
+}
+
+// WithRunOnly marks tags to be run, causing skipped tags to be ignored
+func WithRunOnly(tags ...string) Option {
+	return func(tc *TestContext) error {
+		tc.RunOnly(tags...)
+		return nil
+	}
+}
+
+// WithFuzzy enables fuzzy matching within the given edit
+// distance, keeping the current distance if not positive
+func WithFuzzy(distance int) Option {
+	return func(tc *TestContext) error {
+		tc.Fuzzy = true
+		if distance > 0 {
+			tc.EditDistance = distance
+		}
+		return nil
+	}
+}
+
+// WithVerbose enables printing of informational messages
+func WithVerbose() Option {
+	return func(tc *TestContext) error {
+		tc.Verbose = true
+		return nil
+	}
+}
+
+// WithMode sets how skipped and run tags interact, see Mode
+func WithMode(mode Mode) Option {
+	return func(tc *TestContext) error {
+		tc.Mode = mode
+		return nil
+	}
+}
+
+// WithDryRun logs the decision made for every test
+// instead of skipping or wrapping any, see DryRun
+func WithDryRun() Option {
+	return func(tc *TestContext) error {
+		tc.DryRun = true
+		return nil
+	}
+}
+
+// WithCaseInsensitive matches tags regardless of case
+func WithCaseInsensitive() Option {
+	return func(tc *TestContext) error {
+		tc.CaseInsensitive(true)
+		return nil
+	}
+}
+
+// WithSuggestTags warns about mistyped tags, see SuggestTags
+func WithSuggestTags() Option {
+	return func(tc *TestContext) error {
+		tc.SuggestTags(true)
+		return nil
+	}
+}
+
+// WithStrictTags fails tests under mistyped tags, see StrictTags
+func WithStrictTags() Option {
+	return func(tc *TestContext) error {
+		tc.StrictTags(true)
+		return nil
+	}
+}
+
+// WithRegisteredTags records tags along with their metadata, see RegisterTag
+func WithRegisteredTags(tags ...Tag) Option {
+	return func(tc *TestContext) error {
+		tc.RegisterTag(tags...)
+		return nil
+	}
+}
+
+// WithRequireRegistered fails tests under unregistered tags,
+// see RequireRegistered
+func WithRequireRegistered() Option {
+	return func(tc *TestContext) error {
+		tc.RequireRegistered(true)
+		return nil
+	}
+}
+
+// WithLogger sets the Logger that receives informational messages
+func WithLogger(l Logger) Option {
+	return func(tc *TestContext) error {
+		tc.Logger = l
+		return nil
+	}
+}
+
+// WithConfigFile applies the JSON or YAML config file at the given
+// path, chosen by its extension. Returns ErrNoConfig if the file
+// could not be opened
+func WithConfigFile(path string) Option {
+	return func(tc *TestContext) error {
+		load := loadJSONConfig
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yml", ".yaml":
+			load = loadYAMLConfig
+		}
+		config, err := loadCachedConfig(path, load)
+		if err == nil {
+			config, err = resolveExtends(config)
+		}
+		if err != nil {
+			return err
+		}
+		return tc.Apply(config)
+	}
+}
+
+// WithReportFile sets the path that Main writes a JSON
+// report of every gotag decision to once tests have run
+func WithReportFile(path string) Option {
+	return func(tc *TestContext) error {
+		tc.report = path
+		return nil
+	}
+}
+
+// WithWebhook sets a URL that Main posts a Slack compatible JSON
+// summary to if any must run tags were skipped, quarantined tests
+// failed or skips expired
+func WithWebhook(url string) Option {
+	return func(tc *TestContext) error {
+		tc.webhook = url
+		return nil
+	}
+}
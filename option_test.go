@@ -0,0 +1,31 @@
+package gotag
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewOptions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gotag.yaml")
+	if err := ioutil.WriteFile(path, []byte("skip: [slow]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tc := New(WithSkip(Integration), WithFuzzy(3), WithVerbose(), WithConfigFile(path))
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "integration,slow" {
+		t.Errorf("Unexpected skipped tags %s", tags)
+	}
+	if !tc.Fuzzy || tc.EditDistance != 3 || !tc.Verbose {
+		t.Errorf("Unexpected options fuzzy=%v distance=%d verbose=%v", tc.Fuzzy, tc.EditDistance, tc.Verbose)
+	}
+}
+
+func TestNewOptionError(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected New to panic for a missing config file")
+		}
+	}()
+	New(WithConfigFile(filepath.Join(t.TempDir(), "missing.json")))
+}
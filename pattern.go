@@ -0,0 +1,72 @@
+package gotag
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// compiles a tag pattern. Tags between slashes are regular expressions,
+// e.g. /^integration-.+$/, and tags containing * or ? are wildcard
+// patterns, e.g. db-* where * matches any run of characters and ?
+// matches a single character. Returns nil for plain tags
+func compilePattern(tag string) (*regexp.Regexp, error) {
+	if len(tag) >= 2 && strings.HasPrefix(tag, "/") && strings.HasSuffix(tag, "/") {
+		re, err := regexp.Compile(tag[1 : len(tag)-1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid tag pattern '%s': %v", tag, err)
+		}
+		return re, nil
+	}
+	if !strings.ContainsAny(tag, "*?") {
+		return nil, nil
+	}
+	expr := regexp.QuoteMeta(tag)
+	expr = strings.Replace(expr, `\*`, ".*", -1)
+	expr = strings.Replace(expr, `\?`, ".", -1)
+	return regexp.Compile("^" + expr + "$")
+}
+
+// returns an error for the first malformed pattern among the tags
+func checkPatterns(tags ...string) error {
+	for _, tag := range tags {
+		if _, err := compilePattern(tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// a glob or regex tag of a set along with its compiled form
+type tagPattern struct {
+	canonical string
+	re        *regexp.Regexp
+}
+
+// reports whether any pattern in the set matches the canonical tag
+func (s *tagSet) matchPattern(canonical string) bool {
+	_, ok := s.matchingPattern(canonical)
+	return ok
+}
+
+// returns the canonical form of the first pattern
+// in the set matching the canonical tag
+func (s *tagSet) matchingPattern(canonical string) (string, bool) {
+	for _, p := range s.patterns {
+		if p.re.MatchString(canonical) {
+			return p.canonical, true
+		}
+	}
+	return "", false
+}
+
+// compiles a pattern added through Skip, RunOnly or MustRun, which
+// cannot return an error, warning about malformed patterns instead
+func mustCompilePattern(tag string) *regexp.Regexp {
+	re, err := compilePattern(tag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gotag: WARNING: %v, matching it literally\n", err)
+	}
+	return re
+}
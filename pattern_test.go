@@ -0,0 +1,39 @@
+package gotag
+
+import "testing"
+
+func TestPatterns(t *testing.T) {
+	tc := New()
+	tc.Skip("db-*", "*-slow", "/^integration-.+$/", "cache-?")
+
+	mock := &mockT{}
+	for _, tag := range []string{"db-postgres", "api-slow", "integration-s3", "cache-1"} {
+		tc.Test(tag, mock, func(t T) {})
+	}
+	if mock.skipped != 4 {
+		t.Errorf("Expected 4 tests skipped, got %d", mock.skipped)
+	}
+
+	mock = &mockT{}
+	for _, tag := range []string{"db", "slow-api", "integration-", "cache-12"} {
+		tc.Test(tag, mock, func(t T) {})
+	}
+	if mock.skipped != 0 {
+		t.Errorf("Expected no tests skipped, got %d", mock.skipped)
+	}
+
+	tc = New()
+	tc.RunOnly("unit-*")
+	mock = &mockT{}
+	tc.Test("unit-parser", mock, func(t T) {})
+	tc.Test("integration", mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Errorf("Expected 1 test skipped, got %d", mock.skipped)
+	}
+}
+
+func TestApplyInvalidPattern(t *testing.T) {
+	if err := New().Apply(&Config{Skip: []string{"/[/"}}); err == nil {
+		t.Error("Expected an error for a malformed regex")
+	}
+}
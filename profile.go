@@ -0,0 +1,62 @@
+package gotag
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// EnvProfile is the environment variable holding the name of
+// the config profile to apply, overriding the config's profile
+const EnvProfile = "GOTAG_PROFILE"
+
+// LoadProfile attempts to load a test context like Load, applying the
+// named profile of the config files on top of their other options.
+// An empty name selects the profile as Load does. Returns an error if
+// no config file could be located or none of them defines the profile
+func LoadProfile(name string) (*TestContext, error) {
+	config, err := discoverConfig(".")
+	if err != nil {
+		return nil, err
+	}
+	if config, err = config.withProfile(name); err != nil {
+		return nil, err
+	}
+	return fromConfig(config)
+}
+
+// returns the config merged with the named profile, the profile's
+// options taking precedence. An empty name selects the profile named
+// by GOTAG_PROFILE or else by the config's profile option, if any.
+// Configs without profiles are returned as they are
+func (c *Config) withProfile(name string) (*Config, error) {
+	if len(c.Profiles) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("Unknown profile '%s', no profiles are defined", name)
+		}
+		return c, nil
+	}
+	if name == "" {
+		if name = os.Getenv(EnvProfile); name == "" {
+			name = c.Profile
+		}
+	}
+	if name == "" {
+		return c, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("Unknown profile '%s', expected one of: %s", name, strings.Join(names, ", "))
+	}
+	base := *c
+	base.Profile, base.Profiles = "", nil
+	merged := MergeConfigs(&base, profile)
+	merged.Profile, merged.Profiles = "", nil
+	return merged, nil
+}
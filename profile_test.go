@@ -0,0 +1,81 @@
+package gotag
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testProfiles = `skip: [manual]
+profile: local
+profiles:
+  ci:
+    run: [integration]
+    distance: 1
+  local:
+    skip: [integration, e2e]
+`
+
+func TestLoadProfile(t *testing.T) {
+	t.Setenv(EnvUserConfig, "off")
+	t.Setenv(EnvProfile, "")
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, ".gotag.yml"), []byte(testProfiles), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	tc, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "e2e,integration,manual" {
+		t.Errorf("Expected the default profile's tags, got %s", tags)
+	}
+
+	t.Setenv(EnvProfile, "ci")
+	if tc, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.RunTags(), ","); tags != "integration" || tc.EditDistance != 1 {
+		t.Errorf("Expected the ci profile, got %s, %d", tags, tc.EditDistance)
+	}
+
+	if tc, err = LoadProfile("local"); err != nil {
+		t.Fatal(err)
+	}
+	if len(tc.RunTags()) != 0 || len(tc.SkippedTags()) != 3 {
+		t.Errorf("Expected the named profile to override GOTAG_PROFILE, got %v", tc.RunTags())
+	}
+
+	if _, err := LoadProfile("nightly"); err == nil || !strings.Contains(err.Error(), "ci, local") {
+		t.Errorf("Expected an unknown profile error, got %v", err)
+	}
+}
+
+func TestMergeConfigsProfiles(t *testing.T) {
+	farther := &Config{Profiles: map[string]*Config{"ci": {Skip: []string{"slow"}}}}
+	nearer := &Config{Profile: "ci", Profiles: map[string]*Config{"ci": {Skip: []string{"flaky"}}}}
+	merged := MergeConfigs(farther, nearer)
+	if merged.Profile != "ci" {
+		t.Errorf("Expected the nearer default profile, got %s", merged.Profile)
+	}
+	if tags := strings.Join(merged.Profiles["ci"].Skip, ","); tags != "slow,flaky" {
+		t.Errorf("Expected profiles of the same name to merge, got %s", tags)
+	}
+	if len(farther.Profiles["ci"].Skip) != 1 {
+		t.Error("Expected the merged profiles not to be modified")
+	}
+}
@@ -0,0 +1,96 @@
+package gotag
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Quarantine marks tags of flaky tests. Quarantined tests still run,
+// unless the selection skips them, but their failures are logged and
+// the test skipped instead of failing the build. Main prints a summary
+// of quarantined failures once tests have run. Failures of subtests
+// are only converted if they are started with Run, which tags them
+// with the tags of their parent. Tests run with TestFor or Benchmark
+// fail as usual since their body is not passed a T gotag can wrap
+func (tc *TestContext) Quarantine(tags ...string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	for _, tag := range tags {
+		tc.addTag(tc.quarantine, tag)
+	}
+}
+
+// Quarantine marks tags of flaky tests within the default context
+func Quarantine(tags ...string) {
+	Default().Quarantine(tags...)
+}
+
+// QuarantinedTags returns a sorted slice of quarantined tags for the TestContext
+func (tc *TestContext) QuarantinedTags() []string {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return tc.quarantine.tags(tc.InsertionOrder)
+}
+
+// quarantineFailure is a failure of a quarantined test
+type quarantineFailure struct {
+	Tag      string   `json:"tag"`
+	Test     string   `json:"test"`
+	Messages []string `json:"messages,omitempty"`
+}
+
+// returns the first of the tags that is quarantined or an empty
+// string if there is none. Must be called with the lock held
+func (tc *TestContext) quarantinedTag(tags []string) string {
+	if tc.quarantine.len() == 0 {
+		return ""
+	}
+	for _, tag := range tags {
+		if tc.quarantine.covers(tc.canonical(tag)) {
+			return tag
+		}
+	}
+	return ""
+}
+
+// wraps fn so that failures of tests run under the quarantined
+// tag are recorded and the test skipped instead of failed
+func (tc *TestContext) quarantineFn(tag string, fn interface{}) func(s skippable) {
+	return func(s skippable) {
+		t, ok := s.(T)
+		if !ok {
+			call(fn, s)
+			return
+		}
+		c := &captureT{T: t}
+		c.stop = func() {
+			f := quarantineFailure{Tag: tag, Test: c.Name(), Messages: c.failures()}
+			tc.mu.Lock()
+			tc.quarantined = append(tc.quarantined, f)
+			tc.mu.Unlock()
+			t.Skip(fmt.Sprintf("gotag: quarantined tag '%s' failed", tag))
+		}
+		call(fn, c)
+		if c.Failed() {
+			c.stop()
+		}
+	}
+}
+
+// prints the failures of quarantined tests
+func (tc *TestContext) summarizeQuarantine(w io.Writer) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if len(tc.quarantined) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "gotag: %d quarantined test(s) failed:\n", len(tc.quarantined))
+	for _, f := range tc.quarantined {
+		fmt.Fprintf(w, "    %s (%s)", f.Test, f.Tag)
+		if len(f.Messages) > 0 {
+			fmt.Fprintf(w, ": %s", strings.Join(f.Messages, "; "))
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,85 @@
+package gotag
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestQuarantine(t *testing.T) {
+	tc := New()
+	tc.Quarantine("flaky")
+
+	ran := 0
+	t.Run("error", func(st *testing.T) {
+		tc.Test("flaky", st, func(t T) {
+			ran++
+			t.Error("connection reset")
+			if !t.Failed() {
+				st.Error("Expected the quarantined test to report it failed")
+			}
+		})
+		if !st.Skipped() {
+			st.Error("Expected the failed quarantined test to be skipped")
+		}
+	})
+	t.Run("fatal", func(st *testing.T) {
+		tc.Test("flaky", st, func(t T) {
+			ran++
+			t.Fatalf("timed out after %ds", 5)
+			st.Error("Expected Fatalf to stop the test")
+		})
+	})
+	t.Run("pass", func(st *testing.T) {
+		tc.Test("flaky", st, func(t T) { ran++ })
+		if st.Skipped() {
+			st.Error("Expected a passing quarantined test not to be skipped")
+		}
+	})
+	if ran != 3 {
+		t.Errorf("Expected 3 quarantined tests to run, got %d", ran)
+	}
+
+	var buf bytes.Buffer
+	tc.summarizeQuarantine(&buf)
+	out := buf.String()
+	for _, want := range []string{
+		"gotag: 2 quarantined test(s) failed",
+		"TestQuarantine/error (flaky): connection reset",
+		"TestQuarantine/fatal (flaky): timed out after 5s",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected summary to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestQuarantineSubtests(t *testing.T) {
+	tc := New()
+	tc.Quarantine("flaky")
+
+	tc.Test("flaky", t, func(qt T) {
+		tc.Run("case", qt, "first", func(t T) {
+			t.Error("flaky case")
+		})
+	})
+	if len(tc.quarantined) != 1 || tc.quarantined[0].Test != "TestQuarantineSubtests/first" {
+		t.Errorf("Expected the subtest failure to be quarantined, got %v", tc.quarantined)
+	}
+}
+
+func TestQuarantineConfig(t *testing.T) {
+	tc := New()
+	if err := tc.Apply(&Config{Quarantine: []string{"flaky", "net.*"}}); err != nil {
+		t.Fatal(err)
+	}
+	if tags := tc.QuarantinedTags(); len(tags) != 2 {
+		t.Errorf("Expected 2 quarantined tags, got %v", tags)
+	}
+	if tc.quarantinedTag([]string{"unit", "net.dns"}) != "net.dns" {
+		t.Error("Expected pattern to quarantine net.dns")
+	}
+	if tc.quarantinedTag([]string{"unit"}) != "" {
+		t.Error("Expected unit not to be quarantined")
+	}
+}
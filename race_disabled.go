@@ -0,0 +1,6 @@
+//go:build !race
+
+package gotag
+
+// whether the race detector is enabled, see Race
+const raceEnabled = false
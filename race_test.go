@@ -0,0 +1,80 @@
+package gotag
+
+import (
+	"sync"
+	"testing"
+)
+
+// simulates several packages configuring and using the
+// default context at the same time. Run with -race
+func TestDefaultContextConcurrent(t *testing.T) {
+	previous := Default()
+	t.Cleanup(func() { SetDefault(previous) })
+	SetDefault(New())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Skip("race-skip")
+			Verbose(false)
+			Fuzzy(false)
+			Distance(2)
+			Test("race-skip", &mockT{}, func(t T) {})
+			Test("race-run", &mockT{}, func(t T) {})
+			Benchmark("race-run", &mockB{}, func(b B) {})
+			Default().SkippedTags()
+		}()
+	}
+	wg.Wait()
+
+	mock := &mockT{}
+	Test("race-skip", mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Error("Wrong number of tests skipped")
+	}
+}
+
+func TestContextConcurrent(t *testing.T) {
+	tc := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tc.Skip("tagA")
+			tc.RunOnly("tagB")
+			tc.Test("tagA", &mockT{}, func(t T) {})
+			tc.RunTags()
+		}()
+	}
+	wg.Wait()
+}
+
+// parallel subtests registering tags while others are matched
+// against a set large enough to use the fuzzy index. Run with -race
+func TestContextParallelSubtests(t *testing.T) {
+	tc := New()
+	tc.Fuzzy = true
+	for i := 0; i < fuzzyIndexThreshold; i++ {
+		tc.Skip(benchTag(i, 16))
+	}
+	t.Run("group", func(t *testing.T) {
+		for i := 0; i < 8; i++ {
+			i := i
+			t.Run(benchTag(i, 8), func(t *testing.T) {
+				t.Parallel()
+				tc.Skip(benchTag(fuzzyIndexThreshold+i, 16))
+				tc.MustRun("must")
+				tc.Test(benchTag(i, 16), &mockT{}, func(t T) {})
+				tc.TestTags([]string{"a", "b"}, &mockT{}, func(t T) {})
+				tc.WouldSkip(benchTag(i, 16))
+				tc.SkippedTags()
+			})
+		}
+	})
+	if n := len(tc.SkippedTags()); n != fuzzyIndexThreshold+8 {
+		t.Errorf("Expected %d skipped tags, got %d", fuzzyIndexThreshold+8, n)
+	}
+}
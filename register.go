@@ -0,0 +1,137 @@
+package gotag
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Register tags the top level test, benchmark or fuzz target of the given
+// name without changing its body, the way gotag generate registers tests
+// marked with //gotag: directives. Registered tests that would be skipped
+// are excluded from the run by Main through the -test.skip flag of Go
+// 1.20 and later, so Register must be called before Main, e.g. from an
+// init function. Registered tests that run are not wrapped, so their
+// tags don't apply quarantine, retries, timeouts, locks or setup hooks
+//
+//	func init() {
+//		gotag.Register("TestCheckout", gotag.Integration)
+//	}
+func (tc *TestContext) Register(name string, tags ...string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.registered[name] = append(tc.registered[name], tags...)
+}
+
+// Register tags the top level test of the
+// given name within the default context
+func Register(name string, tags ...string) {
+	Default().Register(name, tags...)
+}
+
+// registeredTest is a registered test and why it would be skipped
+type registeredTest struct {
+	name   string
+	tags   []string
+	reason skipReason
+	reqs   []*prerequisite
+}
+
+// returns the sorted names of the registered tests that would be
+// skipped, recording their decisions if record is true and the
+// context is recording
+func (tc *TestContext) registeredSkips(record bool) []string {
+	tc.mu.RLock()
+	names := make([]string, 0, len(tc.registered))
+	for name := range tc.registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var tests []registeredTest
+	for _, name := range names {
+		tags := tc.registered[name]
+		_, _, reason := tc.shouldSkip(tags)
+		if !reason.skipped() && tc.shard.total > 1 && !tc.shard.has(tags, name) {
+			reason = notInShard
+		}
+		var reqs []*prerequisite
+		if !reason.skipped() {
+			reqs = tc.prerequisitesFor(tags)
+		}
+		tests = append(tests, registeredTest{name, tags, reason, reqs})
+	}
+	dlog, reporters := tc.decisionLog, tc.reporters
+	tc.mu.RUnlock()
+
+	var skipped []string
+	var decisions []decision
+	now := time.Now()
+	for _, test := range tests {
+		// predicates may be slow so they are evaluated without the lock held
+		for _, req := range test.reqs {
+			if !req.met() {
+				test.reason = requirementUnmet
+				break
+			}
+		}
+		if !test.reason.skipped() {
+			continue
+		}
+		skipped = append(skipped, test.name)
+		for _, tag := range test.tags {
+			decisions = append(decisions, decision{Tag: tag, Test: test.name, Skipped: true, Reason: test.reason.String()})
+		}
+		e := Decision{Time: now, Test: test.name, Tags: test.tags, Skipped: true, Reason: test.reason.String()}
+		if dlog != nil {
+			dlog.write(e)
+		}
+		for _, r := range reporters {
+			r.OnDecision(e)
+		}
+	}
+
+	if record && tc.recording {
+		tc.mu.Lock()
+		tc.decisions = append(tc.decisions, decisions...)
+		tc.mu.Unlock()
+	}
+	return skipped
+}
+
+// excludes the registered tests that would be skipped from
+// the run by adding them to the -test.skip flag of fs, or
+// logs them without excluding them during a dry run
+func (tc *TestContext) skipRegistered(fs *flag.FlagSet) error {
+	tc.mu.RLock()
+	dryRun, logger := tc.DryRun, tc.Logger
+	tc.mu.RUnlock()
+	names := tc.registeredSkips(!dryRun)
+	if len(names) == 0 {
+		return nil
+	}
+	if dryRun {
+		for _, name := range names {
+			logTo(logger, nil, "gotag: dry run, would skip registered test %s", name)
+		}
+		return nil
+	}
+	f := fs.Lookup("test.skip")
+	if f == nil {
+		return fmt.Errorf("Registered tests can't be skipped, -test.skip requires Go 1.20 or later")
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	pattern := "^(" + strings.Join(quoted, "|") + ")$"
+	if current := f.Value.String(); current != "" {
+		if strings.Contains(current, "/") {
+			return fmt.Errorf("Registered tests can't be skipped alongside the subtest pattern -test.skip=%s", current)
+		}
+		pattern = current + "|" + pattern
+	}
+	return fs.Set("test.skip", pattern)
+}
@@ -0,0 +1,56 @@
+package gotag
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	tc := New()
+	tc.Register("TestCheckout", Integration)
+	tc.Register("TestParse", "unit")
+	tc.Register("TestDeploy", EndToEnd, "docker")
+	tc.Require("docker", func() bool { return false })
+	tc.RunOnly(Integration, EndToEnd)
+	tc.recording = true
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	skip := fs.String("test.skip", "", "")
+	if err := tc.skipRegistered(fs); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "^(TestDeploy|TestParse)$"; *skip != expected {
+		t.Errorf("Expected -test.skip to be %s, got %s", expected, *skip)
+	}
+	if len(tc.decisions) != 3 {
+		t.Fatalf("Expected a decision for each tag of the skipped tests, got %v", tc.decisions)
+	}
+	for _, d := range tc.decisions {
+		if !d.Skipped || d.Test == "TestCheckout" {
+			t.Errorf("Expected only skipped tests to be recorded, got %+v", d)
+		}
+	}
+}
+
+func TestRegisterExistingSkip(t *testing.T) {
+	tc := New()
+	tc.Register("TestCheckout", Integration)
+	tc.Skip(Integration)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	skip := fs.String("test.skip", "TestFlaky", "")
+	if err := tc.skipRegistered(fs); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "TestFlaky|^(TestCheckout)$"; *skip != expected {
+		t.Errorf("Expected -test.skip to be %s, got %s", expected, *skip)
+	}
+
+	*skip = "TestFlaky/sub"
+	if err := tc.skipRegistered(fs); err == nil {
+		t.Error("Expected an error combining a subtest skip pattern")
+	}
+	if err := tc.skipRegistered(flag.NewFlagSet("test", flag.ContinueOnError)); err == nil {
+		t.Error("Expected an error without a -test.skip flag")
+	}
+}
@@ -0,0 +1,117 @@
+package gotag
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Tag describes a tag registered with RegisterTag
+type Tag struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Owner       string `json:"owner,omitempty" yaml:"owner,omitempty"`
+}
+
+// RegisterTag records tags along with what they mean and who owns
+// them, listed by gotag list. Registering a tag again replaces its
+// metadata. Registering a tag registers its namespaced tags as well,
+// so registering integration covers integration.db
+//
+//	tc.RegisterTag(gotag.Tag{Name: "integration", Description: "needs postgres", Owner: "platform-team"})
+func (tc *TestContext) RegisterTag(tags ...Tag) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	for _, tag := range tags {
+		tc.tagRegistry[tc.canonical(tag.Name)] = tag
+	}
+}
+
+// RegisterTag records tags along with their
+// metadata within the default context
+func RegisterTag(tags ...Tag) {
+	Default().RegisterTag(tags...)
+}
+
+// RegisteredTags returns the tags recorded by RegisterTag, sorted by name
+func (tc *TestContext) RegisteredTags() []Tag {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	tags := make([]Tag, 0, len(tc.tagRegistry))
+	for _, tag := range tc.tagRegistry {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags
+}
+
+// OwnedTags returns the names of the registered tags owned by
+// any of the owners, sorted, so that a team can run only the
+// tagged tests it owns
+//
+//	tc.RunOnly(tc.OwnedTags("platform-team")...)
+func (tc *TestContext) OwnedTags(owners ...string) []string {
+	var names []string
+	for _, tag := range tc.RegisteredTags() {
+		for _, owner := range owners {
+			if tag.Owner == owner {
+				names = append(names, tag.Name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// RequireRegistered sets whether tests under a tag missing from
+// the tags recorded by RegisterTag fail without running
+func (tc *TestContext) RequireRegistered(required bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.requireRegistered = required
+}
+
+// RequireRegistered sets whether tests under unregistered
+// tags fail within the default context
+func RequireRegistered(required bool) {
+	Default().RequireRegistered(required)
+}
+
+// returns the first of the tags that is not registered if registration
+// is required. Must be called with at least a read lock held
+func (tc *TestContext) unregisteredTag(tags []string) (string, bool) {
+	if !tc.requireRegistered {
+		return "", false
+	}
+	for _, tag := range tags {
+		if !tc.registeredTag(tc.canonical(tag)) {
+			return tag, true
+		}
+	}
+	return "", false
+}
+
+// reports whether the canonical tag or one of its namespaces is
+// registered. Must be called with at least a read lock held
+func (tc *TestContext) registeredTag(key string) bool {
+	for i := 0; i <= len(key); i++ {
+		if i < len(key) && key[i] != '.' {
+			continue
+		}
+		if _, ok := tc.tagRegistry[key[:i]]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checks that every tag of the config is named
+func checkTags(tags []Tag) error {
+	for i, tag := range tags {
+		if tag.Name == "" {
+			return fmt.Errorf("Tag %d of the registry has no name", i+1)
+		}
+	}
+	return nil
+}
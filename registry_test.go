@@ -0,0 +1,106 @@
+package gotag
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestRegisterTag(t *testing.T) {
+	tc := New()
+	tc.RegisterTag(Tag{Name: "slow"}, Tag{Name: "integration", Description: "needs postgres", Owner: "platform-team"})
+	tc.RegisterTag(Tag{Name: "slow", Owner: "qa"})
+
+	tags := tc.RegisteredTags()
+	if len(tags) != 2 || tags[0].Name != "integration" || tags[1] != (Tag{Name: "slow", Owner: "qa"}) {
+		t.Errorf("Expected sorted tags with replaced metadata, got %v", tags)
+	}
+	if len(tc.Clone().RegisteredTags()) != 2 {
+		t.Error("Expected the clone to keep the registry")
+	}
+	tc.CaseInsensitive(true)
+	if !tc.registeredTag(tc.canonical("INTEGRATION")) {
+		t.Error("Expected registered tags to be renormalized")
+	}
+	tc.Reset()
+	if len(tc.RegisteredTags()) != 0 {
+		t.Error("Expected Reset to clear the registry")
+	}
+}
+
+func TestRequireRegistered(t *testing.T) {
+	tc := New(WithRegisteredTags(Tag{Name: "integration"}), WithRequireRegistered())
+
+	mock := &failingT{}
+	ran := 0
+	tc.Test("integration.db", mock, func(t T) { ran++ })
+	if ran != 1 || mock.fatal != "" {
+		t.Error("Expected namespaced tags of a registered tag to run")
+	}
+	tc.Test("integraton", mock, func(t T) { ran++ })
+	if ran != 1 || mock.fatal != "gotag: tag 'integraton' is not registered, see RegisterTag" {
+		t.Errorf("Expected an unregistered tag to fail, got %q", mock.fatal)
+	}
+
+	tc.RequireRegistered(false)
+	tc.Test("unit", mock, func(t T) { ran++ })
+	if ran != 2 {
+		t.Error("Expected unregistered tags to run once registration is not required")
+	}
+}
+
+func TestRegistryConfig(t *testing.T) {
+	tc := New()
+	if err := tc.Apply(&Config{Skip: []string{"slow"}, Tags: []Tag{{Owner: "qa"}}}); err == nil {
+		t.Error("Expected a tag without a name to be rejected")
+	}
+	if len(tc.SkippedTags()) != 0 {
+		t.Error("Expected an invalid config not to be applied")
+	}
+
+	data := "tags:\n  - name: integration\n    description: needs postgres\n    owner: platform-team\nrequire_registered: true\n"
+	var config Config
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+	if err := tc.Apply(&config); err != nil {
+		t.Fatal(err)
+	}
+	if tags := tc.RegisteredTags(); len(tags) != 1 || tags[0].Owner != "platform-team" || !tc.requireRegistered {
+		t.Errorf("Expected the registry to be loaded, got %v", tags)
+	}
+
+	out, err := json.Marshal(Config{Tags: tc.RegisteredTags()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"tags":[{"name":"integration","description":"needs postgres","owner":"platform-team"}]`) {
+		t.Errorf("Unexpected serialized registry %s", out)
+	}
+	decoded, err := decodeJSONConfig(strings.NewReader(string(out)))
+	if err != nil || len(decoded.Tags) != 1 || decoded.Tags[0].Name != "integration" {
+		t.Errorf("Expected the registry to round trip, got %+v, %v", decoded, err)
+	}
+
+	merged := MergeConfigs(&Config{Tags: []Tag{{Name: "a"}}}, &Config{Tags: []Tag{{Name: "b"}}})
+	if len(merged.Tags) != 2 {
+		t.Errorf("Expected registries to accumulate, got %v", merged.Tags)
+	}
+}
+
+func TestOwnedTags(t *testing.T) {
+	tc := New(WithRegisteredTags(
+		Tag{Name: "integration", Owner: "platform-team"},
+		Tag{Name: "billing", Owner: "payments"},
+		Tag{Name: "smoke", Owner: "platform-team"},
+		Tag{Name: "slow"},
+	))
+	if tags := strings.Join(tc.OwnedTags("platform-team"), ","); tags != "integration,smoke" {
+		t.Errorf("Expected the tags of the owner, got %s", tags)
+	}
+	if tags := strings.Join(tc.OwnedTags("payments", "qa"), ","); tags != "billing" {
+		t.Errorf("Expected the tags of any of the owners, got %s", tags)
+	}
+}
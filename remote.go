@@ -0,0 +1,97 @@
+package gotag
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// maxExtends is the maximum number of configs a chain
+// of extends may contain, guarding against cycles
+const maxExtends = 8
+
+// httpClient fetches remote configs
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// LoadFromURL attempts to load a test context from the JSON or YAML
+// config served at the given http or https URL. YAML is expected if
+// the URL path ends in .yml or .yaml or the response is served with a
+// YAML content type. Unless pin is empty, the config must hash to the
+// hex encoded SHA-256 it holds or an error wrapping ErrChecksumMismatch
+// is returned. Returns an error if the config could not be fetched
+func LoadFromURL(url, pin string) (*TestContext, error) {
+	config, err := fetchConfig(url, pin)
+	if err != nil {
+		return nil, err
+	}
+	if config, err = resolveExtends(config); err != nil {
+		return nil, err
+	}
+	return fromConfig(config)
+}
+
+// fetches and decodes the config at the given URL, verifying
+// its content against the pinned SHA-256 if one is given
+func fetchConfig(url, pin string) (*Config, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("Invalid config URL '%s', expected http or https", url)
+	}
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Could not fetch config from %s: %s", url, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyChecksum(data, pin); err != nil {
+		return nil, fmt.Errorf("%w: %s", err, url)
+	}
+
+	load := loadJSONConfig
+	ext := strings.ToLower(path.Ext(strings.SplitN(url, "?", 2)[0]))
+	if ext == ".yml" || ext == ".yaml" || strings.Contains(resp.Header.Get("Content-Type"), "yaml") {
+		load = loadYAMLConfig
+	}
+	config, err := load(bytes.NewReader(data))
+	if errs, ok := err.(ConfigErrors); ok {
+		return nil, errs.in(url)
+	}
+	return config, err
+}
+
+// merges the configs the given config extends, farthest first, under it
+// so that its own options override theirs and its tags add to theirs
+func resolveExtends(config *Config) (*Config, error) {
+	chain := []*Config{config}
+	seen := make(map[string]bool)
+	for c := config; c.Extends != ""; c = chain[len(chain)-1] {
+		if seen[c.Extends] || len(chain) >= maxExtends {
+			return nil, fmt.Errorf("Config extends too many configs or itself: %s", c.Extends)
+		}
+		seen[c.Extends] = true
+		base, err := fetchConfig(c.Extends, c.ExtendsSHA256)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, base)
+	}
+	if len(chain) == 1 {
+		return config, nil
+	}
+
+	merged := chain[len(chain)-1]
+	for i := len(chain) - 2; i >= 0; i-- {
+		merged.merge(chain[i])
+	}
+	merged.Extends, merged.ExtendsSHA256 = "", ""
+	return merged, nil
+}
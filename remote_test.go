@@ -0,0 +1,100 @@
+package gotag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const orgConfig = "skip: [flaky-vendor]\nfuzzy: true\ndistance: 3\n"
+
+func serveConfigs(t *testing.T, configs map[string]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		config, ok := configs[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, strings.Replace(config, "SERVER", "http://"+r.Host, -1))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLoadFromURL(t *testing.T) {
+	srv := serveConfigs(t, map[string]string{
+		"/org.yml":   orgConfig,
+		"/team.json": `{"extends": "SERVER/org.yml", "skip": ["slow"], "distance": 1}`,
+	})
+
+	tc, err := LoadFromURL(srv.URL+"/team.json", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "flaky-vendor,slow" {
+		t.Errorf("Expected skipped tags of both configs, got %s", tags)
+	}
+	if !tc.Fuzzy || tc.EditDistance != 1 {
+		t.Errorf("Expected the extending config to override the distance, got fuzzy=%v distance=%d",
+			tc.Fuzzy, tc.EditDistance)
+	}
+
+	sum := sha256.Sum256([]byte(orgConfig))
+	if _, err := LoadFromURL(srv.URL+"/org.yml", hex.EncodeToString(sum[:])); err != nil {
+		t.Errorf("Expected the pinned config to load, got %v", err)
+	}
+	if _, err := LoadFromURL(srv.URL+"/org.yml", strings.Repeat("0", 64)); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+
+	if _, err := LoadFromURL(srv.URL+"/missing.yml", ""); err == nil {
+		t.Error("Expected an error for a missing config")
+	}
+	if _, err := LoadFromURL("ftp://example.com/gotag.yml", ""); err == nil {
+		t.Error("Expected an error for an unsupported scheme")
+	}
+}
+
+func TestExtendsChecksum(t *testing.T) {
+	srv := serveConfigs(t, map[string]string{"/org.yml": orgConfig})
+	sum := sha256.Sum256([]byte(orgConfig))
+
+	dir := t.TempDir()
+	write := func(pin string) {
+		config := fmt.Sprintf("extends: %s/org.yml\nextends_sha256: %s\nrun: [unit]\n", srv.URL, pin)
+		if err := ioutil.WriteFile(filepath.Join(dir, ".gotag.yml"), []byte(config), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(hex.EncodeToString(sum[:]))
+	tc, err := LoadFrom(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "flaky-vendor" {
+		t.Errorf("Expected the extended config to be applied, got %s", tags)
+	}
+
+	write(strings.Repeat("0", 64))
+	if _, err := LoadFrom(dir); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+}
+
+func TestExtendsCycle(t *testing.T) {
+	srv := serveConfigs(t, map[string]string{
+		"/a.yml": "extends: SERVER/b.yml\n",
+		"/b.yml": "extends: SERVER/a.yml\n",
+	})
+	if _, err := LoadFromURL(srv.URL+"/a.yml", ""); err == nil {
+		t.Error("Expected an error for configs extending each other")
+	}
+}
@@ -0,0 +1,89 @@
+package gotag
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Report summarizes the decisions made for the tests
+// of a TestContext per tag
+type Report struct {
+	Tags []TagReport `json:"tags"`
+}
+
+// TagReport holds the number of tests run and skipped under a tag,
+// why they were skipped and the time the tests that ran took
+type TagReport struct {
+	Tag      string         `json:"tag"`
+	Run      int            `json:"run"`
+	Skipped  int            `json:"skipped"`
+	Failed   int            `json:"failed"`
+	Duration time.Duration  `json:"duration"`
+	Reasons  map[string]int `json:"reasons,omitempty"`
+}
+
+// Report returns the number of tests run, skipped and failed per tag,
+// the reasons for the skips and the wall-clock time of the tests that
+// ran, sorted by tag. Tests with several tags count under each of them.
+// Decisions are recorded while Main runs the suite, so Report is meant
+// to be called once m.Run has returned
+func (tc *TestContext) Report() *Report {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+
+	byTag := make(map[string]*TagReport)
+	for _, d := range tc.decisions {
+		r, ok := byTag[d.Tag]
+		if !ok {
+			r = &TagReport{Tag: d.Tag}
+			byTag[d.Tag] = r
+		}
+		if d.Skipped {
+			r.Skipped++
+			if r.Reasons == nil {
+				r.Reasons = make(map[string]int)
+			}
+			r.Reasons[d.Reason]++
+			continue
+		}
+		r.Run++
+		if d.Failed {
+			r.Failed++
+		}
+		r.Duration += d.Duration
+	}
+
+	report := &Report{Tags: make([]TagReport, 0, len(byTag))}
+	for _, r := range byTag {
+		report.Tags = append(report.Tags, *r)
+	}
+	sort.Slice(report.Tags, func(i, j int) bool {
+		return report.Tags[i].Tag < report.Tags[j].Tag
+	})
+	return report
+}
+
+// String formats the report as a table with a row per tag
+func (r *Report) String() string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tRUN\tSKIPPED\tFAILED\tDURATION\tREASONS")
+	for _, t := range r.Tags {
+		reasons := make([]string, 0, len(t.Reasons))
+		for reason := range t.Reasons {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for i, reason := range reasons {
+			reasons[i] = fmt.Sprintf("%s (%d)", reason, t.Reasons[reason])
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n", t.Tag, t.Run, t.Skipped, t.Failed, t.Duration,
+			strings.Join(reasons, ", "))
+	}
+	w.Flush()
+	return b.String()
+}
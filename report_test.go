@@ -0,0 +1,38 @@
+package gotag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	tc := New()
+	tc.Skip("tagA")
+	tc.Require("tagC", func() bool { return false })
+	tc.recording = true
+
+	mock := &mockT{}
+	tc.Test("tagA", mock, func(t T) {})
+	tc.Test("tagA", mock, func(t T) {})
+	tc.TestTags([]string{"tagB", "tagC"}, mock, func(t T) {})
+	tc.Test("tagB", mock, func(t T) {})
+
+	report := tc.Report()
+	if len(report.Tags) != 3 {
+		t.Fatalf("Expected 3 tags, got %d", len(report.Tags))
+	}
+	a, b := report.Tags[0], report.Tags[1]
+	if a.Tag != "tagA" || a.Skipped != 2 || a.Run != 0 || a.Reasons["in skip list"] != 2 {
+		t.Errorf("Unexpected report for tagA %+v", a)
+	}
+	if b.Tag != "tagB" || b.Skipped != 1 || b.Run != 1 || b.Reasons["requirement not met"] != 1 {
+		t.Errorf("Unexpected report for tagB %+v", b)
+	}
+
+	out := report.String()
+	for _, want := range []string{"TAG", "tagA", "in skip list (2)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected table to contain %q, got\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package gotag
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Reporter receives the events of a run. OnDecision is called for
+// every decision to run or skip a test, OnTestStart and OnTestEnd
+// around every test gotag runs and OnRunEnd with the per tag Report
+// once Main has run the suite. Tests may run in parallel so reporters
+// must be safe for concurrent use
+type Reporter interface {
+	OnDecision(d Decision)
+	OnTestStart(test string, tags []string)
+	OnTestEnd(result TestResult)
+	OnRunEnd(report *Report)
+}
+
+// TestResult is the outcome of a test gotag ran
+type TestResult struct {
+	Test     string
+	Tags     []string
+	Failed   bool
+	Duration time.Duration
+}
+
+// AddReporter adds reporters notified of the events of the run
+//
+//	tc.AddReporter(gotag.NewJUnitReporter(f))
+func (tc *TestContext) AddReporter(reporters ...Reporter) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	// copied so that the slice read by running tests never changes
+	tc.reporters = append(tc.reporters[:len(tc.reporters):len(tc.reporters)], reporters...)
+}
+
+// AddReporter adds reporters to the default context
+func AddReporter(reporters ...Reporter) {
+	Default().AddReporter(reporters...)
+}
+
+// WithReporter adds a reporter notified of the events of the run
+func WithReporter(r Reporter) Option {
+	return func(tc *TestContext) error {
+		tc.AddReporter(r)
+		return nil
+	}
+}
+
+// NewTextReporter returns a reporter writing the per tag Report
+// to w as a table once the run ends
+func NewTextReporter(w io.Writer) Reporter {
+	return &textReporter{w: w}
+}
+
+type textReporter struct {
+	w io.Writer
+}
+
+func (r *textReporter) OnDecision(Decision)          {}
+func (r *textReporter) OnTestStart(string, []string) {}
+func (r *textReporter) OnTestEnd(TestResult)         {}
+
+func (r *textReporter) OnRunEnd(report *Report) {
+	fmt.Fprint(r.w, report)
+}
+
+// NewJSONLReporter returns a reporter streaming every decision to w
+// as a line of JSON, the way LogDecisions does
+func NewJSONLReporter(w io.Writer) Reporter {
+	return &decisionLog{w: w}
+}
+
+func (l *decisionLog) OnDecision(d Decision) {
+	l.write(d)
+}
+
+func (l *decisionLog) OnTestStart(string, []string) {}
+func (l *decisionLog) OnTestEnd(TestResult)         {}
+func (l *decisionLog) OnRunEnd(*Report)             {}
+
+// NewJUnitReporter returns a reporter writing the decisions to w as
+// JUnit XML with a test suite per tag once the run ends, the way
+// WithJUnitFile does
+func NewJUnitReporter(w io.Writer) Reporter {
+	return &junitReporter{w: w}
+}
+
+type junitReporter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	decisions []decision
+}
+
+func (r *junitReporter) OnDecision(d Decision) {
+	if !d.Skipped {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tag := range d.Tags {
+		r.decisions = append(r.decisions, decision{Tag: tag, Test: d.Test, Skipped: true, Reason: d.Reason})
+	}
+}
+
+func (r *junitReporter) OnTestStart(string, []string) {}
+
+func (r *junitReporter) OnTestEnd(result TestResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tag := range result.Tags {
+		r.decisions = append(r.decisions,
+			decision{Tag: tag, Test: result.Test, Failed: result.Failed, Duration: result.Duration})
+	}
+}
+
+func (r *junitReporter) OnRunEnd(*Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if bytes, err := junitXML(r.decisions); err == nil {
+		r.w.Write(bytes)
+	}
+}
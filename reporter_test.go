@@ -0,0 +1,111 @@
+package gotag
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingReporter records the events it is notified of
+type recordingReporter struct {
+	mu      sync.Mutex
+	events  []string
+	results []TestResult
+	report  *Report
+}
+
+func (r *recordingReporter) OnDecision(d Decision) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, "decision "+d.Tag+" "+d.Reason)
+}
+
+func (r *recordingReporter) OnTestStart(test string, tags []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, "start "+strings.Join(tags, ","))
+}
+
+func (r *recordingReporter) OnTestEnd(result TestResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, "end "+strings.Join(result.Tags, ","))
+	r.results = append(r.results, result)
+}
+
+func (r *recordingReporter) OnRunEnd(report *Report) {
+	r.report = report
+}
+
+// erroringT records failures reported with Error
+type erroringT struct {
+	mockT
+	failed bool
+}
+
+func (t *erroringT) Error(...interface{}) { t.failed = true }
+func (t *erroringT) Failed() bool         { return t.failed }
+
+func TestReporter(t *testing.T) {
+	r := &recordingReporter{}
+	tc := New()
+	tc.AddReporter(r)
+	tc.Skip("tagA")
+
+	m := runnerFunc(func() int {
+		tc.Test("tagA", &mockT{}, func(t T) {})
+		tc.Test("tagB", &erroringT{}, func(t T) { t.Error("boom") })
+		return 0
+	})
+	if code := tc.main(m); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	want := "decision tagA in skip list|decision  |start tagB|end tagB"
+	if got := strings.Join(r.events, "|"); got != want {
+		t.Errorf("Expected events %q, got %q", want, got)
+	}
+	if len(r.results) != 1 || !r.results[0].Failed {
+		t.Errorf("Expected the failure to be reported, got %+v", r.results)
+	}
+	if r.report == nil || len(r.report.Tags) != 2 {
+		t.Errorf("Expected the run report, got %+v", r.report)
+	}
+
+	// reporters are shared by clones
+	c := tc.Clone()
+	c.Test("tagC", &mockT{}, func(t T) {})
+	if last := r.events[len(r.events)-1]; last != "end tagC" {
+		t.Errorf("Expected the clone to notify the reporter, got %q", last)
+	}
+}
+
+func TestBuiltinReporters(t *testing.T) {
+	var text, jsonl, junit bytes.Buffer
+	tc := New(WithReporter(NewTextReporter(&text)), WithReporter(NewJSONLReporter(&jsonl)),
+		WithReporter(NewJUnitReporter(&junit)))
+	tc.Skip("tagA")
+	m := runnerFunc(func() int {
+		tc.Test("tagA", &mockT{}, func(t T) {})
+		tc.Test("tagB", &mockT{}, func(t T) {})
+		return 0
+	})
+	if code := tc.main(m); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+
+	if !strings.HasPrefix(text.String(), "TAG") || !strings.Contains(text.String(), "in skip list (1)") {
+		t.Errorf("Unexpected text report %q", text.String())
+	}
+	if lines := strings.Count(jsonl.String(), "\n"); lines != 2 {
+		t.Errorf("Expected a line per decision, got %q", jsonl.String())
+	}
+	var suites junitSuites
+	if err := xml.Unmarshal(junit.Bytes(), &suites); err != nil {
+		t.Fatal(err)
+	}
+	if len(suites.Suites) != 2 || suites.Suites[0].Skipped != 1 || suites.Suites[1].Tests != 1 {
+		t.Errorf("Unexpected JUnit report %+v", suites.Suites)
+	}
+}
@@ -0,0 +1,164 @@
+package gotag
+
+import (
+	"context"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// prerequisite is a named predicate evaluated at most
+// once, the first time a test tagged with it runs
+type prerequisite struct {
+	name string
+	pred func() bool
+	once sync.Once
+	ok   bool
+}
+
+func (r *prerequisite) met() bool {
+	r.once.Do(func() {
+		r.ok = r.pred()
+	})
+	return r.ok
+}
+
+// Require registers a predicate for a requirement. Tests tagged with
+// the requirement, or a tag in its namespace, are skipped with a message
+// naming the requirement if the predicate does not hold. The predicate
+// is evaluated once, when the first such test runs
+//
+//	tc.Require("docker", gotag.RequireDocker())
+//	tc.TestTags([]string{"integration", "docker"}, t, func(t gotag.T) { ... })
+func (tc *TestContext) Require(name string, pred func() bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.prerequisites[tc.canonical(name)] = &prerequisite{name: name, pred: pred}
+}
+
+// Require registers a predicate for a requirement within the default context
+func Require(name string, pred func() bool) {
+	Default().Require(name, pred)
+}
+
+// returns the requirements registered for the tags or their
+// namespaces. Must be called with at least a read lock held
+func (tc *TestContext) prerequisitesFor(tags []string) []*prerequisite {
+	if len(tc.prerequisites) == 0 {
+		return nil
+	}
+	var reqs []*prerequisite
+	for _, tag := range tags {
+		key := tc.canonical(tag)
+		for i := 0; i <= len(key); i++ {
+			if i < len(key) && key[i] != '.' {
+				continue
+			}
+			if req, ok := tc.prerequisites[key[:i]]; ok {
+				reqs = append(reqs, req)
+			}
+		}
+	}
+	return reqs
+}
+
+// RequireEnv returns a predicate that holds if every
+// given environment variable is set and not empty
+func RequireEnv(vars ...string) func() bool {
+	return func() bool {
+		for _, v := range vars {
+			if os.Getenv(v) == "" {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// RequireCommand returns a predicate that holds if every
+// given command can be found in the PATH
+func RequireCommand(names ...string) func() bool {
+	return func() bool {
+		for _, name := range names {
+			if _, err := exec.LookPath(name); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// NetworkProbe is the address RequireNetwork dials
+var NetworkProbe = "proxy.golang.org:443"
+
+// RequireNetwork returns a predicate that holds if a TCP
+// connection to NetworkProbe can be established
+func RequireNetwork() func() bool {
+	return func() bool {
+		conn, err := net.DialTimeout("tcp", NetworkProbe, 5*time.Second)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}
+}
+
+// RequireReachable returns a predicate that holds if a TCP connection
+// to every given address, such as localhost:5432, can be established
+func RequireReachable(addrs ...string) func() bool {
+	return func() bool {
+		for _, addr := range addrs {
+			conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+			if err != nil {
+				return false
+			}
+			conn.Close()
+		}
+		return true
+	}
+}
+
+// RequireDNS returns a predicate that holds if every given
+// host name, such as internal.corp, resolves
+func RequireDNS(hosts ...string) func() bool {
+	return func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		for _, host := range hosts {
+			if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// RequireFreePort returns a predicate that holds if every
+// given TCP port can be listened on, for tests starting
+// servers on fixed ports
+func RequireFreePort(ports ...int) func() bool {
+	return func() bool {
+		for _, port := range ports {
+			l, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+			if err != nil {
+				return false
+			}
+			l.Close()
+		}
+		return true
+	}
+}
+
+// RequireDocker returns a predicate that holds if the docker
+// command is installed and can reach a docker daemon
+func RequireDocker() func() bool {
+	return func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return exec.CommandContext(ctx, "docker", "info").Run() == nil
+	}
+}
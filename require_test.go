@@ -0,0 +1,71 @@
+package gotag
+
+import (
+	"net"
+	"testing"
+)
+
+func TestRequire(t *testing.T) {
+	tc := New()
+	calls := 0
+	tc.Require("docker", func() bool {
+		calls++
+		return false
+	})
+	tc.Require("db", RequireEnv("GOTAG_TEST_DATABASE_URL"))
+	t.Setenv("GOTAG_TEST_DATABASE_URL", "postgres://localhost")
+
+	mock := &mockT{}
+	tc.Test("docker", mock, func(t T) {})
+	tc.Test("docker.compose", mock, func(t T) {})
+	tc.TestTags([]string{"integration", "db"}, mock, func(t T) {})
+	tc.Test("dockers", mock, func(t T) {})
+	if mock.skipped != 2 {
+		t.Errorf("Expected 2 tests skipped, got %d", mock.skipped)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the predicate to be evaluated once, got %d", calls)
+	}
+	if skip, reason := tc.WouldSkip("docker"); skip {
+		t.Errorf("Expected WouldSkip to not evaluate requirements, got %s", reason)
+	}
+}
+
+func TestRequireBuiltins(t *testing.T) {
+	t.Setenv("GOTAG_TEST_SET", "1")
+	if !RequireEnv("GOTAG_TEST_SET")() || RequireEnv("GOTAG_TEST_SET", "GOTAG_TEST_UNSET")() {
+		t.Error("Unexpected RequireEnv result")
+	}
+	if RequireCommand("gotag-test-missing-command")() {
+		t.Error("Expected a missing command to not be found")
+	}
+}
+
+func TestRequireNetworkBuiltins(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	port := l.Addr().(*net.TCPAddr).Port
+	if !RequireReachable(addr)() {
+		t.Errorf("Expected %s to be reachable", addr)
+	}
+	if RequireFreePort(port)() {
+		t.Errorf("Expected port %d to be taken", port)
+	}
+	l.Close()
+	if RequireReachable(addr)() {
+		t.Errorf("Expected %s to be unreachable once closed", addr)
+	}
+	if !RequireFreePort(port)() {
+		t.Errorf("Expected port %d to be free once closed", port)
+	}
+
+	if !RequireDNS("localhost")() {
+		t.Error("Expected localhost to resolve")
+	}
+	if RequireDNS("localhost", "gotag.invalid")() {
+		t.Error("Expected a reserved invalid name not to resolve")
+	}
+}
@@ -0,0 +1,108 @@
+package gotag
+
+import "time"
+
+// Unskip removes tags marked by Skip. Unskipping a group removes its
+// members as well. Namespaced tags are not affected, so unskipping
+// integration.db does not unskip tests under a skipped integration
+func (tc *TestContext) Unskip(tags ...string) {
+	tc.checkStarted("Unskip", tags)
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.skip = tc.removeTags(tc.skip, tags)
+	tc.invalidate()
+}
+
+// Unskip removes tags marked by Skip within the default context
+func Unskip(tags ...string) {
+	Default().Unskip(tags...)
+}
+
+// ClearRunOnly removes every tag marked by RunOnly, so that
+// skipped tags apply again regardless of the Mode
+func (tc *TestContext) ClearRunOnly() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.runOnly = tc.emptyTagSet()
+	tc.invalidate()
+}
+
+// ClearRunOnly removes every tag marked by
+// RunOnly within the default context
+func ClearRunOnly() {
+	Default().ClearRunOnly()
+}
+
+// Reset undoes every registration made on the context: skipped, run
+// only, must run, short mode and quarantined tags, skip entries, host
+// skips, groups, requirements, dependencies, hooks, fixtures, retries,
+// timeouts, priorities, locks, registered tests and tags, the selector,
+// DefaultSkip and the shard, along with the decisions recorded so far.
+// Exported fields such as Fuzzy and Verbose and case insensitivity are
+// left as they are. Fixtures created so far are not released, so
+// Teardown should be called first. Reset is intended for long lived
+// helpers and tests that reuse a context and must not be called while
+// tests are running
+func (tc *TestContext) Reset() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.skip = tc.emptyTagSet()
+	tc.runOnly = tc.emptyTagSet()
+	tc.mustRun = tc.emptyTagSet()
+	tc.shortSkip = tc.emptyTagSet()
+	tc.quarantine = tc.emptyTagSet()
+	tc.quarantined = nil
+	tc.selector = nil
+	tc.defaultSkip = false
+	tc.shard = shard{}
+	tc.groups = make(map[string][]string)
+	tc.prerequisites = make(map[string]*prerequisite)
+	tc.hooks = make(map[string]*tagHooks)
+	tc.allHooks = nil
+	tc.fixtures = make(map[string]*fixture)
+	tc.created = nil
+	tc.used = nil
+	tc.retries = make(map[string]retryPolicy)
+	tc.timeouts = make(map[string]time.Duration)
+	tc.priorities = make(map[string]int)
+	tc.hostSkips = make(map[string]hostSkip)
+	tc.exclusions = make(map[string][]*exclusiveLock)
+	tc.dependencies = make(map[string][]string)
+	tc.registered = make(map[string][]string)
+	tc.tagRegistry = make(map[string]Tag)
+	tc.skipEntries = make(map[string]SkipEntry)
+	tc.expired = nil
+	tc.messages = make(map[skipKey][]interface{})
+	tc.decisions = nil
+	tc.started.Store(false)
+	tc.invalidate()
+}
+
+// Reset undoes every registration made on the default context
+func Reset() {
+	Default().Reset()
+}
+
+// returns an empty tag set matching the case sensitivity of
+// the context. Must be called with the lock held
+func (tc *TestContext) emptyTagSet() *tagSet {
+	set := newTagSet()
+	set.foldCase = tc.foldCase
+	return set
+}
+
+// returns a copy of the set without the tags and the members of those
+// naming groups. Must be called with the lock held
+func (tc *TestContext) removeTags(set *tagSet, tags []string) *tagSet {
+	removed := newTagSet()
+	for _, tag := range tags {
+		tc.addTag(removed, tag)
+	}
+	kept := tc.emptyTagSet()
+	for _, key := range set.order {
+		if !removed.has(key) {
+			kept.add(set.originals[key], key)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,68 @@
+package gotag
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnskip(t *testing.T) {
+	tc := New()
+	tc.DefineGroup("slow", "db", "network")
+	tc.Skip("tagA", "tagB", "slow")
+	tc.Unskip("tagA", "slow")
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "tagB" {
+		t.Errorf("Expected only tagB to remain skipped, got %s", tags)
+	}
+	if skipped, _ := tc.WouldSkip("db"); skipped {
+		t.Error("Expected the members of an unskipped group to run")
+	}
+
+	tc.CaseInsensitive(true)
+	tc.Unskip("TAGB")
+	if tags := tc.SkippedTags(); len(tags) != 0 {
+		t.Errorf("Expected unskipping to ignore case, got %v", tags)
+	}
+}
+
+func TestClearRunOnly(t *testing.T) {
+	tc := New()
+	tc.Skip("tagA")
+	tc.RunOnly("tagB")
+	tc.ClearRunOnly()
+	if tags := tc.RunTags(); len(tags) != 0 {
+		t.Errorf("Expected no run tags, got %v", tags)
+	}
+	if skipped, _ := tc.WouldSkip("tagA"); !skipped {
+		t.Error("Expected skipped tags to apply again")
+	}
+}
+
+func TestReset(t *testing.T) {
+	tc := New()
+	tc.Fuzzy = true
+	tc.Skip("tagA")
+	tc.RunOnly("tagB")
+	tc.MustRun("tagC")
+	tc.Quarantine("tagD")
+	tc.DefineGroup("group", "tagA")
+	tc.Timeout("tagB", time.Second)
+	tc.Register("TestA", "tagA")
+	tc.DefaultSkip(true)
+	tc.Shard(1, 2)
+	tc.Test("tagB", &mockT{}, func(t T) {})
+
+	tc.Reset()
+	if len(tc.SkippedTags())+len(tc.RunTags())+len(tc.QuarantinedTags()) != 0 {
+		t.Error("Expected no tags after Reset")
+	}
+	if len(tc.groups)+len(tc.timeouts)+len(tc.registered) != 0 || tc.defaultSkip || tc.shard.total != 0 {
+		t.Error("Expected every registration to be undone")
+	}
+	if tc.started.Load() {
+		t.Error("Expected Reset to allow mutations without warnings")
+	}
+	if !tc.Fuzzy {
+		t.Error("Expected exported settings to be kept")
+	}
+}
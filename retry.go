@@ -0,0 +1,97 @@
+package gotag
+
+import (
+	"fmt"
+	"time"
+)
+
+// retryPolicy is the number of attempts tests under a
+// tag are given and the time waited between them
+type retryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// Retry gives tests under the tag, or a tag in its namespace, the given
+// number of attempts. Failed attempts are logged and the test is run
+// again after waiting backoff, only the last attempt's failures are
+// reported. A test with several retried tags gets the most attempts.
+// Attempts below 2 disable retries for the tag. Tests run with TestFor
+// are not retried since their body is not passed a T gotag can wrap
+//
+//	tc.Retry("network", 3, time.Second)
+func (tc *TestContext) Retry(tag string, attempts int, backoff time.Duration) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.retries[tc.canonical(tag)] = retryPolicy{attempts, backoff}
+}
+
+// Retry gives tests under the tag the given number
+// of attempts within the default context
+func Retry(tag string, attempts int, backoff time.Duration) {
+	Default().Retry(tag, attempts, backoff)
+}
+
+// returns the retry policy with the most attempts of the tags and their
+// namespaces, the most specific namespace taking precedence. Must be
+// called with at least a read lock held
+func (tc *TestContext) retryPolicyFor(tags []string) retryPolicy {
+	var policy retryPolicy
+	if len(tc.retries) == 0 {
+		return policy
+	}
+	for _, tag := range tags {
+		key := tc.canonical(tag)
+		var found retryPolicy
+		for i := 0; i <= len(key); i++ {
+			if i < len(key) && key[i] != '.' {
+				continue
+			}
+			if p, ok := tc.retries[key[:i]]; ok {
+				found = p
+			}
+		}
+		if found.attempts > policy.attempts {
+			policy = found
+		}
+	}
+	return policy
+}
+
+// aborts a failed attempt
+type attemptFailed struct{}
+
+// wraps fn so that every attempt but the last records its
+// failures and the test is run again if it failed
+func retryFn(policy retryPolicy, fn interface{}) func(s skippable) {
+	return func(s skippable) {
+		t, ok := s.(T)
+		if !ok {
+			call(fn, s)
+			return
+		}
+		for attempt := 1; attempt < policy.attempts; attempt++ {
+			if runAttempt(t, fn) {
+				return
+			}
+			t.Log(fmt.Sprintf("gotag: attempt %d of %d failed, retrying in %s", attempt, policy.attempts, policy.backoff))
+			time.Sleep(policy.backoff)
+		}
+		call(fn, t)
+	}
+}
+
+// runs a single attempt, returning whether it succeeded
+func runAttempt(t T, fn interface{}) (ok bool) {
+	c := &captureT{T: t, stop: func() { panic(attemptFailed{}) }}
+	defer func() {
+		if r := recover(); r != nil {
+			if _, aborted := r.(attemptFailed); !aborted {
+				panic(r)
+			}
+			ok = false
+		}
+	}()
+	call(fn, c)
+	return !c.Failed()
+}
@@ -0,0 +1,83 @@
+package gotag
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	tc := New()
+	tc.Retry("network", 3, time.Millisecond)
+
+	attempts := 0
+	t.Run("eventually", func(st *testing.T) {
+		tc.Test("network.dns", st, func(t T) {
+			attempts++
+			if attempts < 3 {
+				t.Fatal("connection refused")
+			}
+		})
+		if st.Failed() {
+			st.Error("Expected the third attempt to succeed")
+		}
+	})
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+
+	attempts = 0
+	tc.Test("network", t, func(t T) { attempts++ })
+	if attempts != 1 {
+		t.Errorf("Expected a passing test to run once, got %d", attempts)
+	}
+
+	attempts = 0
+	tc.Test("unit", t, func(t T) {
+		attempts++
+	})
+	if attempts != 1 {
+		t.Errorf("Expected unit to run once, got %d", attempts)
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	tc := New()
+	tc.Retry("network", 2, 0)
+	tc.Quarantine("network")
+
+	attempts := 0
+	t.Run("always", func(st *testing.T) {
+		tc.Test("network", st, func(t T) {
+			attempts++
+			t.Error("connection refused")
+		})
+	})
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+	if len(tc.quarantined) != 1 {
+		t.Errorf("Expected the last attempt's failure to be quarantined, got %v", tc.quarantined)
+	}
+}
+
+func TestRetryPolicyFor(t *testing.T) {
+	tc := New()
+	tc.Retry("network", 3, time.Second)
+	tc.Retry("network.flaky", 5, 0)
+	tc.Retry("db", 2, 0)
+
+	cases := []struct {
+		tags     []string
+		attempts int
+	}{
+		{[]string{"network.dns"}, 3},
+		{[]string{"network.flaky.x"}, 5},
+		{[]string{"db", "network"}, 3},
+		{[]string{"unit"}, 0},
+	}
+	for _, c := range cases {
+		if p := tc.retryPolicyFor(c.tags); p.attempts != c.attempts {
+			t.Errorf("Expected %d attempts for %v, got %d", c.attempts, c.tags, p.attempts)
+		}
+	}
+}
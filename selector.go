@@ -0,0 +1,158 @@
+package gotag
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Selector selects tests by label. Tags of the form key=value are
+// labels; any other tag is a label key with an empty value. The
+// syntax mirrors Kubernetes label selectors, a comma separated list
+// of requirements that must all hold:
+//
+//	speed=fast, speed==fast   label equals value
+//	speed!=slow               label is missing or does not equal value
+//	requires in (db, cache)   label is one of the values
+//	requires notin (network)  label is missing or none of the values
+//	integration, !manual      label key exists or does not
+type Selector struct {
+	source       string
+	requirements []requirement
+}
+
+type requirement struct {
+	key    string
+	op     string
+	values []string
+}
+
+var (
+	setRequirement    = regexp.MustCompile(`^([^\s!=(),]+)\s+(in|notin)\s*\(([^()]*)\)$`)
+	valueRequirement  = regexp.MustCompile(`^([^\s!=(),]+)\s*(!=|==|=)\s*([^\s!=(),]*)$`)
+	existsRequirement = regexp.MustCompile(`^(!?)\s*([^\s!=(),]+)$`)
+)
+
+// ParseSelector parses a label selector such as
+// "speed!=slow, requires in (db)"
+func ParseSelector(s string) (*Selector, error) {
+	sel := &Selector{source: s}
+	for _, part := range splitRequirements(s) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var r requirement
+		if m := setRequirement.FindStringSubmatch(part); m != nil {
+			r = requirement{key: m[1], op: m[2], values: splitTags(m[3])}
+		} else if m := valueRequirement.FindStringSubmatch(part); m != nil {
+			r = requirement{key: m[1], op: m[2], values: []string{m[3]}}
+			if r.op == "==" {
+				r.op = "="
+			}
+		} else if m := existsRequirement.FindStringSubmatch(part); m != nil {
+			r = requirement{key: m[2], op: "exists"}
+			if m[1] == "!" {
+				r.op = "!exists"
+			}
+		} else {
+			return nil, fmt.Errorf("Invalid selector requirement '%s'", part)
+		}
+		sel.requirements = append(sel.requirements, r)
+	}
+	return sel, nil
+}
+
+// splits a selector on commas outside of parentheses
+func splitRequirements(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// String returns the selector as it was parsed
+func (sel *Selector) String() string {
+	return sel.source
+}
+
+// Matches reports whether the given tags satisfy every requirement
+func (sel *Selector) Matches(tags ...string) bool {
+	labels := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value := tag, ""
+		if i := strings.Index(tag, "="); i >= 0 {
+			key, value = tag[:i], tag[i+1:]
+		}
+		labels[key] = value
+	}
+
+	for _, r := range sel.requirements {
+		value, ok := labels[r.key]
+		switch r.op {
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "!exists":
+			if ok {
+				return false
+			}
+		case "=":
+			if !ok || value != r.values[0] {
+				return false
+			}
+		case "!=":
+			if ok && value == r.values[0] {
+				return false
+			}
+		case "in":
+			if !ok || !contains(r.values, value) {
+				return false
+			}
+		case "notin":
+			if ok && contains(r.values, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Select restricts the TestContext instance to tests whose tags match
+// the selector. Tests that would otherwise run are skipped if they don't
+// match. Passing nil removes the selector
+func (tc *TestContext) Select(sel *Selector) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.selector = sel
+	tc.invalidate()
+}
+
+// Selector returns the selector set on the TestContext instance, if any
+func (tc *TestContext) Selector() *Selector {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return tc.selector
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package gotag
+
+import "testing"
+
+func TestSelectorMatches(t *testing.T) {
+	cases := []struct {
+		selector string
+		tags     []string
+		matches  bool
+	}{
+		{"speed=fast", []string{"speed=fast"}, true},
+		{"speed==fast", []string{"speed=slow"}, false},
+		{"speed!=slow", []string{"integration"}, true},
+		{"speed!=slow", []string{"speed=slow"}, false},
+		{"requires in (db, cache)", []string{"requires=db"}, true},
+		{"requires in (db, cache)", []string{"requires=network"}, false},
+		{"requires notin (network)", []string{"integration"}, true},
+		{"integration", []string{"integration"}, true},
+		{"!manual", []string{"manual"}, false},
+		{"speed!=slow, requires in (db)", []string{"speed=fast", "requires=db"}, true},
+		{"speed!=slow, requires in (db)", []string{"speed=slow", "requires=db"}, false},
+	}
+	for _, c := range cases {
+		sel, err := ParseSelector(c.selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sel.Matches(c.tags...) != c.matches {
+			t.Errorf("Expected '%s' matching %v to be %v", c.selector, c.tags, c.matches)
+		}
+	}
+
+	if _, err := ParseSelector("speed in db"); err == nil {
+		t.Error("Expected an error for a malformed requirement")
+	}
+}
+
+func TestSelect(t *testing.T) {
+	tc := New()
+	sel, _ := ParseSelector("speed!=slow")
+	tc.Select(sel)
+
+	mock := &mockT{}
+	tc.Test("speed=slow", mock, func(t T) {})
+	tc.Test("speed=fast", mock, func(t T) {})
+	tc.Test("integration", mock, func(t T) {})
+	if mock.skipped != 1 {
+		t.Error("Wrong number of tests skipped")
+	}
+}
@@ -0,0 +1,84 @@
+package gotag
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// shard is the part of the tagged tests a context runs
+type shard struct {
+	index, total int
+}
+
+func (s shard) String() string {
+	return fmt.Sprintf("%d/%d", s.index, s.total)
+}
+
+// Shard partitions tagged tests across total CI nodes, running only the
+// tests of the 1-based index so that a suite can be split across
+// machines by running it with every index from 1 to total. Tests are
+// assigned to shards by hashing their first tag and top level test name,
+// so a subtest runs on the shard of its parent and every node agrees on
+// the partition. Tests that are not in the shard are skipped. Panics if
+// index is not between 1 and total
+//
+//	tc.Shard(2, 5)
+func (tc *TestContext) Shard(index, total int) {
+	if total < 1 || index < 1 || index > total {
+		panic(fmt.Sprintf("gotag: invalid shard %d/%d", index, total))
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.shard = shard{index, total}
+}
+
+// Shard partitions tagged tests across total CI
+// nodes within the default context
+func Shard(index, total int) {
+	Default().Shard(index, total)
+}
+
+// parses a shard of the form index/total, e.g. 2/5
+func parseShard(s string) (shard, error) {
+	i := strings.IndexByte(s, '/')
+	if i < 0 {
+		return shard{}, fmt.Errorf("Invalid shard '%s', expected index/total", s)
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(s[:i]))
+	if err != nil {
+		return shard{}, fmt.Errorf("Invalid shard '%s': %v", s, err)
+	}
+	total, err := strconv.Atoi(strings.TrimSpace(s[i+1:]))
+	if err != nil {
+		return shard{}, fmt.Errorf("Invalid shard '%s': %v", s, err)
+	}
+	if total < 1 || index < 1 || index > total {
+		return shard{}, fmt.Errorf("Invalid shard '%s', index must be between 1 and total", s)
+	}
+	return shard{index, total}, nil
+}
+
+// reports whether the test with the given tags and name belongs to
+// the shard. Every test belongs to the shard of a single node
+func (s shard) has(tags []string, name string) bool {
+	if s.total <= 1 {
+		return true
+	}
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		name = name[:i]
+	}
+	var tag string
+	if len(tags) > 0 {
+		tag = tags[0]
+	}
+	// FNV-1a, computed inline so that the check doesn't allocate
+	h := uint32(2166136261)
+	for _, str := range []string{tag, "\x00", name} {
+		for i := 0; i < len(str); i++ {
+			h ^= uint32(str[i])
+			h *= 16777619
+		}
+	}
+	return int(h%uint32(s.total)) == s.index-1
+}
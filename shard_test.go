@@ -0,0 +1,52 @@
+package gotag
+
+import (
+	"fmt"
+	"testing"
+)
+
+type namedT struct {
+	mockT
+	name string
+}
+
+func (t *namedT) Name() string { return t.name }
+
+func TestShard(t *testing.T) {
+	const total = 3
+	runs := make(map[string]int)
+	for index := 1; index <= total; index++ {
+		tc := New()
+		tc.Shard(index, total)
+		for i := 0; i < 30; i++ {
+			name := fmt.Sprintf("Test%d", i)
+			tc.Test("integration", &namedT{name: name}, func(t T) { runs[name]++ })
+		}
+	}
+	if len(runs) != 30 {
+		t.Errorf("Expected every test to run on some shard, got %d", len(runs))
+	}
+	for name, n := range runs {
+		if n != 1 {
+			t.Errorf("Expected %s to run on a single shard, ran on %d", name, n)
+		}
+	}
+}
+
+func TestShardSubtests(t *testing.T) {
+	s := shard{2, 4}
+	if s.has([]string{"db"}, "TestQuery") != s.has([]string{"db", "case"}, "TestQuery/first") {
+		t.Error("Expected subtests to belong to the shard of their parent")
+	}
+}
+
+func TestParseShard(t *testing.T) {
+	if s, err := parseShard("2/5"); err != nil || s != (shard{2, 5}) {
+		t.Errorf("Expected shard 2/5, got %v, %v", s, err)
+	}
+	for _, bad := range []string{"2", "0/5", "6/5", "a/5", "1/0"} {
+		if _, err := parseShard(bad); err == nil {
+			t.Errorf("Expected an error for shard '%s'", bad)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package gotag
+
+import "flag"
+
+// reports whether tests run in short mode. Reads the -test.short flag
+// rather than calling testing.Short, which panics outside of tests.
+// A variable for tests
+var testingShort = func() bool {
+	f := flag.Lookup("test.short")
+	return f != nil && f.Value.String() == "true"
+}
+
+// SkipInShort marks tags whose tests are skipped when tests run in
+// short mode, with go test -short, whatever tags are run
+//
+//	tc.SkipInShort(gotag.Integration, gotag.EndToEnd)
+func (tc *TestContext) SkipInShort(tags ...string) {
+	tc.checkStarted("SkipInShort", tags)
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	for _, tag := range tags {
+		tc.addTag(tc.shortSkip, tag)
+	}
+	tc.invalidate()
+}
+
+// SkipInShort marks tags whose tests are skipped in
+// short mode within the default context
+func SkipInShort(tags ...string) {
+	Default().SkipInShort(tags...)
+}
+
+// returns the first of the tags skipped in short mode if tests run in
+// short mode. Must be called with at least a read lock held
+func (tc *TestContext) shortSkipped(tags []string) (string, bool) {
+	if tc.shortSkip.len() == 0 || !testingShort() {
+		return "", false
+	}
+	for _, tag := range tags {
+		if tc.shortSkip.covers(tc.canonical(tag)) {
+			return tag, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,44 @@
+package gotag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSkipInShort(t *testing.T) {
+	defer func(fn func() bool) { testingShort = fn }(testingShort)
+	short := false
+	testingShort = func() bool { return short }
+
+	tc := New()
+	tc.RunOnly("integration")
+	tc.SkipInShort("integration")
+	if skip, _ := tc.WouldSkip("integration"); skip {
+		t.Error("Expected integration tests to run outside of short mode")
+	}
+	short = true
+	if skip, reason := tc.WouldSkip("integration"); !skip || reason != "short mode" {
+		t.Errorf("Expected integration tests to be skipped in short mode whatever runs, got %v, %s", skip, reason)
+	}
+
+	mt := &messageT{}
+	tc.Test("integration", mt, func(t T) {})
+	if len(mt.messages) != 1 || !strings.Contains(mt.messages[0], "tag 'integration' is skipped in short mode") {
+		t.Errorf("Unexpected skip message %q", mt.messages)
+	}
+
+	tc = New()
+	if err := tc.Apply(&Config{ShortSkips: []string{"E2E"}}); err != nil {
+		t.Fatal(err)
+	}
+	tc.CaseInsensitive(true)
+	if skip, _ := tc.WouldSkip("e2e"); !skip {
+		t.Error("Expected short_skips to be skipped in short mode")
+	}
+}
+
+func TestTestingShort(t *testing.T) {
+	if testingShort() != testing.Short() {
+		t.Error("Expected short mode to follow the -test.short flag")
+	}
+}
@@ -0,0 +1,172 @@
+package gotag
+
+import (
+	"encoding/json"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// SkipEntry is an entry of the skip config option given as an object,
+// skipping the tag for the given reason, until a deadline if any
+//
+//	skip:
+//	  - {tag: integration, reason: "no staging db in PR builds"}
+//	  - {tag: flaky-s3, until: 2025-09-01, reason: "AWS outage"}
+type SkipEntry struct {
+	Tag    string `json:"tag" yaml:"tag"`
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// Until is a date, the tag being skipped through that day,
+	// or an RFC 3339 time. The tag is skipped for good if empty
+	Until string `json:"until,omitempty" yaml:"until,omitempty"`
+}
+
+// describes the deadline and reason of the skip
+func (s SkipEntry) String() string {
+	switch {
+	case s.Until == "":
+		return s.Reason
+	case s.Reason == "":
+		return fmt.Sprintf("until %s", s.Until)
+	}
+	return fmt.Sprintf("until %s, %s", s.Until, s.Reason)
+}
+
+// SkipWithReason marks the tag to be skipped like Skip, recording why.
+// The reason is included in the skip message and the reports so that
+// people know why their tests didn't run
+//
+//	tc.SkipWithReason(gotag.Integration, "no staging db in PR builds")
+func (tc *TestContext) SkipWithReason(tag, reason string) {
+	tc.Skip(tag)
+	tc.mu.Lock()
+	tc.skipEntries[tc.canonical(tag)] = SkipEntry{Tag: tag, Reason: reason}
+	tc.mu.Unlock()
+}
+
+// SkipWithReason marks the tag to be skipped
+// for the reason within the default context
+func SkipWithReason(tag, reason string) {
+	Default().SkipWithReason(tag, reason)
+}
+
+// describes the reason for reports, along with why the tag is skipped
+// if it was given. Must be called with at least a read lock held
+func (tc *TestContext) describeReason(tag string, reason skipReason) string {
+	if reason == foundInSkip {
+		if skip, ok := tc.skipEntryFor(tag); ok && skip.Reason != "" {
+			return reason.String() + ": " + skip.Reason
+		}
+	}
+	return reason.String()
+}
+
+// returns the skip entry of the skipped tag, namespace or pattern
+// covering the tag, looking it up in the context and its ancestors.
+// Must be called with at least a read lock held
+func (tc *TestContext) skipEntryFor(tag string) (SkipEntry, bool) {
+	key := tc.canonical(tag)
+	if match, ok := tc.skip.covering(key); ok {
+		skip, ok := tc.skipEntries[match]
+		return skip, ok
+	}
+	for p := tc.parent; p != nil; p = p.parent {
+		p.mu.RLock()
+		match, covered := p.skip.covering(key)
+		skip, ok := p.skipEntries[match]
+		p.mu.RUnlock()
+		if covered {
+			return skip, ok
+		}
+	}
+	return SkipEntry{}, false
+}
+
+// skipListItem is an entry of the skip config option,
+// either a tag or a skip entry
+type skipListItem struct {
+	tag   string
+	entry *SkipEntry
+}
+
+func (e *skipListItem) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &e.tag); err == nil {
+		return nil
+	}
+	e.entry = &SkipEntry{}
+	return json.Unmarshal(data, e.entry)
+}
+
+func (e *skipListItem) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&e.tag); err == nil {
+		return nil
+	}
+	e.entry = &SkipEntry{}
+	return unmarshal(e.entry)
+}
+
+// adds the items of a skip list to the config
+func (c *Config) addSkipItems(items []skipListItem) {
+	for _, item := range items {
+		if item.entry != nil {
+			c.SkipEntries = append(c.SkipEntries, *item.entry)
+		} else {
+			c.Skip = append(c.Skip, item.tag)
+		}
+	}
+}
+
+// plainConfig decodes a config without the custom decoding of its
+// skip list
+type plainConfig Config
+
+// UnmarshalJSON decodes a config whose skip list may hold skip entries
+func (c *Config) UnmarshalJSON(data []byte) error {
+	raw := struct {
+		*plainConfig
+		Skip []skipListItem `json:"skip"`
+	}{plainConfig: (*plainConfig)(c)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c.addSkipItems(raw.Skip)
+	return nil
+}
+
+// UnmarshalYAML decodes a config whose skip list may hold skip entries
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal((*plainConfig)(c)); err == nil {
+		return nil
+	}
+	// the skip list holds skip entries, so it is
+	// decoded separately from the other options
+	*c = Config{}
+	var fields yaml.MapSlice
+	if err := unmarshal(&fields); err != nil {
+		return err
+	}
+	var items []skipListItem
+	rest := fields[:0]
+	for _, field := range fields {
+		if field.Key != "skip" {
+			rest = append(rest, field)
+			continue
+		}
+		bytes, err := yaml.Marshal(field.Value)
+		if err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(bytes, &items); err != nil {
+			return err
+		}
+	}
+	bytes, err := yaml.Marshal(rest)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(bytes, (*plainConfig)(c)); err != nil {
+		return err
+	}
+	c.addSkipItems(items)
+	return nil
+}
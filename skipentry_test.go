@@ -0,0 +1,81 @@
+package gotag
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSkipWithReason(t *testing.T) {
+	tc := New()
+	tc.SkipWithReason("integration", "no staging db in PR builds")
+
+	s := &messageT{}
+	tc.Test("integration", s, func(T) {})
+	want := "tag 'integration' is in skip list: no staging db in PR builds"
+	if len(s.messages) != 1 || !strings.Contains(s.messages[0], want) {
+		t.Errorf("Expected the reason in the skip message, got %v", s.messages)
+	}
+
+	tc.recording = true
+	tc.Test("integration", &mockT{}, func(T) {})
+	tc.recording = false
+	var b bytes.Buffer
+	tc.LogDecisions(&b)
+	tc.Test("integration", &mockT{}, func(T) {})
+	reasons := tc.Report().Tags[0].Reasons
+	if reasons["in skip list: no staging db in PR builds"] != 1 {
+		t.Errorf("Expected the reason in the report, got %v", reasons)
+	}
+	var e Decision
+	if err := json.Unmarshal(b.Bytes(), &e); err != nil || e.Reason != "in skip list: no staging db in PR builds" {
+		t.Errorf("Expected the reason in the decision log, got %+v, %v", e, err)
+	}
+}
+
+func TestSkipWithReasonCovering(t *testing.T) {
+	tc := New()
+	tc.SkipWithReason("db", "no db in PR builds")
+	tc.SkipWithReason("s3-*", "AWS outage")
+	tc.SkipWithReason("/^gpu[0-9]$/", "no gpus")
+	tc.SkipWithReason("db.postgres.slow", "too slow")
+	child := tc.Child()
+
+	for tag, want := range map[string]string{
+		"db.postgres":      "tag 'db.postgres' is in skip list: no db in PR builds",
+		"db.postgres.slow": "tag 'db.postgres.slow' is in skip list: too slow",
+		"s3-eu":            "tag 's3-eu' is in skip list: AWS outage",
+		"gpu1":             "tag 'gpu1' is in skip list: no gpus",
+	} {
+		s := &messageT{}
+		child.Test(tag, s, func(T) {})
+		if len(s.messages) != 1 || !strings.Contains(s.messages[0], want) {
+			t.Errorf("Expected %q in the skip message, got %v", want, s.messages)
+		}
+	}
+	tc.mu.RLock()
+	why := tc.describeReason("s3-us", foundInSkip)
+	tc.mu.RUnlock()
+	if why != "in skip list: AWS outage" {
+		t.Errorf("Expected the reason of the pattern in the report, got %s", why)
+	}
+}
+
+func TestSkipEntryConfig(t *testing.T) {
+	data := `{"skip": ["slow", {"tag": "integration", "reason": "no staging db"}]}`
+	config, err := decodeJSONConfig(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc := New()
+	if err := tc.Apply(config); err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "integration,slow" {
+		t.Errorf("Expected both tags to be skipped, got %s", tags)
+	}
+	if why := tc.describeReason("integration", foundInSkip); why != "in skip list: no staging db" {
+		t.Errorf("Unexpected reason %s", why)
+	}
+}
@@ -0,0 +1,37 @@
+package gotag
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SkipFile is the name of the plain text file listing tags to skip,
+// one tag, wildcard pattern or /regular expression/ per line, loaded
+// alongside the config file of the same directory. Blank lines and
+// lines starting with # are ignored, so scripts can append to it
+// without parsing a config format
+const SkipFile = ".gotagskip"
+
+// attempts to read a skip file
+func loadSkipFile(r io.Reader) (*Config, error) {
+	var config Config
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		tag := strings.TrimSpace(scanner.Text())
+		if tag == "" || strings.HasPrefix(tag, "#") {
+			continue
+		}
+		if err := checkPatterns(tag); err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", SkipFile, line, err)
+		}
+		config.Skip = append(config.Skip, tag)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	in := make(interner)
+	config.Skip = in.unique(config.Skip)
+	return &config, nil
+}
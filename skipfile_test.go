@@ -0,0 +1,49 @@
+package gotag
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSkipFile(t *testing.T) {
+	dir := t.TempDir()
+	skips := "# quarantined by CI\nflaky-payments\n\n  db-*  \n/^e2e-.+$/\nflaky-payments\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, SkipFile), []byte(skips), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tc, err := LoadFrom(dir)
+	if err != nil {
+		t.Fatalf("Expected a skip file alone to load: %v", err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "/^e2e-.+$/,db-*,flaky-payments" {
+		t.Errorf("Unexpected skipped tags %s", tags)
+	}
+	for _, tag := range []string{"flaky-payments", "db-write", "e2e-checkout"} {
+		if skipped, _ := tc.WouldSkip(tag); !skipped {
+			t.Errorf("Expected tag %s to be skipped", tag)
+		}
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, ".gotag.yml"), []byte("skip: [slow]\nfuzzy: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if tc, err = LoadFrom(dir); err != nil {
+		t.Fatal(err)
+	}
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "/^e2e-.+$/,db-*,flaky-payments,slow" || !tc.Fuzzy {
+		t.Errorf("Expected the skip file to add to the config, got %s", tags)
+	}
+}
+
+func TestSkipFileInvalid(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, SkipFile), []byte("slow\n/(unclosed/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadFrom(dir)
+	if err == nil || !strings.HasPrefix(err.Error(), ".gotagskip line 2:") {
+		t.Errorf("Expected the invalid line to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package gotag
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Condition is a fact about the host tests run on, see SkipIf
+type Condition struct {
+	desc  string
+	holds bool
+}
+
+// Holds reports whether the condition holds on this host
+func (c Condition) Holds() bool {
+	return c.holds
+}
+
+func (c Condition) String() string {
+	return c.desc
+}
+
+// GOOS returns a condition that holds if the tests
+// run on one of the given operating systems
+func GOOS(goos ...string) Condition {
+	return Condition{"goos=" + strings.Join(goos, ","), containsString(goos, runtime.GOOS)}
+}
+
+// GOARCH returns a condition that holds if the tests
+// run on one of the given architectures
+func GOARCH(goarch ...string) Condition {
+	return Condition{"goarch=" + strings.Join(goarch, ","), containsString(goarch, runtime.GOARCH)}
+}
+
+// Race returns a condition that holds if whether the
+// race detector is enabled matches enabled
+func Race(enabled bool) Condition {
+	return Condition{"race=" + strconv.FormatBool(enabled), raceEnabled == enabled}
+}
+
+// CGO returns a condition that holds if whether
+// cgo is enabled matches enabled
+func CGO(enabled bool) Condition {
+	return Condition{"cgo=" + strconv.FormatBool(enabled), cgoEnabled == enabled}
+}
+
+// the version of Go the tests were built with, a variable for tests
+var goVersion = runtime.Version()
+
+// GoVersionBelow returns a condition that holds if the tests were built
+// with a release of Go older than version, e.g. 1.21. Development
+// versions of Go are considered newer than every release. Panics if
+// version is malformed
+func GoVersionBelow(version string) Condition {
+	want, err := parseGoVersion(version)
+	if err != nil {
+		panic(fmt.Sprintf("gotag: %v", err))
+	}
+	c := Condition{desc: "go<" + version}
+	if have, err := parseGoVersion(goVersion); err == nil {
+		for i := range want {
+			if have[i] != want[i] {
+				c.holds = have[i] < want[i]
+				break
+			}
+		}
+	}
+	return c
+}
+
+// parses a version such as 1.21, go1.21 or go1.21.3
+func parseGoVersion(version string) ([3]int, error) {
+	var v [3]int
+	parts := strings.Split(strings.TrimPrefix(version, "go"), ".")
+	if len(parts) > len(v) {
+		return v, fmt.Errorf("Invalid Go version '%s'", version)
+	}
+	for i, part := range parts {
+		// prereleases such as 1.22rc1 count as the release
+		if j := strings.IndexAny(part, "abcdefghijklmnopqrstuvwxyz"); j > 0 && i == len(parts)-1 {
+			part = part[:j]
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("Invalid Go version '%s'", version)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipIf skips tests under the tag, or a tag in its namespace, if every
+// condition holds on this host, whatever tags are run, so that platform
+// specific tests are skipped on unsupported hosts
+//
+//	tc.SkipIf("docker", gotag.GOOS("windows"))
+//	tc.SkipIf("cgo", gotag.Race(true))
+func (tc *TestContext) SkipIf(tag string, conds ...Condition) {
+	tc.checkStarted("SkipIf", []string{tag})
+	desc := make([]string, len(conds))
+	for i, c := range conds {
+		if !c.holds {
+			return
+		}
+		desc[i] = c.desc
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.hostSkips[tc.canonical(tag)] = hostSkip{tag: tag, conditions: strings.Join(desc, ", ")}
+	tc.invalidate()
+}
+
+// hostSkip is a tag skipped on this host and the conditions that hold
+type hostSkip struct {
+	tag, conditions string
+}
+
+// SkipIf skips tests under the tag if every condition
+// holds on this host within the default context
+func SkipIf(tag string, conds ...Condition) {
+	Default().SkipIf(tag, conds...)
+}
+
+// SkipRule is a rule of the skip_if config option, skipping tests
+// under the tag on hosts matching every condition that is set
+type SkipRule struct {
+	Tag    string `json:"tag" yaml:"tag"`
+	GOOS   string `json:"goos" yaml:"goos"`
+	GOARCH string `json:"goarch" yaml:"goarch"`
+	// GoBelow is a Go version, see GoVersionBelow
+	GoBelow string `json:"go_below" yaml:"go_below"`
+	Race    *bool  `json:"race" yaml:"race"`
+	CGO     *bool  `json:"cgo" yaml:"cgo"`
+}
+
+// returns the conditions of the rule. Returns an error
+// if the rule is malformed or sets no conditions
+func (r SkipRule) conditions() ([]Condition, error) {
+	if r.Tag == "" {
+		return nil, fmt.Errorf("Invalid skip_if rule without a tag")
+	}
+	var conds []Condition
+	if r.GOOS != "" {
+		conds = append(conds, GOOS(splitTags(r.GOOS)...))
+	}
+	if r.GOARCH != "" {
+		conds = append(conds, GOARCH(splitTags(r.GOARCH)...))
+	}
+	if r.GoBelow != "" {
+		if _, err := parseGoVersion(r.GoBelow); err != nil {
+			return nil, err
+		}
+		conds = append(conds, GoVersionBelow(r.GoBelow))
+	}
+	if r.Race != nil {
+		conds = append(conds, Race(*r.Race))
+	}
+	if r.CGO != nil {
+		conds = append(conds, CGO(*r.CGO))
+	}
+	if len(conds) == 0 {
+		return nil, fmt.Errorf("Invalid skip_if rule for tag '%s' without conditions", r.Tag)
+	}
+	return conds, nil
+}
+
+// returns the skip of the first of the tags, or their namespaces, skipped
+// on this host. Must be called with at least a read lock held
+func (tc *TestContext) hostSkipped(tags []string) (hostSkip, bool) {
+	if len(tc.hostSkips) == 0 {
+		return hostSkip{}, false
+	}
+	for _, tag := range tags {
+		key := tc.canonical(tag)
+		for i := 0; i <= len(key); i++ {
+			if i < len(key) && key[i] != '.' {
+				continue
+			}
+			if skip, ok := tc.hostSkips[key[:i]]; ok {
+				return skip, true
+			}
+		}
+	}
+	return hostSkip{}, false
+}
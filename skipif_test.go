@@ -0,0 +1,73 @@
+package gotag
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSkipIf(t *testing.T) {
+	tc := New()
+	tc.RunOnly("docker", "cgo")
+	tc.SkipIf("docker", GOOS(runtime.GOOS), GOARCH(runtime.GOARCH))
+	tc.SkipIf("cgo", GOOS("plan9"))
+
+	if skip, reason := tc.WouldSkip("docker.compose"); !skip || reason != "unsupported host" {
+		t.Errorf("Expected docker tests to be skipped on this host whatever runs, got %v, %s", skip, reason)
+	}
+	if skip, _ := tc.WouldSkip("cgo"); skip {
+		t.Error("Expected cgo tests to run when a condition doesn't hold")
+	}
+
+	mt := &messageT{}
+	tc.Test("docker", mt, func(t T) {})
+	want := "tag 'docker' is skipped on hosts where goos=" + runtime.GOOS + ", goarch=" + runtime.GOARCH
+	if len(mt.messages) != 1 || !strings.Contains(mt.messages[0], want) {
+		t.Errorf("Expected %q in the skip message, got %q", want, mt.messages)
+	}
+}
+
+func TestConditions(t *testing.T) {
+	if !Race(raceEnabled).Holds() || Race(!raceEnabled).Holds() {
+		t.Error("Expected Race to match the race detector")
+	}
+	if !CGO(cgoEnabled).Holds() {
+		t.Error("Expected CGO to match cgo")
+	}
+
+	defer func(v string) { goVersion = v }(goVersion)
+	goVersion = "go1.21.3"
+	for version, below := range map[string]bool{"1.22": true, "go1.21.4": true, "1.21": false, "1.20": false} {
+		if c := GoVersionBelow(version); c.Holds() != below {
+			t.Errorf("Expected %s to hold: %v", c, below)
+		}
+	}
+	goVersion = "devel go1.23-abc"
+	if GoVersionBelow("99.0").Holds() {
+		t.Error("Expected development versions to be newer than every release")
+	}
+}
+
+func TestApplySkipIf(t *testing.T) {
+	enabled := raceEnabled
+	tc := New()
+	err := tc.Apply(&Config{SkipIf: []SkipRule{
+		{Tag: "docker", GOOS: "plan9, " + runtime.GOOS},
+		{Tag: "cgo", Race: &enabled},
+		{Tag: "windows", GOOS: "none"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for tag, want := range map[string]bool{"docker": true, "cgo": true, "windows": false} {
+		if skip, _ := tc.WouldSkip(tag); skip != want {
+			t.Errorf("Expected %s to be skipped: %v", tag, want)
+		}
+	}
+
+	for _, rule := range []SkipRule{{Tag: "docker"}, {GOOS: "linux"}, {Tag: "old", GoBelow: "one"}} {
+		if err := New().Apply(&Config{SkipIf: []SkipRule{rule}}); err == nil {
+			t.Errorf("Expected an error for rule %+v", rule)
+		}
+	}
+}
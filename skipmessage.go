@@ -0,0 +1,117 @@
+package gotag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultSkipMessage prefixes why a test was skipped with
+// "skipped by gotag: ", e.g. "skipped by gotag: tag 'integration'
+// is in skip list"
+func DefaultSkipMessage(tag, why string) string {
+	return "skipped by gotag: " + why
+}
+
+// identifies a skip message
+type skipKey struct {
+	tag, match, selector, shard string
+	// the conditions of a tag skipped on this host
+	host string
+	// the deadline and reason of a tag skipped by
+	// SkipWithReason or SkipUntil
+	until, why string
+	reason     skipReason
+	distance   int
+	// whether tag lists the tags of a test with several tags
+	several bool
+}
+
+// returns the arguments to skip a test with. Arguments are cached per
+// tag and reason so that skipping a test doesn't allocate once the
+// message has been formatted
+func (tc *TestContext) skipMessage(tags []string, tag, match string, reason skipReason, distance int) []interface{} {
+	key := skipKey{tag: tag, match: match, reason: reason, distance: distance}
+	if tag == "" {
+		if len(tags) == 1 {
+			key.tag = tags[0]
+		} else {
+			key.tag, key.several = strings.Join(tags, ", "), true
+		}
+	}
+	tc.mu.RLock()
+	if reason == notSelected && tc.selector != nil {
+		key.selector = tc.selector.String()
+	}
+	if reason == notInShard {
+		key.shard = tc.shard.String()
+	}
+	if reason == foundInSkip {
+		if skip, ok := tc.skipEntryFor(key.tag); ok {
+			key.until, key.why = skip.Until, skip.Reason
+		}
+	}
+	if reason == unsupportedHost {
+		key.host = tc.hostSkips[tc.canonical(tag)].conditions
+	}
+	args, ok := tc.messages[key]
+	format := tc.SkipMessage
+	tc.mu.RUnlock()
+	if ok {
+		return args
+	}
+
+	if format == nil {
+		format = DefaultSkipMessage
+	}
+	args = []interface{}{format(key.tag, explain(key))}
+	tc.mu.Lock()
+	tc.messages[key] = args
+	tc.mu.Unlock()
+	return args
+}
+
+// describes why a test was skipped
+func explain(key skipKey) string {
+	switch key.reason {
+	case foundInSkip:
+		switch {
+		case key.until != "" && key.why != "":
+			return fmt.Sprintf("tag '%s' is skipped until %s: %s", key.tag, key.until, key.why)
+		case key.until != "":
+			return fmt.Sprintf("tag '%s' is skipped until %s", key.tag, key.until)
+		case key.why != "":
+			return fmt.Sprintf("tag '%s' is in skip list: %s", key.tag, key.why)
+		}
+		return fmt.Sprintf("tag '%s' is in skip list", key.tag)
+	case fuzzyMatchSkip:
+		return fmt.Sprintf("tag '%s' is within an edit distance of %d of skipped tag '%s'",
+			key.tag, key.distance, key.match)
+	case notInRunOnly:
+		if key.several {
+			return fmt.Sprintf("none of tags '%s' are in run list", key.tag)
+		}
+		return fmt.Sprintf("tag '%s' is not in run list", key.tag)
+	case notSelected:
+		if key.several {
+			return fmt.Sprintf("tags '%s' do not match selector '%s'", key.tag, key.selector)
+		}
+		return fmt.Sprintf("tag '%s' does not match selector '%s'", key.tag, key.selector)
+	case requirementUnmet:
+		return fmt.Sprintf("requirement '%s' is not met", key.tag)
+	case notEnabled:
+		if key.several {
+			return fmt.Sprintf("none of tags '%s' are enabled and tagged tests are skipped by default", key.tag)
+		}
+		return fmt.Sprintf("tag '%s' is not enabled and tagged tests are skipped by default", key.tag)
+	case notInShard:
+		return fmt.Sprintf("test is not in shard %s", key.shard)
+	case unsupportedHost:
+		return fmt.Sprintf("tag '%s' is skipped on hosts where %s", key.tag, key.host)
+	case shortMode:
+		return fmt.Sprintf("tag '%s' is skipped in short mode", key.tag)
+	case requiredSkipped:
+		return fmt.Sprintf("tag '%s' requires skipped tag '%s'", key.tag, key.match)
+	default:
+		return key.reason.String()
+	}
+}
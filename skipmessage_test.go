@@ -0,0 +1,47 @@
+package gotag
+
+import (
+	"fmt"
+	"testing"
+)
+
+// messageT records the message of every skip
+type messageT struct {
+	mockT
+	messages []string
+}
+
+func (t *messageT) Skip(args ...interface{}) {
+	t.messages = append(t.messages, fmt.Sprint(args...))
+}
+
+func TestSkipMessage(t *testing.T) {
+	tc := New()
+	tc.Skip("integration")
+	tc.Fuzzy = true
+
+	mock := &messageT{}
+	tc.Test("integration", mock, func(t T) {})
+	tc.Test("integratoin", mock, func(t T) {})
+	tc.Require("docker", func() bool { return false })
+	tc.Test("docker", mock, func(t T) {})
+	want := []string{
+		"skipped by gotag: tag 'integration' is in skip list",
+		"skipped by gotag: tag 'integratoin' is within an edit distance of 2 of skipped tag 'integration'",
+		"skipped by gotag: requirement 'docker' is not met",
+	}
+	if fmt.Sprint(mock.messages) != fmt.Sprint(want) {
+		t.Errorf("Expected messages %q, got %q", want, mock.messages)
+	}
+
+	tc = New()
+	tc.RunOnly("unit")
+	tc.SkipMessage = func(tag, why string) string {
+		return "[" + tag + "] " + why
+	}
+	mock = &messageT{}
+	tc.TestTags([]string{"integration", "db"}, mock, func(t T) {})
+	if len(mock.messages) != 1 || mock.messages[0] != "[integration, db] none of tags 'integration, db' are in run list" {
+		t.Errorf("Unexpected messages %q", mock.messages)
+	}
+}
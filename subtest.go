@@ -0,0 +1,48 @@
+package gotag
+
+import "testing"
+
+// Run runs fn as a subtest of t named name, under the given tag in
+// addition to the tags of t, if t is a test executed by the TestContext
+// instance. This allows the cases of a table driven test to be tagged
+// and skipped independently. Returns whether the subtest succeeded
+func (tc *TestContext) Run(tag string, t T, name string, fn func(t T)) bool {
+	var tags []string
+	if n, ok := t.(interface{ Name() string }); ok {
+		tc.mu.RLock()
+		tags = append(tags, tc.active[n.Name()]...)
+		tc.mu.RUnlock()
+	}
+	tags = append(tags, tag)
+	return t.Run(name, func(st *testing.T) {
+		tc.run(tags, st, fn)
+	})
+}
+
+// Run runs fn as a subtest of t under the given tag in addition
+// to the tags of t within the default context
+func Run(tag string, t T, name string, fn func(t T)) bool {
+	return Default().Run(tag, t, name, fn)
+}
+
+// BenchmarkRun runs fn as a sub-benchmark of b named name, under the
+// given tag in addition to the tags of b, with the semantics of
+// testing.B.Run. Returns whether the sub-benchmark succeeded
+func (tc *TestContext) BenchmarkRun(tag string, b B, name string, fn func(b B)) bool {
+	var tags []string
+	if n, ok := b.(interface{ Name() string }); ok {
+		tc.mu.RLock()
+		tags = append(tags, tc.active[n.Name()]...)
+		tc.mu.RUnlock()
+	}
+	tags = append(tags, tag)
+	return b.Run(name, func(sb *testing.B) {
+		tc.run(tags, sb, fn)
+	})
+}
+
+// BenchmarkRun runs fn as a sub-benchmark of b under the given tag
+// in addition to the tags of b within the default context
+func BenchmarkRun(tag string, b B, name string, fn func(b B)) bool {
+	return Default().BenchmarkRun(tag, b, name, fn)
+}
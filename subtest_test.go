@@ -0,0 +1,48 @@
+package gotag
+
+import "testing"
+
+func TestRunSubtests(t *testing.T) {
+	tc := New()
+	tc.Skip("integration")
+
+	ran := 0
+	tc.Test("unit", t, func(t T) {
+		tc.Run("integration", t, "integration", func(t T) {
+			t.Error("Expected the integration case to be skipped")
+		})
+		tc.Run("fast", t, "fast", func(t T) { ran++ })
+	})
+	if ran != 1 {
+		t.Errorf("Expected 1 subtest to run, got %d", ran)
+	}
+
+	// subtests inherit the tags of their parent
+	tc = New()
+	tc.RunOnly("unit")
+	tc.Test("unit", t, func(t T) {
+		tc.Run("integration", t, "inherited", func(t T) { ran++ })
+	})
+	if ran != 2 {
+		t.Errorf("Expected the subtest to inherit the parent tag, got %d runs", ran)
+	}
+}
+
+func TestBenchmarkRun(t *testing.T) {
+	tc := New()
+	tc.Skip("integration")
+
+	var ran, skipped int
+	testing.Benchmark(func(b *testing.B) {
+		tc.Benchmark("unit", b, func(b B) {
+			tc.BenchmarkRun("integration", b, "integration", func(b B) { skipped++ })
+			tc.BenchmarkRun("fast", b, "fast", func(b B) { ran++ })
+		})
+	})
+	if skipped != 0 {
+		t.Errorf("Expected the integration sub-benchmark to be skipped, ran %d times", skipped)
+	}
+	if ran == 0 {
+		t.Error("Expected the fast sub-benchmark to run")
+	}
+}
@@ -0,0 +1,198 @@
+package gotag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maximum number of known tags suggested for an unknown tag
+const maxSuggestions = 3
+
+// SuggestTags sets whether tests warn about tags that no skip, run or
+// other list of the context, nor RegisterTag, mentions but that are
+// within EditDistance of a tag that is, as typos would be. Tags are
+// only checked while Fuzzy is false, since fuzzy matching already
+// catches typos
+//
+//	--- SKIP: TestDB
+//	    gotag: WARNING: unknown tag 'integratoin', did you mean 'integration'?
+func (tc *TestContext) SuggestTags(enabled bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.suggestTags = enabled
+}
+
+// SuggestTags sets whether tests warn about
+// mistyped tags within the default context
+func SuggestTags(enabled bool) {
+	Default().SuggestTags(enabled)
+}
+
+// StrictTags sets whether tests under a mistyped tag, as reported by
+// SuggestTags, fail without running instead of only warning
+func (tc *TestContext) StrictTags(strict bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.strictTags = strict
+}
+
+// StrictTags sets whether tests under a mistyped tag
+// fail without running within the default context
+func StrictTags(strict bool) {
+	Default().StrictTags(strict)
+}
+
+// returns the first of the tags unknown to the context along with the
+// known tags closest to it, or an empty tag if every tag is either
+// known or too far from any known tag to be a typo. Must be called
+// with at least a read lock held
+func (tc *TestContext) unknownTag(tags []string) (string, []string) {
+	if !tc.suggestTags && !tc.strictTags || tc.Fuzzy {
+		return "", nil
+	}
+	for _, tag := range tags {
+		key := tc.canonical(tag)
+		if tc.knownTag(key) {
+			continue
+		}
+		if suggestions := tc.suggestions(key); len(suggestions) > 0 {
+			return tag, suggestions
+		}
+	}
+	return "", nil
+}
+
+// reports whether any list of the context mentions the canonical
+// tag. Must be called with at least a read lock held
+func (tc *TestContext) knownTag(key string) bool {
+	if tc.inheritedCovers(skipSet, key) || tc.inheritedCovers(runOnlySet, key) {
+		return true
+	}
+	for _, set := range []*tagSet{tc.mustRun, tc.shortSkip, tc.quarantine} {
+		if set.covers(key) {
+			return true
+		}
+	}
+	if _, ok := tc.groups[key]; ok {
+		return true
+	}
+	if _, ok := tc.hostSkips[key]; ok {
+		return true
+	}
+	if _, ok := tc.exclusions[key]; ok {
+		return true
+	}
+	if _, ok := tc.dependencies[key]; ok {
+		return true
+	}
+	if _, ok := tc.timeouts[key]; ok {
+		return true
+	}
+	if _, ok := tc.retries[key]; ok {
+		return true
+	}
+	if _, ok := tc.priorities[key]; ok {
+		return true
+	}
+	if _, ok := tc.hooks[key]; ok {
+		return true
+	}
+	if _, ok := tc.prerequisites[key]; ok {
+		return true
+	}
+	return tc.registeredTag(key)
+}
+
+// returns the known tags within the edit distance of the canonical tag,
+// closest first. Must be called with at least a read lock held
+func (tc *TestContext) suggestions(key string) []string {
+	known := make(map[string]string)
+	add := func(tag string) {
+		known[tc.canonical(tag)] = tag
+	}
+	for _, kind := range []setKind{skipSet, runOnlySet} {
+		for _, tag := range tc.inheritedTags(kind) {
+			add(tag)
+		}
+	}
+	for _, set := range []*tagSet{tc.mustRun, tc.shortSkip, tc.quarantine} {
+		for _, tag := range set.tags(true) {
+			add(tag)
+		}
+	}
+	for name, members := range tc.groups {
+		add(name)
+		for _, tag := range members {
+			add(tag)
+		}
+	}
+	for _, skip := range tc.hostSkips {
+		add(skip.tag)
+	}
+	for tag, required := range tc.dependencies {
+		add(tag)
+		for _, tag := range required {
+			add(tag)
+		}
+	}
+	for _, locks := range tc.exclusions {
+		for _, l := range locks {
+			for _, tag := range l.tags {
+				add(tag)
+			}
+		}
+	}
+	for tag := range tc.priorities {
+		add(tag)
+	}
+	for tag := range tc.timeouts {
+		add(tag)
+	}
+	for tag := range tc.retries {
+		add(tag)
+	}
+	for _, h := range tc.hooks {
+		add(h.tag)
+	}
+	for _, req := range tc.prerequisites {
+		add(req.name)
+	}
+	for _, tag := range tc.tagRegistry {
+		add(tag.Name)
+	}
+
+	type candidate struct {
+		tag      string
+		distance int
+	}
+	var candidates []candidate
+	for k, tag := range known {
+		if d := tc.distance(k, key); d <= tc.EditDistance {
+			candidates = append(candidates, candidate{tag, d})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].tag < candidates[j].tag
+	})
+	if len(candidates) > maxSuggestions {
+		candidates = candidates[:maxSuggestions]
+	}
+	suggestions := make([]string, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = c.tag
+	}
+	return suggestions
+}
+
+// formats the message reporting an unknown tag
+func unknownTagMessage(tag string, suggestions []string) string {
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = "'" + s + "'"
+	}
+	return fmt.Sprintf("unknown tag '%s', did you mean %s?", tag, strings.Join(quoted, " or "))
+}
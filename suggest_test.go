@@ -0,0 +1,94 @@
+package gotag
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type failingT struct {
+	mockT
+	fatal  string
+	logged []string
+}
+
+func (t *failingT) Fatalf(format string, args ...interface{}) {
+	t.fatal = fmt.Sprintf(format, args...)
+}
+
+func (t *failingT) Logf(format string, args ...interface{}) {
+	t.logged = append(t.logged, fmt.Sprintf(format, args...))
+}
+
+func TestSuggestTags(t *testing.T) {
+	tc := New()
+	tc.RunOnly("integration")
+	tc.Timeout("database", time.Minute)
+	tc.SuggestTags(true)
+
+	mock := &failingT{}
+	ran := 0
+	tc.Test("integratoin", mock, func(t T) { ran++ })
+	if len(mock.logged) != 1 || !strings.Contains(mock.logged[0], "unknown tag 'integratoin', did you mean 'integration'?") {
+		t.Errorf("Expected a suggestion, got %v", mock.logged)
+	}
+	tc.Test("databse", mock, func(t T) { ran++ })
+	tc.Test("unit", mock, func(t T) { ran++ })
+	tc.Test("integration", mock, func(t T) { ran++ })
+	if len(mock.logged) != 2 || !strings.Contains(mock.logged[1], "'database'") {
+		t.Errorf("Expected only the mistyped tags to be reported, got %v", mock.logged)
+	}
+	if ran != 1 || mock.fatal != "" {
+		t.Errorf("Expected warnings not to change the outcome, %d tests ran", ran)
+	}
+
+	tc.Fuzzy = true
+	tc.Test("integratoin", mock, func(t T) { ran++ })
+	if len(mock.logged) != 2 {
+		t.Error("Expected no suggestions while fuzzy matching")
+	}
+}
+
+func TestStrictTags(t *testing.T) {
+	tc := New(WithStrictTags())
+	tc.Skip("slow", "flaky")
+
+	mock := &failingT{}
+	ran := false
+	tc.Test("slwo", mock, func(t T) { ran = true })
+	if ran || mock.fatal != "gotag: unknown tag 'slwo', did you mean 'slow'?" {
+		t.Errorf("Expected the test to fail without running, got %q", mock.fatal)
+	}
+	if len(tc.Clone().suggestions("slwo")) != 1 {
+		t.Error("Expected a single suggestion")
+	}
+
+	tc.DryRun = true
+	mock = &failingT{}
+	tc.Test("slwo", mock, func(t T) {})
+	if mock.fatal != "" {
+		t.Error("Expected dry runs to only warn")
+	}
+}
+
+func TestUnknownTagMessage(t *testing.T) {
+	msg := unknownTagMessage("tagC", []string{"tagA", "tagB"})
+	if msg != "unknown tag 'tagC', did you mean 'tagA' or 'tagB'?" {
+		t.Errorf("Unexpected message %s", msg)
+	}
+}
+
+func TestApplyStrictTags(t *testing.T) {
+	tc := New()
+	if err := tc.Apply(&Config{Skip: []string{"slow"}, SuggestTags: true, StrictTags: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !tc.suggestTags || !tc.strictTags {
+		t.Error("Expected the config to enable suggestions")
+	}
+	merged := MergeConfigs(&Config{StrictTags: true}, &Config{})
+	if !merged.StrictTags {
+		t.Error("Expected strict tags to be kept when merging")
+	}
+}
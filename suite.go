@@ -0,0 +1,190 @@
+package gotag
+
+import (
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// EnvSeed is the environment variable holding the seed
+// that suites shuffle tests with, see Suite.Shuffle
+const EnvSeed = "GOTAG_SEED"
+
+// Priority sets the priority of tests under the tag within a Suite.
+// Tests of higher priority run first and tests without a prioritized
+// tag have a priority of 0, so fast tags can be given a positive
+// priority and slow ones a negative priority to surface failures
+// early. A test with several prioritized tags gets the highest
+//
+//	tc.Priority("unit", 10)
+//	tc.Priority(gotag.EndToEnd, -10)
+func (tc *TestContext) Priority(tag string, p int) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.priorities[tc.canonical(tag)] = p
+}
+
+// Priority sets the priority of tests under
+// the tag within the default context
+func Priority(tag string, p int) {
+	Default().Priority(tag, p)
+}
+
+// returns the highest priority of the tags, or 0 if none of
+// them is prioritized. Must be called with at least a read lock held
+func (tc *TestContext) priorityOf(tags []string) int {
+	p, ok := 0, false
+	for _, tag := range tags {
+		if tp, set := tc.priorities[tc.canonical(tag)]; set && (!ok || tp > p) {
+			p, ok = tp, true
+		}
+	}
+	return p
+}
+
+// Suite collects tagged tests to be run as subtests of a test in
+// priority order, see Priority
+//
+//	func TestCheckout(t *testing.T) {
+//		s := tc.Suite(t)
+//		s.Add(gotag.EndToEnd, "browser", testBrowser)
+//		s.Add("unit", "totals", testTotals)
+//		s.Run() // runs totals first
+//	}
+type Suite struct {
+	tc    *TestContext
+	t     T
+	tests []suiteTest
+
+	shuffle  bool
+	shuffled []string
+}
+
+type suiteTest struct {
+	name string
+	tags []string
+	fn   func(t T)
+}
+
+// Suite returns an empty suite of subtests of t
+func (tc *TestContext) Suite(t T) *Suite {
+	return &Suite{tc: tc, t: t}
+}
+
+// NewSuite returns an empty suite of subtests of t within the default context
+func NewSuite(t T) *Suite {
+	return Default().Suite(t)
+}
+
+// Add adds a test named name under the tag to the suite
+func (s *Suite) Add(tag, name string, fn func(t T)) {
+	s.AddTags([]string{tag}, name, fn)
+}
+
+// AddTags adds a test named name under the tags to the suite
+func (s *Suite) AddTags(tags []string, name string, fn func(t T)) {
+	s.tests = append(s.tests, suiteTest{name: name, tags: append([]string(nil), tags...), fn: fn})
+}
+
+// Shuffle shuffles the order that tests under the tags run in among
+// tests of the same priority, or the order of every test if no tags are
+// given, to detect tests depending on each other. Tests are shuffled
+// with the seed held by GOTAG_SEED, or a random seed otherwise, which
+// is logged by the suite's test so that a failing order can be
+// reproduced
+//
+//	GOTAG_SEED=1718 go test -run TestCheckout -v
+func (s *Suite) Shuffle(tags ...string) {
+	s.shuffle = true
+	s.shuffled = append(s.shuffled, tags...)
+}
+
+// Run runs the tests of the suite as subtests in priority order, tests
+// of the same priority in the order they were added unless shuffled.
+// Each subtest also gets the tags of the suite's test, like Run.
+// Returns whether every subtest succeeded
+func (s *Suite) Run() bool {
+	var parent []string
+	if n, ok := s.t.(interface{ Name() string }); ok {
+		s.tc.mu.RLock()
+		parent = s.tc.active[n.Name()]
+		s.tc.mu.RUnlock()
+	}
+	tests := append([]suiteTest(nil), s.tests...)
+	s.tc.mu.RLock()
+	priorities := make([]int, len(tests))
+	for i, test := range tests {
+		priorities[i] = s.tc.priorityOf(test.tags)
+	}
+	s.tc.mu.RUnlock()
+	order := make([]int, len(tests))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return priorities[order[i]] > priorities[order[j]]
+	})
+	if s.shuffle {
+		seed := time.Now().UnixNano()
+		if env := os.Getenv(EnvSeed); env != "" {
+			var err error
+			if seed, err = strconv.ParseInt(env, 10, 64); err != nil {
+				s.t.Errorf("gotag: malformed %s '%s': %v", EnvSeed, env, err)
+				return false
+			}
+		}
+		s.t.Logf("gotag: shuffling tests with %s=%d", EnvSeed, seed)
+		s.shuffleOrder(order, priorities, rand.New(rand.NewSource(seed)))
+	}
+
+	ok := true
+	for _, i := range order {
+		test := tests[i]
+		tags := append(append([]string(nil), parent...), test.tags...)
+		ok = s.t.Run(test.name, func(st *testing.T) {
+			s.tc.run(tags, st, test.fn)
+		}) && ok
+	}
+	return ok
+}
+
+// permutes the positions of the shuffled tests within each priority
+func (s *Suite) shuffleOrder(order, priorities []int, rng *rand.Rand) {
+	s.tc.mu.RLock()
+	defer s.tc.mu.RUnlock()
+	want := make(map[string]bool, len(s.shuffled))
+	for _, tag := range s.shuffled {
+		want[s.tc.canonical(tag)] = true
+	}
+	shuffled := func(test suiteTest) bool {
+		if len(want) == 0 {
+			return true
+		}
+		for _, tag := range test.tags {
+			if want[s.tc.canonical(tag)] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for start := 0; start < len(order); {
+		end := start
+		for end < len(order) && priorities[order[end]] == priorities[order[start]] {
+			end++
+		}
+		var positions []int
+		for pos := start; pos < end; pos++ {
+			if shuffled(s.tests[order[pos]]) {
+				positions = append(positions, pos)
+			}
+		}
+		rng.Shuffle(len(positions), func(i, j int) {
+			order[positions[i]], order[positions[j]] = order[positions[j]], order[positions[i]]
+		})
+		start = end
+	}
+}
@@ -0,0 +1,79 @@
+package gotag
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSuitePriority(t *testing.T) {
+	tc := New()
+	tc.Priority("unit", 10)
+	tc.Priority("e2e", -10)
+	tc.Skip("manual")
+
+	var order []string
+	record := func(name string) func(t T) {
+		return func(t T) { order = append(order, name) }
+	}
+	s := tc.Suite(t)
+	s.Add("e2e", "browser", record("browser"))
+	s.Add("db", "queries", record("queries"))
+	s.AddTags([]string{"db", "unit"}, "totals", record("totals"))
+	s.Add("manual", "upload", record("upload"))
+	s.Add("integration", "payments", record("payments"))
+	if !s.Run() {
+		t.Error("Expected the suite to succeed")
+	}
+	if got := strings.Join(order, ","); got != "totals,queries,payments,browser" {
+		t.Errorf("Expected tests in priority order, got %s", got)
+	}
+}
+
+func TestSuiteInheritsTags(t *testing.T) {
+	tc := New()
+	tc.RunOnly("unit")
+	ran := false
+	tc.Test("unit", t, func(t T) {
+		s := tc.Suite(t)
+		s.Add("db", "inherited", func(t T) { ran = true })
+		s.Run()
+	})
+	if !ran {
+		t.Error("Expected the suite's tests to inherit the parent's tags")
+	}
+}
+
+func TestSuiteShuffle(t *testing.T) {
+	tc := New()
+	tc.Priority("unit", 1)
+	run := func(seed string) string {
+		t.Setenv(EnvSeed, seed)
+		var order []string
+		s := tc.Suite(t)
+		s.Add("unit", "first", func(t T) { order = append(order, "first") })
+		for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+			name := name
+			s.Add("integration", name, func(t T) { order = append(order, name) })
+		}
+		s.Add("db", "last", func(t T) { order = append(order, "last") })
+		s.Shuffle("integration")
+		s.Run()
+		return strings.Join(order, ",")
+	}
+
+	order := run("42")
+	if order != run("42") {
+		t.Error("Expected the same seed to give the same order")
+	}
+	if !strings.HasPrefix(order, "first,") || !strings.HasSuffix(order, ",last") {
+		t.Errorf("Expected only the integration tests to be shuffled, got %s", order)
+	}
+	shuffled := false
+	for seed := 0; seed < 5 && !shuffled; seed++ {
+		shuffled = run(strconv.Itoa(seed)) != "first,a,b,c,d,e,f,last"
+	}
+	if !shuffled {
+		t.Error("Expected the integration tests to be shuffled")
+	}
+}
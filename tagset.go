@@ -0,0 +1,213 @@
+package gotag
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// tagSet holds registered tags keyed by their canonical form, which
+// is computed once on registration and used for every lookup. The
+// original form is kept so reports show the tag the user wrote
+type tagSet struct {
+	originals map[string]string
+
+	// canonical forms in the order they were registered
+	order []string
+
+	index fuzzyIndex
+
+	// compiled glob and regex tags, see compilePattern
+	patterns []tagPattern
+
+	// whether canonical forms are lower case, in which
+	// case patterns are compiled to ignore case
+	foldCase bool
+}
+
+func newTagSet() *tagSet {
+	return &tagSet{originals: make(map[string]string)}
+}
+
+// adds a tag under its canonical form. The first original
+// form registered for a canonical tag is kept
+func (s *tagSet) add(tag, canonical string) {
+	if _, ok := s.originals[canonical]; !ok {
+		s.originals[canonical] = tag
+		s.order = append(s.order, canonical)
+		s.index.insert(canonical, len(s.order)-1)
+		if s.foldCase {
+			// lower casing a regular expression could change its
+			// meaning, e.g. \D, so the original is compiled instead
+			if re := mustCompilePattern(tag); re != nil {
+				s.patterns = append(s.patterns, tagPattern{canonical, regexp.MustCompile("(?i)" + re.String())})
+			}
+		} else if re := mustCompilePattern(canonical); re != nil {
+			s.patterns = append(s.patterns, tagPattern{canonical, re})
+		}
+	}
+}
+
+// reports whether the canonical tag is in the set
+func (s *tagSet) has(canonical string) bool {
+	_, ok := s.originals[canonical]
+	return ok
+}
+
+// reports whether the canonical tag or any of its namespaces is in
+// the set or the tag matches a pattern in the set. Tags are namespaced
+// by dots, so a set holding integration covers integration.db and
+// integration.db.postgres. Each namespace is looked up from the root
+// down without allocating
+func (s *tagSet) covers(canonical string) bool {
+	for i := 0; i < len(canonical); i++ {
+		if canonical[i] == '.' && s.has(canonical[:i]) {
+			return true
+		}
+	}
+	return s.has(canonical) || s.matchPattern(canonical)
+}
+
+// returns the canonical tag of the set covering the canonical tag,
+// preferring the tag itself, then its namespaces from the closest up,
+// then the first pattern matching it
+func (s *tagSet) covering(canonical string) (string, bool) {
+	if s.has(canonical) {
+		return canonical, true
+	}
+	for i := len(canonical) - 1; i > 0; i-- {
+		if canonical[i] == '.' && s.has(canonical[:i]) {
+			return canonical[:i], true
+		}
+	}
+	return s.matchingPattern(canonical)
+}
+
+// returns the number of tags in the set
+func (s *tagSet) len() int {
+	return len(s.order)
+}
+
+// returns the original form of the canonical tag
+func (s *tagSet) original(canonical string) string {
+	return s.originals[canonical]
+}
+
+// returns the original forms of the tags in the set, sorted
+// unless insertion order is requested
+func (s *tagSet) tags(insertionOrder bool) []string {
+	tags := make([]string, len(s.order))
+	for i, canonical := range s.order {
+		tags[i] = s.originals[canonical]
+	}
+	if !insertionOrder {
+		sort.Strings(tags)
+	}
+	return tags
+}
+
+// canonical returns the form of a tag used for lookups. Tags are
+// normalized once when registered and once per lookup so lookups
+// never re-normalize the registered sets. Must be called with at
+// least a read lock held
+func (tc *TestContext) canonical(tag string) string {
+	if tc.foldCase {
+		return strings.ToLower(tag)
+	}
+	return tag
+}
+
+// CaseInsensitive sets whether tags are matched regardless of case,
+// both exactly and fuzzily. Tags that have already been registered
+// are normalized again, so CaseInsensitive can be called at any time
+// before tests start
+func (tc *TestContext) CaseInsensitive(enabled bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.foldCase == enabled {
+		return
+	}
+	tc.foldCase = enabled
+
+	for _, set := range []**tagSet{&tc.skip, &tc.runOnly, &tc.mustRun, &tc.shortSkip, &tc.quarantine} {
+		old := *set
+		renormalized := newTagSet()
+		renormalized.foldCase = enabled
+		for _, key := range old.order {
+			tag := old.originals[key]
+			renormalized.add(tag, tc.canonical(tag))
+		}
+		*set = renormalized
+	}
+	groups := make(map[string][]string, len(tc.groups))
+	for key, members := range tc.groups {
+		groups[tc.canonical(key)] = append(groups[tc.canonical(key)], members...)
+	}
+	tc.groups = groups
+	prerequisites := make(map[string]*prerequisite, len(tc.prerequisites))
+	for _, req := range tc.prerequisites {
+		prerequisites[tc.canonical(req.name)] = req
+	}
+	tc.prerequisites = prerequisites
+	hooks := make(map[string]*tagHooks, len(tc.hooks))
+	for _, h := range tc.hooks {
+		hooks[tc.canonical(h.tag)] = h
+	}
+	tc.hooks = hooks
+	retries := make(map[string]retryPolicy, len(tc.retries))
+	for key, policy := range tc.retries {
+		retries[tc.canonical(key)] = policy
+	}
+	tc.retries = retries
+	timeouts := make(map[string]time.Duration, len(tc.timeouts))
+	for key, d := range tc.timeouts {
+		timeouts[tc.canonical(key)] = d
+	}
+	tc.timeouts = timeouts
+	priorities := make(map[string]int, len(tc.priorities))
+	for key, p := range tc.priorities {
+		priorities[tc.canonical(key)] = p
+	}
+	tc.priorities = priorities
+	hostSkips := make(map[string]hostSkip, len(tc.hostSkips))
+	for _, skip := range tc.hostSkips {
+		hostSkips[tc.canonical(skip.tag)] = skip
+	}
+	tc.hostSkips = hostSkips
+	dependencies := make(map[string][]string, len(tc.dependencies))
+	for key, required := range tc.dependencies {
+		dependencies[tc.canonical(key)] = append(dependencies[tc.canonical(key)], required...)
+	}
+	tc.dependencies = dependencies
+	exclusions := make(map[string][]*exclusiveLock, len(tc.exclusions))
+	for _, locks := range tc.exclusions {
+		for _, l := range locks {
+			for _, tag := range l.tags {
+				key := tc.canonical(tag)
+				if !containsLock(exclusions[key], l) {
+					exclusions[key] = append(exclusions[key], l)
+				}
+			}
+		}
+	}
+	tc.exclusions = exclusions
+	registry := make(map[string]Tag, len(tc.tagRegistry))
+	for _, tag := range tc.tagRegistry {
+		registry[tc.canonical(tag.Name)] = tag
+	}
+	tc.tagRegistry = registry
+	entries := make(map[string]SkipEntry, len(tc.skipEntries))
+	for _, skip := range tc.skipEntries {
+		entries[tc.canonical(skip.Tag)] = skip
+	}
+	tc.skipEntries = entries
+	tc.messages = make(map[skipKey][]interface{})
+	tc.invalidate()
+}
+
+// CaseInsensitive sets whether tags are matched
+// regardless of case within the default context
+func CaseInsensitive(enabled bool) {
+	Default().CaseInsensitive(enabled)
+}
@@ -0,0 +1,95 @@
+package gotag
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTagSet(t *testing.T) {
+	s := newTagSet()
+	s.add("TagA", "taga")
+	s.add("TAGA", "taga")
+	if !s.has("taga") {
+		t.Error("Expected canonical tag to be in set")
+	}
+	if s.has("TagA") {
+		t.Error("Expected lookups to use the canonical form")
+	}
+	tags := s.tags(false)
+	if len(tags) != 1 || tags[0] != "TagA" {
+		t.Errorf("Expected original form 'TagA', got %v", tags)
+	}
+}
+
+func TestTagsOrder(t *testing.T) {
+	tc := New()
+	tc.Skip("tagC", "tagA", "tagB")
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "tagA,tagB,tagC" {
+		t.Errorf("Expected sorted tags, got %s", tags)
+	}
+
+	tc.InsertionOrder = true
+	if tags := strings.Join(tc.SkippedTags(), ","); tags != "tagC,tagA,tagB" {
+		t.Errorf("Expected tags in insertion order, got %s", tags)
+	}
+}
+
+func TestNamespacedTags(t *testing.T) {
+	tc := New()
+	tc.Skip("integration")
+
+	mock := &mockT{}
+	tc.Test("integration", mock, func(t T) {})
+	tc.Test("integration.db.postgres", mock, func(t T) {})
+	tc.Test("integrations", mock, func(t T) {})
+	tc.Test("unit.integration", mock, func(t T) {})
+	if mock.skipped != 2 {
+		t.Errorf("Expected 2 tests skipped, got %d", mock.skipped)
+	}
+
+	tc = New()
+	tc.RunOnly("integration.db")
+	mock = &mockT{}
+	tc.Test("integration.db", mock, func(t T) {})
+	tc.Test("integration.db.postgres", mock, func(t T) {})
+	tc.Test("integration.cache", mock, func(t T) {})
+	tc.Test("integration", mock, func(t T) {})
+	if mock.skipped != 2 {
+		t.Errorf("Expected 2 tests skipped, got %d", mock.skipped)
+	}
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	tc := New()
+	tc.Skip("Integration", "DB.*", "/^Slow$/")
+	tc.Retry("Network", 3, 0)
+
+	mock := &mockT{}
+	tc.Test("integration", mock, func(t T) {})
+	if mock.skipped != 0 {
+		t.Fatal("Expected matching to be case sensitive by default")
+	}
+
+	tc.CaseInsensitive(true)
+	for _, tag := range []string{"integration", "INTEGRATION", "db.Postgres", "slow", "integration.api"} {
+		if skip, _ := tc.WouldSkip(tag); !skip {
+			t.Errorf("Expected %s to be skipped regardless of case", tag)
+		}
+	}
+	if tags := tc.SkippedTags(); tags[len(tags)-1] != "Integration" {
+		t.Errorf("Expected original forms to be kept, got %v", tags)
+	}
+	if tc.retryPolicyFor([]string{"network"}).attempts != 3 {
+		t.Error("Expected retries to be matched regardless of case")
+	}
+
+	tc.Fuzzy = true
+	if skip, _ := tc.WouldSkip("INTEGRATON"); !skip {
+		t.Error("Expected fuzzy matching to ignore case")
+	}
+
+	tc.CaseInsensitive(false)
+	if skip, _ := tc.WouldSkip("SLOW"); skip {
+		t.Error("Expected matching to be case sensitive again")
+	}
+}
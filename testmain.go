@@ -0,0 +1,276 @@
+package gotag
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	// EnvReport is the environment variable holding the path
+	// that Main writes its JSON report to
+	EnvReport = "GOTAG_REPORT"
+
+	// EnvWebhook is the environment variable holding the URL
+	// that Main notifies of skipped must run tags
+	EnvWebhook = "GOTAG_WEBHOOK"
+)
+
+// Main is a one-stop entry point for TestMain. It applies the given
+// options to the default context and configures it like Init, runs
+// the suite, then tears down the tags that ran and writes the summaries
+// and reports that are set. Returns the exit code to pass to os.Exit:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(gotag.Main(m))
+//	}
+func Main(m *testing.M, opts ...Option) int {
+	return Default().main(m, opts...)
+}
+
+// Init configures the default context from the config files discovered
+// by Load, the GOTAG_* environment variables and the -gotag.* flags, in
+// increasing order of precedence. The flags are registered on
+// flag.CommandLine and the command line is parsed if it has not been
+// already, so Init should be called from TestMain before m.Run:
+//
+//	func TestMain(m *testing.M) {
+//		if err := gotag.Init(); err != nil {
+//			log.Fatal(err)
+//		}
+//		os.Exit(m.Run())
+//	}
+//
+// Main calls Init itself. Returns an error if a config file or
+// environment variable is malformed
+func Init() error {
+	return Default().init()
+}
+
+func (tc *TestContext) init() error {
+	if err := tc.loadDefault(); err != nil {
+		return err
+	}
+	if !flag.Parsed() {
+		tc.RegisterFlags(flag.CommandLine)
+		flag.Parse()
+	}
+	return nil
+}
+
+// runner matches testing.M. This allows Main to be testable
+type runner interface {
+	Run() int
+}
+
+func (tc *TestContext) main(m runner, opts ...Option) int {
+	for _, opt := range opts {
+		if err := opt(tc); err != nil {
+			fmt.Fprintf(os.Stderr, "gotag: %v\n", err)
+			return 2
+		}
+	}
+	// the environment overrides the webhook of config files
+	if tc.webhook == "" {
+		tc.webhook = os.Getenv(EnvWebhook)
+	}
+	if err := tc.init(); err != nil {
+		fmt.Fprintf(os.Stderr, "gotag: %v\n", err)
+		return 2
+	}
+	if err := tc.checkExpired(os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "gotag: %v\n", err)
+		return 1
+	}
+	if tc.report == "" {
+		tc.report = os.Getenv(EnvReport)
+	}
+	if tc.junit == "" {
+		tc.junit = os.Getenv(EnvJUnit)
+	}
+	if tc.metrics == "" {
+		tc.metrics = os.Getenv(EnvMetrics)
+	}
+	if tc.decisionLogPath == "" {
+		tc.decisionLogPath = os.Getenv(EnvDecisionLog)
+	}
+	var closeLog func() error
+	if tc.decisionLogPath != "" {
+		var err error
+		if closeLog, err = tc.openDecisionLog(tc.decisionLogPath); err != nil {
+			fmt.Fprintf(os.Stderr, "gotag: could not open decision log: %v\n", err)
+		}
+	}
+
+	tc.recording = true
+	if err := tc.skipRegistered(flag.CommandLine); err != nil {
+		fmt.Fprintf(os.Stderr, "gotag: %v\n", err)
+	}
+	code := m.Run()
+	tc.recording = false
+	tc.Teardown()
+	if closeLog != nil {
+		if err := closeLog(); err != nil {
+			fmt.Fprintf(os.Stderr, "gotag: could not write decision log: %v\n", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+
+	tc.summarize(os.Stdout)
+	tc.summarizeQuarantine(os.Stdout)
+	if tc.Verbose {
+		fmt.Fprint(os.Stdout, tc.Report())
+	}
+	if tc.report != "" {
+		if err := tc.writeReport(tc.report); err != nil {
+			fmt.Fprintf(os.Stderr, "gotag: could not write report: %v\n", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+	if tc.junit != "" {
+		if err := tc.writeJUnit(tc.junit); err != nil {
+			fmt.Fprintf(os.Stderr, "gotag: could not write JUnit report: %v\n", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+	tc.mu.RLock()
+	reporters := tc.reporters
+	tc.mu.RUnlock()
+	if len(reporters) > 0 {
+		report := tc.Report()
+		for _, r := range reporters {
+			r.OnRunEnd(report)
+		}
+	}
+	if tc.metrics != "" {
+		if err := tc.writeMetrics(tc.metrics); err != nil {
+			fmt.Fprintf(os.Stderr, "gotag: could not write metrics: %v\n", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+	violations := tc.violations()
+	for _, v := range violations {
+		fmt.Fprintf(os.Stderr, "gotag: must run tag '%s' was skipped by %s (%s)\n", v.Tag, v.Test, v.Reason)
+	}
+	if tc.webhook != "" {
+		if payload := tc.webhookPayload(violations); payload != nil {
+			if err := notify(tc.webhook, payload); err != nil {
+				fmt.Fprintf(os.Stderr, "gotag: could not notify webhook: %v\n", err)
+			}
+		}
+	}
+	return code
+}
+
+// decision records whether a test was run or skipped
+// and the outcome of tests that were run
+type decision struct {
+	Tag      string        `json:"tag"`
+	Test     string        `json:"test,omitempty"`
+	Skipped  bool          `json:"skipped"`
+	Reason   string        `json:"reason,omitempty"`
+	Failed   bool          `json:"failed,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// records one decision per tag so that reports count
+// a test with several tags under each of them
+func (tc *TestContext) record(tags []string, s skippable, reason skipReason, start time.Time) {
+	d := decision{
+		Skipped: reason.skipped(),
+		Reason:  reason.String(),
+	}
+	if n, ok := s.(interface{ Name() string }); ok {
+		d.Test = n.Name()
+	}
+	if !d.Skipped {
+		d.Duration = time.Since(start)
+		if f, ok := s.(interface{ Failed() bool }); ok {
+			d.Failed = f.Failed()
+		}
+	}
+
+	tc.mu.Lock()
+	for _, tag := range tags {
+		d.Tag = tag
+		d.Reason = tc.describeReason(tag, reason)
+		tc.decisions = append(tc.decisions, d)
+	}
+	tc.mu.Unlock()
+}
+
+// prints the number of skipped tests, followed by the number per tag
+// under which a test with several tags counts for each of them
+func (tc *TestContext) summarize(w io.Writer) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	var skipped []decision
+	counts := make(map[string]int)
+	for _, d := range tc.decisions {
+		if d.Skipped {
+			counts[d.Tag]++
+			skipped = append(skipped, d)
+		}
+	}
+	if len(skipped) == 0 {
+		return
+	}
+
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = fmt.Sprintf("%s (%d)", tag, counts[tag])
+	}
+	fmt.Fprintf(w, "gotag: skipped %d test(s): %s\n", countTests(skipped), strings.Join(parts, ", "))
+}
+
+// returns the number of distinct tests the decisions were made for,
+// as decisions are recorded per tag. Decisions of tests without a
+// name are counted individually
+func countTests(decisions []decision) int {
+	n := 0
+	seen := make(map[string]bool)
+	for _, d := range decisions {
+		if d.Test == "" || !seen[d.Test] {
+			seen[d.Test] = true
+			n++
+		}
+	}
+	return n
+}
+
+// writes every recorded decision and the per tag
+// totals of Report to the given path as JSON
+func (tc *TestContext) writeReport(path string) error {
+	report := tc.Report()
+	tc.mu.Lock()
+	bytes, err := json.MarshalIndent(struct {
+		Decisions []decision  `json:"decisions"`
+		Tags      []TagReport `json:"tags"`
+	}{tc.decisions, report.Tags}, "", "  ")
+	tc.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bytes, 0644)
+}
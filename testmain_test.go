@@ -0,0 +1,86 @@
+package gotag
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMainReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	tc := New()
+	tc.Skip("tagA")
+
+	m := runnerFunc(func() int {
+		mock := &mockT{}
+		tc.Test("tagA", mock, func(t T) {})
+		tc.Test("tagB", mock, func(t T) {})
+		return 0
+	})
+	if code := tc.main(m, WithReportFile(path)); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Decisions []decision  `json:"decisions"`
+		Tags      []TagReport `json:"tags"`
+	}
+	if err := json.Unmarshal(bytes, &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Decisions) != 2 {
+		t.Fatalf("Expected 2 decisions, got %d", len(report.Decisions))
+	}
+	if !report.Decisions[0].Skipped || report.Decisions[1].Skipped {
+		t.Error("Wrong decisions recorded")
+	}
+	if len(report.Tags) != 2 || report.Tags[0].Skipped != 1 || report.Tags[1].Run != 1 {
+		t.Errorf("Wrong tag totals %+v", report.Tags)
+	}
+}
+
+func TestMainExitCode(t *testing.T) {
+	tc := New()
+	m := runnerFunc(func() int { return 1 })
+	if code := tc.main(m); code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tc := New()
+	tc.Skip("tagA", "tagB")
+	tc.recording = true
+
+	mock := &mockT{}
+	tc.Test("tagB", mock, func(t T) {})
+	tc.Test("tagA", mock, func(t T) {})
+	tc.Test("tagA", mock, func(t T) {})
+
+	var buf bytes.Buffer
+	tc.summarize(&buf)
+	if !strings.Contains(buf.String(), "skipped 3 test(s): tagA (2), tagB (1)") {
+		t.Errorf("Unexpected summary '%s'", buf.String())
+	}
+
+	// a test with several tags is a single test
+	tc.TestTags([]string{"tagA", "tagB"}, &namedT{name: "TestBoth"}, func(t T) {})
+	buf.Reset()
+	tc.summarize(&buf)
+	if !strings.Contains(buf.String(), "skipped 4 test(s): tagA (3), tagB (2)") {
+		t.Errorf("Unexpected summary '%s'", buf.String())
+	}
+}
+
+type runnerFunc func() int
+
+func (f runnerFunc) Run() int {
+	return f()
+}
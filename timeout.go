@@ -0,0 +1,156 @@
+package gotag
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Timeout sets a time budget for tests under the tag, or a tag in its
+// namespace. The test function runs in its own goroutine and the test
+// fails if it has not returned within the budget. The goroutine cannot
+// be stopped and keeps running until the test function returns.
+// A test with several tags gets the smallest budget. Budgets that
+// are not positive disable the timeout for the tag. Tests run with
+// TestFor have no budget since their body is not passed a T gotag
+// can wrap
+//
+//	tc.Timeout(gotag.Integration, 2*time.Minute)
+func (tc *TestContext) Timeout(tag string, d time.Duration) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.timeouts[tc.canonical(tag)] = d
+}
+
+// Timeout sets a time budget for tests under
+// the tag within the default context
+func Timeout(tag string, d time.Duration) {
+	Default().Timeout(tag, d)
+}
+
+// returns the smallest budget of the tags and their namespaces, the most
+// specific namespace taking precedence, and the tag it was set for.
+// Must be called with at least a read lock held
+func (tc *TestContext) timeoutFor(tags []string) (time.Duration, string) {
+	var budget time.Duration
+	var budgetTag string
+	if len(tc.timeouts) == 0 {
+		return budget, budgetTag
+	}
+	for _, tag := range tags {
+		key := tc.canonical(tag)
+		var found time.Duration
+		for i := 0; i <= len(key); i++ {
+			if i < len(key) && key[i] != '.' {
+				continue
+			}
+			if d, ok := tc.timeouts[key[:i]]; ok {
+				found = d
+			}
+		}
+		if found > 0 && (budget == 0 || found < budget) {
+			budget, budgetTag = found, tag
+		}
+	}
+	return budget, budgetTag
+}
+
+// bodyT is the T of a test function running in its own goroutine.
+// Failures and skips are recorded, to be reported by the goroutine
+// running the test, and FailNow and SkipNow stop the test function
+type bodyT struct {
+	*captureT
+
+	skipMu  sync.Mutex
+	skipped bool
+}
+
+func (b *bodyT) Skip(args ...interface{}) {
+	b.Log(args...)
+	b.SkipNow()
+}
+
+func (b *bodyT) Skipf(format string, args ...interface{}) {
+	b.Logf(format, args...)
+	b.SkipNow()
+}
+
+func (b *bodyT) SkipNow() {
+	b.skipMu.Lock()
+	b.skipped = true
+	b.skipMu.Unlock()
+	runtime.Goexit()
+}
+
+func (b *bodyT) Skipped() bool {
+	b.skipMu.Lock()
+	defer b.skipMu.Unlock()
+	return b.skipped
+}
+
+// wraps fn so that it runs in its own goroutine and the
+// test fails if fn has not returned within the budget
+func timeoutFn(d time.Duration, tag string, fn interface{}) func(s skippable) {
+	return func(s skippable) {
+		msg := fmt.Sprintf("gotag: test exceeded the %s timeout of tag '%s'", d, tag)
+		t, ok := s.(T)
+		if !ok {
+			if !runWithin(d, func() { call(fn, s) }) {
+				panic(msg)
+			}
+			return
+		}
+
+		body := &bodyT{captureT: &captureT{T: t, stop: runtime.Goexit, quiet: true}}
+		var returned bool
+		if !runWithin(d, func() { call(fn, body); returned = true }) {
+			// the test function may keep running
+			body.detach()
+			t.Fatal(msg)
+			return
+		}
+		if messages := body.failures(); len(messages) > 0 {
+			for _, msg := range messages {
+				t.Error(msg)
+			}
+		} else if body.Failed() {
+			t.Fail()
+		}
+		if !returned {
+			// the test function called SkipNow, FailNow or Fatal. Stopped
+			// through t so that wrappers such as retries see it
+			if body.Skipped() {
+				t.SkipNow()
+				return
+			}
+			t.FailNow()
+		}
+	}
+}
+
+// runs fn in its own goroutine, returning whether it returned within
+// d. Panics of fn are raised again in the calling goroutine
+func runWithin(d time.Duration, fn func()) bool {
+	done := make(chan struct{})
+	var panicked interface{}
+	go func() {
+		defer close(done)
+		defer func() {
+			panicked = recover()
+		}()
+		fn()
+	}()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		return false
+	}
+	if panicked != nil {
+		panic(panicked)
+	}
+	return true
+}
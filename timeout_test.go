@@ -0,0 +1,151 @@
+package gotag
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+type timeoutT struct {
+	mockT
+	mu     sync.Mutex
+	fatals []string
+	logs   int
+	errors []interface{}
+	failed bool
+}
+
+func (t *timeoutT) Error(args ...interface{}) { t.errors = append(t.errors, args...) }
+
+func (t *timeoutT) Fatal(args ...interface{}) { t.fatals = append(t.fatals, args[0].(string)) }
+func (t *timeoutT) Fail()                     { t.failed = true }
+
+func (t *timeoutT) Log(...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.logs++
+}
+
+func TestTimeout(t *testing.T) {
+	tc := New()
+	tc.Timeout("slow", 10*time.Millisecond)
+
+	mock := &timeoutT{}
+	release := make(chan struct{})
+	tc.Test("slow.db", mock, func(t T) { <-release })
+	close(release)
+	want := "gotag: test exceeded the 10ms timeout of tag 'slow.db'"
+	if len(mock.fatals) != 1 || mock.fatals[0] != want {
+		t.Errorf("Expected %q, got %v", want, mock.fatals)
+	}
+
+	mock = &timeoutT{}
+	tc.Test("slow", mock, func(t T) {})
+	tc.Test("unit", mock, func(t T) { time.Sleep(20 * time.Millisecond) })
+	if len(mock.fatals) != 0 {
+		t.Errorf("Expected no timeouts, got %v", mock.fatals)
+	}
+}
+
+func TestTimeoutFailures(t *testing.T) {
+	tc := New()
+	tc.Timeout("slow", time.Minute)
+	tc.Timeout("slower", 10*time.Millisecond)
+
+	mock := &timeoutT{}
+	tc.Test("slow", mock, func(t T) { t.Errorf("boom") })
+	if len(mock.errors) != 1 || mock.errors[0] != "boom" || mock.logs != 0 {
+		t.Errorf("Expected the failure to reach the test once, got errors=%v logs=%d", mock.errors, mock.logs)
+	}
+
+	mock = &timeoutT{}
+	release, finished := make(chan struct{}), make(chan struct{})
+	tc.Test("slower", mock, func(t T) {
+		defer close(finished)
+		<-release
+		t.Log("late")
+		t.Error("late")
+	})
+	close(release)
+	<-finished
+	if len(mock.fatals) != 1 || mock.failed || len(mock.errors) != 0 || mock.logs != 0 {
+		t.Errorf("Expected nothing to reach the test after it timed out, got %+v", mock)
+	}
+}
+
+func TestTimeoutRetry(t *testing.T) {
+	tc := New()
+	tc.Retry("net", 3, 0)
+	tc.Timeout("net", time.Second)
+
+	attempts := 0
+	tc.Test("net", &mockT{}, func(t T) {
+		attempts++
+		t.Fatal("unreachable")
+	})
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+// stopT stops the goroutine running the test when it is skipped
+type stopT struct {
+	mockT
+}
+
+func (t *stopT) Skip(...interface{}) { t.SkipNow() }
+
+func (t *stopT) SkipNow() {
+	t.skipped++
+	runtime.Goexit()
+}
+
+func TestTimeoutQuarantine(t *testing.T) {
+	tc := New()
+	tc.Quarantine("net")
+	tc.Timeout("net", time.Second)
+
+	mock := &stopT{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tc.Test("net", mock, func(t T) { t.Fatal("unreachable") })
+	}()
+	<-done
+	if mock.skipped != 1 || len(tc.quarantined) != 1 {
+		t.Fatalf("Expected the failure to be quarantined, got %d skip(s) and %v", mock.skipped, tc.quarantined)
+	}
+	if messages := tc.quarantined[0].Messages; len(messages) != 1 || messages[0] != "unreachable" {
+		t.Errorf("Expected the failure message to be recorded, got %v", messages)
+	}
+}
+
+func TestTimeoutSkipNow(t *testing.T) {
+	tc := New()
+	tc.Timeout("slow", time.Minute)
+
+	after := false
+	t.Run("skipped", func(st *testing.T) {
+		tc.Test("slow", st, func(t T) { t.SkipNow() })
+		after = true
+	})
+	if after {
+		t.Error("Expected SkipNow to stop the test")
+	}
+}
+
+func TestTimeoutConfig(t *testing.T) {
+	tc := New()
+	err := tc.Apply(&Config{Timeouts: map[string]string{"slow": "2m", "db": "30s"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, tag := tc.timeoutFor([]string{"slow", "db.postgres"}); d != 30*time.Second || tag != "db.postgres" {
+		t.Errorf("Expected the smallest budget of 30s for db.postgres, got %s for %s", d, tag)
+	}
+
+	if err := tc.Apply(&Config{Timeouts: map[string]string{"slow": "soon"}}); err == nil {
+		t.Error("Expected an error for a malformed timeout")
+	}
+}
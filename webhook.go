@@ -0,0 +1,112 @@
+package gotag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// returns the recorded decisions that skipped a must run tag
+func (tc *TestContext) violations() []decision {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	var violations []decision
+	for _, d := range tc.decisions {
+		if d.Skipped && tc.mustRun.covers(tc.canonical(d.Tag)) {
+			violations = append(violations, d)
+		}
+	}
+	return violations
+}
+
+// webhookPayload is posted to the configured webhook. The text
+// field makes the payload compatible with Slack incoming webhooks
+type webhookPayload struct {
+	Text        string              `json:"text"`
+	Violations  []decision          `json:"violations"`
+	Quarantined []quarantineFailure `json:"quarantined,omitempty"`
+	Expired     []SkipEntry         `json:"expired,omitempty"`
+	// Skipped holds the number of skipped tests per tag
+	Skipped map[string]int `json:"skipped,omitempty"`
+}
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// returns the payload summarizing the run for the webhook, or nil if
+// no must run tag was skipped, no quarantined test failed and no skip
+// expired, so that teams are only nudged when skip lists rot
+func (tc *TestContext) webhookPayload(violations []decision) *webhookPayload {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	if len(violations) == 0 && len(tc.quarantined) == 0 && len(tc.expired) == 0 {
+		return nil
+	}
+	payload := &webhookPayload{
+		Violations:  violations,
+		Quarantined: append([]quarantineFailure(nil), tc.quarantined...),
+		Expired:     append([]SkipEntry(nil), tc.expired...),
+	}
+	for _, d := range tc.decisions {
+		if d.Skipped {
+			if payload.Skipped == nil {
+				payload.Skipped = make(map[string]int)
+			}
+			payload.Skipped[d.Tag]++
+		}
+	}
+
+	var lines []string
+	if len(violations) > 0 {
+		lines = append(lines, fmt.Sprintf("gotag: %d test(s) under must run tags were skipped", countTests(violations)))
+		for _, v := range violations {
+			lines = append(lines, fmt.Sprintf("• %s skipped %s (%s)", v.Tag, v.Test, v.Reason))
+		}
+	}
+	if len(payload.Quarantined) > 0 {
+		lines = append(lines, fmt.Sprintf("gotag: %d quarantined test(s) failed", len(payload.Quarantined)))
+		for _, f := range payload.Quarantined {
+			lines = append(lines, fmt.Sprintf("• %s failed %s", f.Tag, f.Test))
+		}
+	}
+	if len(payload.Expired) > 0 {
+		lines = append(lines, fmt.Sprintf("gotag: %d skip(s) have expired and can be removed", len(payload.Expired)))
+		for _, skip := range payload.Expired {
+			lines = append(lines, fmt.Sprintf("• %s %s", skip.Tag, skip))
+		}
+	}
+	if len(payload.Skipped) > 0 {
+		tags := make([]string, 0, len(payload.Skipped))
+		for tag := range payload.Skipped {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		for i, tag := range tags {
+			tags[i] = fmt.Sprintf("%s (%d)", tag, payload.Skipped[tag])
+		}
+		lines = append(lines, "skipped tags: "+strings.Join(tags, ", "))
+	}
+	payload.Text = strings.Join(lines, "\n")
+	return payload
+}
+
+// posts the payload to the webhook url
+func notify(url string, payload *webhookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Webhook responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package gotag
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMustRunWebhook(t *testing.T) {
+	var payload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	tc := New()
+	tc.Skip("tagA")
+	tc.MustRun("tagA")
+	m := runnerFunc(func() int {
+		mock := &mockT{}
+		tc.Test("tagA", mock, func(t T) {})
+		tc.Test("tagB", mock, func(t T) {})
+		return 0
+	})
+	if code := tc.main(m, WithWebhook(server.URL)); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	if len(payload.Violations) != 1 || payload.Violations[0].Tag != "tagA" {
+		t.Errorf("Unexpected violations %v", payload.Violations)
+	}
+	if payload.Text == "" {
+		t.Error("Expected a text summary for Slack")
+	}
+}
+
+func TestWebhookCountsTests(t *testing.T) {
+	tc := New()
+	tc.Skip("tagA", "tagB")
+	tc.MustRun("tagA", "tagB")
+	tc.recording = true
+	tc.TestTags([]string{"tagA", "tagB"}, &namedT{name: "TestBoth"}, func(t T) {})
+
+	payload := tc.webhookPayload(tc.violations())
+	if payload == nil || len(payload.Violations) != 2 {
+		t.Fatalf("Expected a violation per tag, got %+v", payload)
+	}
+	if !strings.Contains(payload.Text, "gotag: 1 test(s) under must run tags were skipped") {
+		t.Errorf("Expected the test to be counted once, got %q", payload.Text)
+	}
+}
+
+func TestRottingSkipsWebhook(t *testing.T) {
+	t.Setenv(EnvWebhook, "")
+	var payload webhookPayload
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	tc := New()
+	if err := tc.Apply(&Config{
+		Webhook:     server.URL,
+		Quarantine:  []string{"flaky"},
+		SkipEntries: []SkipEntry{{Tag: "s3", Reason: "outage", Until: "2020-01-01"}},
+		Skip:        []string{"manual"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	m := runnerFunc(func() int {
+		tc.Test("flaky", &mockT{}, func(t T) { t.Error("boom") })
+		tc.Test("manual", &mockT{}, func(t T) {})
+		return 0
+	})
+	if code := tc.main(m); code != 0 {
+		t.Fatalf("Expected exit code 0, got %d", code)
+	}
+	if posts != 1 {
+		t.Fatalf("Expected one post, got %d", posts)
+	}
+	if len(payload.Quarantined) != 1 || payload.Quarantined[0].Tag != "flaky" {
+		t.Errorf("Unexpected quarantine failures %+v", payload.Quarantined)
+	}
+	if len(payload.Expired) != 1 || payload.Expired[0].Tag != "s3" {
+		t.Errorf("Unexpected expired skips %+v", payload.Expired)
+	}
+	if payload.Skipped["manual"] != 1 {
+		t.Errorf("Unexpected skipped tags %v", payload.Skipped)
+	}
+	for _, want := range []string{"1 quarantined test(s) failed", "1 skip(s) have expired", "skipped tags: manual (1)"} {
+		if !strings.Contains(payload.Text, want) {
+			t.Errorf("Expected text to contain %q, got %q", want, payload.Text)
+		}
+	}
+
+	// nothing to nudge about
+	tc = New()
+	tc.Skip("manual")
+	m = runnerFunc(func() int {
+		tc.Test("manual", &mockT{}, func(t T) {})
+		return 0
+	})
+	tc.main(m, WithWebhook(server.URL))
+	if posts != 1 {
+		t.Errorf("Expected no post for a healthy run, got %d", posts)
+	}
+}
+
+func TestConfigWebhook(t *testing.T) {
+	tc := New()
+	if err := tc.Apply(&Config{Webhook: "ftp://example.com"}); err == nil {
+		t.Error("Expected an error for a non http webhook")
+	}
+	tc = New()
+	if err := WithWebhook("https://code.example.com")(tc); err != nil {
+		t.Fatal(err)
+	}
+	if err := tc.Apply(&Config{Webhook: "https://config.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if tc.webhook != "https://code.example.com" {
+		t.Errorf("Expected the webhook given in code to take precedence, got %s", tc.webhook)
+	}
+	merged := MergeConfigs(&Config{Webhook: "https://root.example.com"}, &Config{Webhook: "https://pkg.example.com"})
+	if merged.Webhook != "https://pkg.example.com" {
+		t.Errorf("Expected the nearer webhook, got %s", merged.Webhook)
+	}
+}